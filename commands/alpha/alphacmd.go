@@ -19,7 +19,9 @@ import (
 
 	"github.com/GoogleContainerTools/kpt/commands/alpha/license"
 	"github.com/GoogleContainerTools/kpt/commands/alpha/live"
+	"github.com/GoogleContainerTools/kpt/commands/alpha/repo"
 	"github.com/GoogleContainerTools/kpt/commands/alpha/rollouts"
+	"github.com/GoogleContainerTools/kpt/commands/alpha/rpkg"
 	"github.com/GoogleContainerTools/kpt/commands/alpha/wasm"
 
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/alphadocs"
@@ -50,6 +52,8 @@ func GetCommand(ctx context.Context, _, version string) *cobra.Command {
 		live.GetCommand(ctx, "", version),
 		license.NewCommand(ctx, version),
 		rollouts.NewCommand(ctx, version),
+		repo.NewCommand(ctx, version),
+		rpkg.NewCommand(ctx, version),
 	)
 
 	return alpha
@@ -0,0 +1,187 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/repodocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/options"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/cmd/get"
+)
+
+const (
+	command = "cmdrepoget"
+)
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx:        ctx,
+		getFlags:   options.Get{ConfigFlags: rcg},
+		printFlags: get.NewGetPrintFlags(),
+	}
+	c := &cobra.Command{
+		Use:     "get [REPOSITORY_NAME]",
+		Aliases: []string{"ls", "list"},
+		Short:   repodocs.GetShort,
+		Long:    repodocs.GetShort + "\n" + repodocs.GetLong,
+		Example: repodocs.GetExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+
+		ValidArgsFunction: porch.Completer{Flags: rcg}.RepositoryArgs(1),
+	}
+	r.Command = c
+
+	// Create flags
+	r.getFlags.AddFlags(c)
+	r.printFlags.AddFlags(c)
+	return r
+}
+
+type runner struct {
+	ctx     context.Context
+	Command *cobra.Command
+
+	// Flags
+	getFlags     options.Get
+	printFlags   *get.PrintFlags
+	requestTable bool
+}
+
+func (r *runner) preRunE(cmd *cobra.Command, _ []string) error {
+	// Only request the server-side table when the output is printed as a
+	// table; other output formats need the full objects.
+	outputOption := cmd.Flags().Lookup("output").Value.String()
+	if strings.Contains(outputOption, "custom-columns") || outputOption == "yaml" || strings.Contains(outputOption, "jsonpath") {
+		r.requestTable = false
+	} else {
+		r.requestTable = true
+	}
+	return nil
+}
+
+func (r *runner) runE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	var objs []runtime.Object
+	b, err := r.getFlags.ResourceBuilder()
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	if r.requestTable {
+		scheme := runtime.NewScheme()
+		// Accept PartialObjectMetadata and Table
+		if err := metav1.AddMetaToScheme(scheme); err != nil {
+			return errors.E(op, fmt.Errorf("error building runtime.Scheme: %w", err))
+		}
+		b = b.WithScheme(scheme, schema.GroupVersion{Version: "v1"})
+	} else {
+		// We want to print the server version, not whatever version we happen to have compiled in
+		b = b.Unstructured()
+	}
+
+	if len(args) > 0 {
+		b = b.ResourceNames("repository", args...)
+	} else {
+		b = b.ResourceTypes("repositories").SelectAllParam(true)
+	}
+
+	b = b.ContinueOnError().
+		Latest().
+		Flatten()
+
+	if r.requestTable {
+		b = b.TransformRequests(func(req *rest.Request) {
+			req.SetHeader("Accept", strings.Join([]string{
+				"application/json;as=Table;g=meta.k8s.io;v=v1",
+				"application/json",
+			}, ","))
+		})
+	}
+
+	res := b.Do()
+	if err := res.Err(); err != nil {
+		return errors.E(op, err)
+	}
+
+	infos, err := res.Infos()
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	// Decode json objects in tables (likely PartialObjectMetadata)
+	for _, i := range infos {
+		if table, ok := i.Object.(*metav1.Table); ok {
+			for i := range table.Rows {
+				row := &table.Rows[i]
+				if row.Object.Object == nil && row.Object.Raw != nil {
+					u := &unstructured.Unstructured{}
+					if err := u.UnmarshalJSON(row.Object.Raw); err != nil {
+						klog.Warningf("error parsing raw object: %v", err)
+					}
+					row.Object.Object = u
+				}
+			}
+		}
+	}
+
+	for _, i := range infos {
+		switch obj := i.Object.(type) {
+		case *unstructured.Unstructured:
+			objs = append(objs, obj)
+		case *metav1.Table:
+			objs = append(objs, obj)
+		default:
+			return errors.E(op, fmt.Sprintf("Unrecognized response %T", obj))
+		}
+	}
+
+	printer, err := r.printFlags.ToPrinter()
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	w := printers.GetNewTabWriter(cmd.OutOrStdout())
+	for _, obj := range objs {
+		if err := printer.PrintObj(obj, w); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return errors.E(op, err)
+	}
+
+	return nil
+}
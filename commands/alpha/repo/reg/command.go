@@ -0,0 +1,232 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/repodocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/spf13/cobra"
+	coreapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdreporeg"
+)
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:     "reg REPOSITORY",
+		Aliases: []string{"register"},
+		Short:   repodocs.RegShort,
+		Long:    repodocs.RegShort + "\n" + repodocs.RegLong,
+		Example: repodocs.RegExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+	}
+	r.Command = c
+
+	c.Flags().StringVar(&r.directory, "directory", "/", "Directory within the repository where to look for packages.")
+	c.Flags().StringVar(&r.branch, "branch", "main", "Branch in the repository where finalized packages are committed.")
+	c.Flags().BoolVar(&r.createBranch, "create-branch", false, "Create the package branch if it doesn't already exist.")
+	c.Flags().StringVar(&r.name, "name", "", "Name of the package repository. If unspecified, will use the name portion (last segment) of the repository URL.")
+	c.Flags().StringVar(&r.description, "description", "", "Brief description of the package repository.")
+	c.Flags().BoolVar(&r.deployment, "deployment", false, "Repository is a deployment repository; packages in a deployment repository are considered deployment-ready.")
+	c.Flags().StringVar(&r.content, "content", string(configapi.RepositoryContentPackage),
+		fmt.Sprintf("Content stored in the repository; one of: %s, %s.", configapi.RepositoryContentPackage, configapi.RepositoryContentFunction))
+	c.Flags().StringVar(&r.username, "repo-basic-username", "", "Username for repository authentication using basic auth.")
+	c.Flags().StringVar(&r.password, "repo-basic-password", "", "Password for repository authentication using basic auth.")
+	c.Flags().BoolVar(&r.credentialHelper, "repo-credential-helper", false, "Obtain basic auth credentials for the repository from the configured git credential helper.")
+
+	return r
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+
+	// Flags
+	directory        string
+	branch           string
+	createBranch     bool
+	description      string
+	name             string
+	deployment       bool
+	content          string
+	username         string
+	password         string
+	credentialHelper bool
+}
+
+func (r *runner) preRunE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".preRunE"
+	client, err := porch.CreateClientWithFlags(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+	return nil
+}
+
+func (r *runner) runE(_ *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	if len(args) == 0 {
+		return errors.E(op, "repository is required positional argument")
+	}
+
+	repository := args[0]
+
+	var content configapi.RepositoryContent
+	switch r.content {
+	case string(configapi.RepositoryContentPackage), string(configapi.RepositoryContentFunction):
+		content = configapi.RepositoryContent(r.content)
+	default:
+		return errors.E(op, fmt.Errorf("invalid content %q; must be one of: %s, %s",
+			r.content, configapi.RepositoryContentPackage, configapi.RepositoryContentFunction))
+	}
+
+	var git *configapi.GitRepository
+	var oci *configapi.OciRepository
+	var rt configapi.RepositoryType
+
+	if strings.HasPrefix(repository, "oci://") {
+		rt = configapi.RepositoryTypeOCI
+		oci = &configapi.OciRepository{
+			Registry: repository[6:],
+		}
+	} else {
+		rt = configapi.RepositoryTypeGit
+		git = &configapi.GitRepository{
+			Repo:         repository,
+			Branch:       r.branch,
+			CreateBranch: r.createBranch,
+			Directory:    r.directory,
+		}
+	}
+	if r.name == "" {
+		r.name = porch.LastSegment(repository)
+	}
+
+	secret, err := r.buildAuthSecret(repository)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if secret != nil {
+		if err := r.client.Create(r.ctx, secret); err != nil {
+			return errors.E(op, err)
+		}
+
+		if git != nil {
+			git.SecretRef.Name = secret.Name
+		}
+		if oci != nil {
+			oci.SecretRef.Name = secret.Name
+		}
+	}
+
+	repo := &configapi.Repository{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Repository",
+			APIVersion: configapi.GroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.name,
+			Namespace: *r.cfg.Namespace,
+		},
+		Spec: configapi.RepositorySpec{
+			Description: r.description,
+			Type:        rt,
+			Content:     content,
+			Deployment:  r.deployment,
+			Git:         git,
+			Oci:         oci,
+		},
+	}
+	if err := r.client.Create(r.ctx, repo); err != nil {
+		if secret != nil {
+			// Don't leave the credentials behind without a repository
+			// referencing them.
+			if derr := r.client.Delete(r.ctx, secret); derr != nil {
+				return errors.E(op, fmt.Errorf("%w; failed to delete secret %q: %v", err, secret.Name, derr))
+			}
+		}
+		return errors.E(op, err)
+	}
+
+	return nil
+}
+
+// buildAuthSecret returns the Secret holding the credentials porch uses to
+// access the repository, or nil if no authentication was requested. Porch
+// only supports basic auth, so that is the only kind of Secret created.
+func (r *runner) buildAuthSecret(repository string) (*coreapi.Secret, error) {
+	basicAuth := r.username != "" || r.password != ""
+	if basicAuth && r.credentialHelper {
+		return nil, fmt.Errorf("--repo-credential-helper cannot be used with --repo-basic-username or --repo-basic-password")
+	}
+
+	secret := &coreapi.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: coreapi.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-auth", r.name),
+			Namespace: *r.cfg.Namespace,
+		},
+	}
+
+	switch {
+	case r.credentialHelper:
+		username, password, err := gitCredentialFill(r.ctx, repository)
+		if err != nil {
+			return nil, err
+		}
+		secret.Type = coreapi.SecretTypeBasicAuth
+		secret.Data = map[string][]byte{
+			coreapi.BasicAuthUsernameKey: []byte(username),
+			coreapi.BasicAuthPasswordKey: []byte(password),
+		}
+	case basicAuth:
+		secret.Type = coreapi.SecretTypeBasicAuth
+		secret.Data = map[string][]byte{
+			coreapi.BasicAuthUsernameKey: []byte(r.username),
+			coreapi.BasicAuthPasswordKey: []byte(r.password),
+		}
+	default:
+		return nil, nil
+	}
+	return secret, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reg
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	coreapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{configapi.AddToScheme, coreapi.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("error building scheme: %v", err)
+		}
+	}
+	return scheme
+}
+
+func TestCmd(t *testing.T) {
+	ns := "ns"
+	testCases := map[string]struct {
+		args       []string
+		setup      func(r *runner)
+		wantErr    bool
+		wantName   string
+		wantSpec   configapi.RepositorySpec
+		wantSecret *coreapi.Secret
+	}{
+		"git repository": {
+			args:     []string{"https://github.com/platkrm/test-blueprints.git"},
+			wantName: "test-blueprints",
+			wantSpec: configapi.RepositorySpec{
+				Type:    configapi.RepositoryTypeGit,
+				Content: configapi.RepositoryContentPackage,
+				Git: &configapi.GitRepository{
+					Repo:      "https://github.com/platkrm/test-blueprints.git",
+					Branch:    "main",
+					Directory: "/",
+				},
+			},
+		},
+		"oci function repository": {
+			args: []string{"oci://us-docker.pkg.dev/kpt-fn/functions"},
+			setup: func(r *runner) {
+				r.content = string(configapi.RepositoryContentFunction)
+			},
+			wantName: "functions",
+			wantSpec: configapi.RepositorySpec{
+				Type:    configapi.RepositoryTypeOCI,
+				Content: configapi.RepositoryContentFunction,
+				Oci: &configapi.OciRepository{
+					Registry: "us-docker.pkg.dev/kpt-fn/functions",
+				},
+			},
+		},
+		"deployment repository with basic auth": {
+			args: []string{"https://github.com/platkrm/deployments.git"},
+			setup: func(r *runner) {
+				r.name = "prod"
+				r.deployment = true
+				r.username = "user"
+				r.password = "secret"
+			},
+			wantName: "prod",
+			wantSpec: configapi.RepositorySpec{
+				Type:       configapi.RepositoryTypeGit,
+				Content:    configapi.RepositoryContentPackage,
+				Deployment: true,
+				Git: &configapi.GitRepository{
+					Repo:      "https://github.com/platkrm/deployments.git",
+					Branch:    "main",
+					Directory: "/",
+					SecretRef: configapi.SecretRef{Name: "prod-auth"},
+				},
+			},
+			wantSecret: &coreapi.Secret{
+				Type: coreapi.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					"username": []byte("user"),
+					"password": []byte("secret"),
+				},
+			},
+		},
+		"conflicting auth flags": {
+			args: []string{"https://github.com/platkrm/deployments.git"},
+			setup: func(r *runner) {
+				r.username = "user"
+				r.credentialHelper = true
+			},
+			wantErr: true,
+		},
+		"invalid content": {
+			args: []string{"https://github.com/platkrm/deployments.git"},
+			setup: func(r *runner) {
+				r.content = "Blueprints"
+			},
+			wantErr: true,
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(createScheme(t)).Build()
+			r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+			r.client = c
+			if tc.setup != nil {
+				tc.setup(r)
+			}
+
+			err := r.runE(r.Command, tc.args)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var repo configapi.Repository
+			if err := c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: tc.wantName}, &repo); err != nil {
+				t.Fatalf("failed to get repository: %v", err)
+			}
+			assert.Equal(t, tc.wantSpec, repo.Spec)
+
+			var secret coreapi.Secret
+			err = c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: tc.wantName + "-auth"}, &secret)
+			if tc.wantSecret == nil {
+				assert.True(t, apierrors.IsNotFound(err), "expected no auth secret, got %v", err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.wantSecret.Type, secret.Type)
+				assert.Equal(t, tc.wantSecret.Data, secret.Data)
+			}
+		})
+	}
+}
+
+func TestCmd_RepositoryExists(t *testing.T) {
+	ns := "ns"
+	existing := &configapi.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: ns},
+	}
+	c := fake.NewClientBuilder().WithScheme(createScheme(t)).WithObjects(existing).Build()
+	r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+	r.client = c
+	r.name = "prod"
+	r.username = "user"
+	r.password = "secret"
+
+	err := r.runE(r.Command, []string{"https://github.com/platkrm/deployments.git"})
+	if assert.Error(t, err) {
+		assert.True(t, apierrors.IsAlreadyExists(err), "expected already exists error, got %v", err)
+	}
+
+	// the secret created for the repository must not be left behind.
+	var secret coreapi.Secret
+	err = c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "prod-auth"}, &secret)
+	assert.True(t, apierrors.IsNotFound(err), "expected no auth secret, got %v", err)
+}
+
+func TestGitCredentialFill(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	home := t.TempDir()
+	gitconfig := "[credential]\n\thelper = \"!f() { test \\\"$1\\\" = get && echo username=porch && echo password=token; }; f\"\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(gitconfig), 0600))
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	username, password, err := gitCredentialFill(context.Background(), "https://github.com/platkrm/private.git")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "porch", username)
+		assert.Equal(t, "token", password)
+	}
+
+	_, _, err = gitCredentialFill(context.Background(), "git@github.com:platkrm/private.git")
+	assert.Error(t, err)
+}
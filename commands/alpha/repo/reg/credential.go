@@ -0,0 +1,77 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// gitCredentialFill asks the git credential helpers configured for the
+// current user for the credentials of the repository, using
+// `git credential fill`. It never prompts for credentials on the terminal.
+func gitCredentialFill(ctx context.Context, repository string) (string, string, error) {
+	u, err := url.Parse(repository)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("--repo-credential-helper requires an http(s) repository URL, got %q", repository)
+	}
+
+	var input bytes.Buffer
+	fmt.Fprintf(&input, "protocol=%s\n", u.Scheme)
+	fmt.Fprintf(&input, "host=%s\n", u.Host)
+	if p := strings.TrimPrefix(u.Path, "/"); p != "" {
+		fmt.Fprintf(&input, "path=%s\n", p)
+	}
+	input.WriteString("\n")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = &input
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if err := cmd.Run(); err != nil {
+		return "", "", fmt.Errorf("failed to get credentials for %s from git credential helper: %w: %s",
+			u.Host, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var username, password string
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "username":
+			username = value
+		case "password":
+			password = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+	if username == "" || password == "" {
+		return "", "", fmt.Errorf("git credential helper returned no credentials for %s", u.Host)
+	}
+	return username, password, nil
+}
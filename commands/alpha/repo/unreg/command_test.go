@@ -0,0 +1,98 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unreg
+
+import (
+	"context"
+	"testing"
+
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	coreapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{configapi.AddToScheme, coreapi.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("error building scheme: %v", err)
+		}
+	}
+	return scheme
+}
+
+func TestCmd(t *testing.T) {
+	ns := "ns"
+	testCases := map[string]struct {
+		keepSecret bool
+		wantSecret bool
+	}{
+		"delete auth secret": {},
+		"keep auth secret": {
+			keepSecret: true,
+			wantSecret: true,
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(createScheme(t)).
+				WithObjects(
+					&configapi.Repository{
+						ObjectMeta: metav1.ObjectMeta{Name: "blueprints", Namespace: ns},
+						Spec: configapi.RepositorySpec{
+							Type: configapi.RepositoryTypeGit,
+							Git: &configapi.GitRepository{
+								Repo:      "https://github.com/platkrm/blueprints.git",
+								SecretRef: configapi.SecretRef{Name: "blueprints-auth"},
+							},
+						},
+					},
+					&coreapi.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: "blueprints-auth", Namespace: ns},
+					},
+				).
+				Build()
+
+			r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+			r.client = c
+			r.keepSecret = tc.keepSecret
+
+			assert.NoError(t, r.runE(r.Command, []string{"blueprints"}))
+
+			err := c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "blueprints"}, &configapi.Repository{})
+			assert.True(t, apierrors.IsNotFound(err), "expected repository to be deleted, got %v", err)
+
+			err = c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "blueprints-auth"}, &coreapi.Secret{})
+			if tc.wantSecret {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, apierrors.IsNotFound(err), "expected secret to be deleted, got %v", err)
+			}
+		})
+	}
+
+	r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+	r.client = fake.NewClientBuilder().WithScheme(createScheme(t)).Build()
+	assert.Error(t, r.runE(r.Command, []string{"missing"}))
+}
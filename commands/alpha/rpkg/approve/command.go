@@ -0,0 +1,142 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approve
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/rpkgdocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdrpkgapprove"
+)
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+
+	c := &cobra.Command{
+		Use:        "approve [PACKAGE ...] [flags]",
+		Short:      rpkgdocs.ApproveShort,
+		Long:       rpkgdocs.ApproveShort + "\n" + rpkgdocs.ApproveLong,
+		Example:    rpkgdocs.ApproveExamples,
+		SuggestFor: []string{},
+		PreRunE:    r.preRunE,
+		RunE:       r.runE,
+		Hidden:     porch.HidePorchCommands,
+
+		ValidArgsFunction: porch.Completer{Flags: rcg}.PackageRevisionArgs(0, porchapi.PackageRevisionLifecycleProposed),
+	}
+	r.Command = c
+
+	c.Flags().StringVarP(&r.message, "message", "m", "", "Message describing the approval, recorded on the package revision.")
+	r.selector.AddFlags(c, rcg)
+
+	return r
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  rest.Interface
+	Command *cobra.Command
+	// porchClient lists the package revisions matching the selector.
+	porchClient client.Reader
+
+	// Flags
+	message  string
+	selector porch.Selector
+}
+
+func (r *runner) preRunE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".preRunE"
+
+	client, err := porch.CreateRESTClient(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+
+	if !r.selector.IsEmpty() {
+		porchClient, err := porch.CreateClientWithFlags(r.cfg)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		r.porchClient = porchClient
+	}
+	return nil
+}
+
+func (r *runner) runE(_ *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+	var messages []string
+
+	namespace := *r.cfg.Namespace
+
+	names := args
+	if !r.selector.IsEmpty() {
+		if len(args) > 0 {
+			return errors.E(op, "PACKAGE arguments can't be combined with --selector, --repository or --name")
+		}
+		var err error
+		names, err = r.selector.Select(r.ctx, r.porchClient, namespace, porchapi.PackageRevisionLifecycleProposed)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		if len(names) == 0 {
+			fmt.Fprintln(r.Command.ErrOrStderr(), "no proposed package revisions matched")
+			return nil
+		}
+	}
+
+	var result porch.BulkResult
+	for _, name := range names {
+		if err := porch.UpdatePackageRevisionApproval(r.ctx, r.client, client.ObjectKey{
+			Namespace: namespace,
+			Name:      name,
+		}, porchapi.PackageRevisionLifecyclePublished, r.message); err != nil {
+			messages = append(messages, err.Error())
+			result.Failed = append(result.Failed, name)
+			fmt.Fprintf(r.Command.ErrOrStderr(), "%s failed (%s)\n", name, err)
+		} else {
+			result.Succeeded = append(result.Succeeded, name)
+			fmt.Fprintf(r.Command.ErrOrStderr(), "%s approved\n", name)
+		}
+	}
+	if !r.selector.IsEmpty() {
+		fmt.Fprintln(r.Command.ErrOrStderr(), result.Summary("approved"))
+	}
+
+	if len(messages) > 0 {
+		return errors.E(op, fmt.Errorf("errors:\n  %s", strings.Join(messages, "\n  ")))
+	}
+	return nil
+}
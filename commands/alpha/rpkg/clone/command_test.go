@@ -0,0 +1,178 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clone
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func packageRevision(ns, name, repo, pkg, revision string, lifecycle porchapi.PackageRevisionLifecycle) client.Object {
+	return &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: repo,
+			PackageName:    pkg,
+			Revision:       revision,
+			Lifecycle:      lifecycle,
+		},
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	ns := "ns"
+	objs := []client.Object{
+		packageRevision(ns, "blueprints-basens-v1", "blueprints", "basens", "v1", porchapi.PackageRevisionLifecyclePublished),
+		packageRevision(ns, "blueprints-basens-v2", "blueprints", "basens", "v2", porchapi.PackageRevisionLifecyclePublished),
+		packageRevision(ns, "blueprints-empty-v1", "blueprints", "empty", "v1", porchapi.PackageRevisionLifecyclePublished),
+		packageRevision(ns, "blueprints-basens-draft", "blueprints", "basens", "", porchapi.PackageRevisionLifecycleDraft),
+		packageRevision(ns, "deployments-existing-v1", "deployments", "existing", "v1", porchapi.PackageRevisionLifecyclePublished),
+	}
+
+	testCases := map[string]struct {
+		args     []string
+		search   string
+		stdin    string
+		wantErr  string
+		wantSpec porchapi.PackageCloneTaskSpec
+		wantOut  string
+	}{
+		"git source with directory and ref": {
+			args: []string{"https://github.com/platkrm/test-blueprints.git/basens@basens/v1", "target"},
+			wantSpec: porchapi.PackageCloneTaskSpec{
+				Strategy: porchapi.ResourceMerge,
+				Upstream: porchapi.UpstreamPackage{
+					Type: porchapi.RepositoryTypeGit,
+					Git: &porchapi.GitPackage{
+						Repo:      "https://github.com/platkrm/test-blueprints.git",
+						Ref:       "basens/v1",
+						Directory: "/basens",
+					},
+				},
+			},
+		},
+		"oci source": {
+			args: []string{"oci://us-docker.pkg.dev/blueprints/basens", "target"},
+			wantSpec: porchapi.PackageCloneTaskSpec{
+				Strategy: porchapi.ResourceMerge,
+				Upstream: porchapi.UpstreamPackage{
+					Type: porchapi.RepositoryTypeOCI,
+					Oci:  &porchapi.OciPackage{Image: "oci://us-docker.pkg.dev/blueprints/basens"},
+				},
+			},
+		},
+		"package revision source": {
+			args: []string{"blueprints-basens-v1", "target"},
+			wantSpec: porchapi.PackageCloneTaskSpec{
+				Strategy: porchapi.ResourceMerge,
+				Upstream: porchapi.UpstreamPackage{
+					UpstreamRef: &porchapi.PackageRevisionRef{Name: "blueprints-basens-v1"},
+				},
+			},
+		},
+		"interactive selection": {
+			args:  []string{"target"},
+			stdin: "3\n",
+			wantSpec: porchapi.PackageCloneTaskSpec{
+				Strategy: porchapi.ResourceMerge,
+				Upstream: porchapi.UpstreamPackage{
+					UpstreamRef: &porchapi.PackageRevisionRef{Name: "blueprints-empty-v1"},
+				},
+			},
+			wantOut: "1)  blueprints   basens    v1        blueprints-basens-v1",
+		},
+		"interactive selection with search": {
+			args:   []string{"target"},
+			search: "basens",
+			stdin:  "2\n",
+			wantSpec: porchapi.PackageCloneTaskSpec{
+				Strategy: porchapi.ResourceMerge,
+				Upstream: porchapi.UpstreamPackage{
+					UpstreamRef: &porchapi.PackageRevisionRef{Name: "blueprints-basens-v2"},
+				},
+			},
+			wantOut: "Enter a number [1-2]: ",
+		},
+		"search without matches": {
+			args:    []string{"target"},
+			search:  "missing",
+			wantErr: `no published package revisions match "missing"`,
+		},
+		"invalid selection": {
+			args:    []string{"target"},
+			stdin:   "9\n",
+			wantErr: `invalid selection "9"`,
+		},
+		"search with source": {
+			args:    []string{"blueprints-basens-v1", "target"},
+			search:  "basens",
+			wantErr: "SOURCE_PACKAGE cannot be provided together with --search",
+		},
+		"package exists": {
+			args:    []string{"blueprints-basens-v1", "existing"},
+			wantErr: "`clone` cannot create a new revision for package \"existing\" that already exists in repo \"deployments\"",
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(createScheme(t)).WithObjects(objs...).Build()
+			r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+			r.client = c
+			r.repository = "deployments"
+			r.search = tc.search
+
+			out := &bytes.Buffer{}
+			r.Command.SetIn(strings.NewReader(tc.stdin))
+			r.Command.SetErr(out)
+
+			err := r.parseArgs(r.Command, tc.args)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.wantSpec, r.clone)
+			assert.Equal(t, tc.args[len(tc.args)-1], r.target)
+			assert.Contains(t, out.String(), tc.wantOut)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clone
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// selectUpstream lists the published package revisions available in the
+// registered repositories, filtered by the --search flag, and prompts the
+// user to pick the one to clone. It returns the name of the selected
+// package revision.
+func (r *runner) selectUpstream(in io.Reader, out io.Writer) (string, error) {
+	var list porchapi.PackageRevisionList
+	if err := r.client.List(r.ctx, &list, client.InNamespace(*r.cfg.Namespace)); err != nil {
+		return "", err
+	}
+
+	candidates := filterUpstreams(list.Items, r.search)
+	if len(candidates) == 0 {
+		if r.search != "" {
+			return "", fmt.Errorf("no published package revisions match %q", r.search)
+		}
+		return "", fmt.Errorf("no published package revisions found in namespace %q", *r.cfg.Namespace)
+	}
+
+	fmt.Fprintln(out, "Select the upstream package revision to clone:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tREPOSITORY\tPACKAGE\tREVISION\tNAME")
+	for i, pr := range candidates {
+		fmt.Fprintf(w, "%d)\t%s\t%s\t%s\t%s\n", i+1, pr.Spec.RepositoryName, pr.Spec.PackageName, pr.Spec.Revision, pr.Name)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(out, "Enter a number [1-%d]: ", len(candidates))
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", fmt.Errorf("no upstream package revision selected")
+	}
+	i, err := strconv.Atoi(line)
+	if err != nil || i < 1 || i > len(candidates) {
+		return "", fmt.Errorf("invalid selection %q; must be a number between 1 and %d", line, len(candidates))
+	}
+	return candidates[i-1].Name, nil
+}
+
+// filterUpstreams returns the published package revisions whose repository,
+// package name or revision contain the search text, sorted by repository,
+// package name and revision.
+func filterUpstreams(prs []porchapi.PackageRevision, search string) []porchapi.PackageRevision {
+	search = strings.ToLower(search)
+	var candidates []porchapi.PackageRevision
+	for _, pr := range prs {
+		if !porchapi.LifecycleIsPublished(pr.Spec.Lifecycle) {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(pr.Spec.RepositoryName), search) &&
+			!strings.Contains(strings.ToLower(pr.Spec.PackageName), search) &&
+			!strings.Contains(strings.ToLower(pr.Spec.Revision), search) {
+			continue
+		}
+		candidates = append(candidates, pr)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i].Spec, candidates[j].Spec
+		if a.RepositoryName != b.RepositoryName {
+			return a.RepositoryName < b.RepositoryName
+		}
+		if a.PackageName != b.PackageName {
+			return a.PackageName < b.PackageName
+		}
+		return a.Revision < b.Revision
+	})
+	return candidates
+}
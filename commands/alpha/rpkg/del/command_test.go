@@ -0,0 +1,61 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package del
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func TestCmd(t *testing.T) {
+	ns := "ns"
+	c := fake.NewClientBuilder().WithScheme(createScheme(t)).WithObjects(&porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "blueprints-basens-v1", Namespace: ns},
+	}).Build()
+
+	out := &bytes.Buffer{}
+	r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+	r.client = c
+	r.Command.SetOut(out)
+	r.Command.SetErr(out)
+
+	err := r.runE(r.Command, []string{"blueprints-basens-v1", "missing"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "\"missing\" not found")
+	}
+	assert.Contains(t, out.String(), "blueprints-basens-v1 deleted\n")
+	assert.Contains(t, out.String(), "missing failed")
+
+	err = c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "blueprints-basens-v1"}, &porchapi.PackageRevision{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
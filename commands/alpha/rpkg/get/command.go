@@ -0,0 +1,156 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/rpkgdocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdrpkgget"
+)
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:     "get [PACKAGE]",
+		Aliases: []string{"list"},
+		Short:   rpkgdocs.GetShort,
+		Long:    rpkgdocs.GetShort + "\n" + rpkgdocs.GetLong,
+		Example: rpkgdocs.GetExamples,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+
+		ValidArgsFunction: porch.Completer{Flags: rcg}.PackageRevisionArgs(1),
+	}
+	r.Command = c
+
+	completer := porch.Completer{Flags: rcg}
+	c.Flags().StringVar(&r.packageName, "name", "", "Name of the packages to get. Any package whose name contains this value will be included in the results.")
+	c.Flags().StringVar(&r.revision, "revision", "", "Revision of the packages to get. Any package whose revision matches this value will be included in the results.")
+	_ = c.RegisterFlagCompletionFunc("name", completer.PackageNames)
+	_ = c.RegisterFlagCompletionFunc("revision", completer.Revisions)
+
+	return r
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+
+	// Flags
+	packageName string
+	revision    string
+}
+
+func (r *runner) preRunE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".preRunE"
+	client, err := porch.CreateClientWithFlags(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+	return nil
+}
+
+func (r *runner) runE(_ *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	namespace := *r.cfg.Namespace
+	var prs []porchapi.PackageRevision
+	if len(args) > 0 {
+		var pr porchapi.PackageRevision
+		if err := r.client.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: args[0]}, &pr); err != nil {
+			return errors.E(op, err)
+		}
+		prs = append(prs, pr)
+	} else {
+		var list porchapi.PackageRevisionList
+		if err := r.client.List(r.ctx, &list, client.InNamespace(namespace)); err != nil {
+			return errors.E(op, err)
+		}
+		prs = r.filter(list.Items)
+	}
+
+	if err := writePackageRevisions(printer.FromContextOrDie(r.ctx).OutStream(), prs); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// filter returns the package revisions matching the --name and --revision
+// flags, sorted by repository, package name and revision.
+func (r *runner) filter(prs []porchapi.PackageRevision) []porchapi.PackageRevision {
+	var matched []porchapi.PackageRevision
+	for _, pr := range prs {
+		if r.packageName != "" && !strings.Contains(pr.Spec.PackageName, r.packageName) {
+			continue
+		}
+		if r.revision != "" && pr.Spec.Revision != r.revision {
+			continue
+		}
+		matched = append(matched, pr)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i].Spec, matched[j].Spec
+		if a.RepositoryName != b.RepositoryName {
+			return a.RepositoryName < b.RepositoryName
+		}
+		if a.PackageName != b.PackageName {
+			return a.PackageName < b.PackageName
+		}
+		if a.Revision != b.Revision {
+			return a.Revision < b.Revision
+		}
+		return a.WorkspaceName < b.WorkspaceName
+	})
+	return matched
+}
+
+func writePackageRevisions(out io.Writer, prs []porchapi.PackageRevision) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPACKAGE\tWORKSPACENAME\tREVISION\tLATEST\tLIFECYCLE\tREPOSITORY")
+	for _, pr := range prs {
+		latest := pr.Labels[porchapi.LatestPackageRevisionKey] == porchapi.LatestPackageRevisionValue
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%s\t%s\n", pr.Name, pr.Spec.PackageName, pr.Spec.WorkspaceName,
+			pr.Spec.Revision, latest, pr.Spec.Lifecycle, pr.Spec.RepositoryName)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func packageRevision(ns, name, repo, pkg, revision string, lifecycle porchapi.PackageRevisionLifecycle, latest bool) client.Object {
+	pr := &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: repo,
+			PackageName:    pkg,
+			WorkspaceName:  porchapi.WorkspaceName(revision),
+			Revision:       revision,
+			Lifecycle:      lifecycle,
+		},
+	}
+	if latest {
+		pr.Labels = map[string]string{porchapi.LatestPackageRevisionKey: porchapi.LatestPackageRevisionValue}
+	}
+	return pr
+}
+
+func TestCmd(t *testing.T) {
+	ns := "ns"
+	objs := []client.Object{
+		packageRevision(ns, "deployments-app-v1", "deployments", "app", "v1", porchapi.PackageRevisionLifecyclePublished, true),
+		packageRevision(ns, "blueprints-basens-v2", "blueprints", "basens", "v2", porchapi.PackageRevisionLifecyclePublished, true),
+		packageRevision(ns, "blueprints-basens-v1", "blueprints", "basens", "v1", porchapi.PackageRevisionLifecyclePublished, false),
+		packageRevision("other", "blueprints-other-v1", "blueprints", "other", "v1", porchapi.PackageRevisionLifecyclePublished, true),
+	}
+
+	testCases := map[string]struct {
+		args     []string
+		name     string
+		revision string
+		want     string
+		wantErr  bool
+	}{
+		"all": {
+			want: `NAME                  PACKAGE  WORKSPACENAME  REVISION  LATEST  LIFECYCLE  REPOSITORY
+blueprints-basens-v1  basens   v1             v1        false   Published  blueprints
+blueprints-basens-v2  basens   v2             v2        true    Published  blueprints
+deployments-app-v1    app      v1             v1        true    Published  deployments
+`,
+		},
+		"by name and revision": {
+			name:     "base",
+			revision: "v1",
+			want: `NAME                  PACKAGE  WORKSPACENAME  REVISION  LATEST  LIFECYCLE  REPOSITORY
+blueprints-basens-v1  basens   v1             v1        false   Published  blueprints
+`,
+		},
+		"package revision": {
+			args: []string{"deployments-app-v1"},
+			want: `NAME                PACKAGE  WORKSPACENAME  REVISION  LATEST  LIFECYCLE  REPOSITORY
+deployments-app-v1  app      v1             v1        true    Published  deployments
+`,
+		},
+		"missing package revision": {
+			args:    []string{"blueprints-other-v1"},
+			wantErr: true,
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			r := newRunner(printer.WithContext(context.Background(), printer.New(out, out)), &genericclioptions.ConfigFlags{Namespace: &ns})
+			r.client = fake.NewClientBuilder().WithScheme(createScheme(t)).WithObjects(objs...).Build()
+			r.packageName = tc.name
+			r.revision = tc.revision
+
+			err := r.runE(r.Command, tc.args)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, out.String())
+		})
+	}
+}
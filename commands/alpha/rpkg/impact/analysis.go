@@ -0,0 +1,399 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impact
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/util/update"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Status is the outcome of simulating the update of a downstream package
+// revision to the blueprint revision.
+type Status string
+
+const (
+	// StatusUpToDate means the downstream package revision is already based
+	// on the blueprint revision.
+	StatusUpToDate Status = "up-to-date"
+	// StatusClean means the update would merge without losing any change.
+	StatusClean Status = "clean"
+	// StatusConflict means the update would fail, or would lose either a
+	// change made in the downstream package or a change made upstream.
+	StatusConflict Status = "conflict"
+	// StatusError means the update could not be simulated.
+	StatusError Status = "error"
+)
+
+// Result is the impact of the blueprint revision on a downstream package
+// revision.
+type Result struct {
+	PackageRevision string     `json:"packageRevision"`
+	Repository      string     `json:"repository"`
+	Package         string     `json:"package"`
+	Upstream        string     `json:"upstream"`
+	Status          Status     `json:"status"`
+	Conflicts       []Conflict `json:"conflicts,omitempty"`
+	Message         string     `json:"message,omitempty"`
+}
+
+// Conflict is a change that the update can't reconcile.
+type Conflict struct {
+	// File is the path of the file in the package. It is empty if the
+	// update fails as a whole.
+	File string `json:"file,omitempty"`
+	// Resource identifies the resource in the file, e.g. `ConfigMap/example`.
+	// It is empty for files that don't hold KRM resources.
+	Resource string `json:"resource,omitempty"`
+	// Field is the path of the conflicting field, e.g. `data.key`.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (c Conflict) String() string {
+	var parts []string
+	for _, s := range []string{c.File, c.Resource, c.Field} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		return c.Message
+	}
+	return strings.Join(parts, " ") + ": " + c.Message
+}
+
+// upstreamRef returns the name of the package revision the package revision
+// was last cloned from or updated to, or an empty string if it doesn't have
+// an upstream in a registered repository.
+func upstreamRef(pr *porchapi.PackageRevision) string {
+	for i := len(pr.Spec.Tasks) - 1; i >= 0; i-- {
+		task := pr.Spec.Tasks[i]
+		switch {
+		case task.Update != nil && task.Update.Upstream.UpstreamRef != nil:
+			return task.Update.Upstream.UpstreamRef.Name
+		case task.Clone != nil && task.Clone.Upstream.UpstreamRef != nil:
+			return task.Clone.Upstream.UpstreamRef.Name
+		}
+	}
+	return ""
+}
+
+// findDownstream returns the package revisions based on a revision of the
+// package of the blueprint revision, sorted by name. Only the latest
+// published revision and the unpublished revisions of a downstream package
+// are returned, since older revisions are never updated.
+func findDownstream(prs []porchapi.PackageRevision, blueprint *porchapi.PackageRevision) []porchapi.PackageRevision {
+	revisions := map[string]bool{}
+	for _, pr := range prs {
+		if pr.Spec.RepositoryName == blueprint.Spec.RepositoryName && pr.Spec.PackageName == blueprint.Spec.PackageName {
+			revisions[pr.Name] = true
+		}
+	}
+
+	var downstream []porchapi.PackageRevision
+	for _, pr := range prs {
+		if revisions[pr.Name] || !revisions[upstreamRef(&pr)] {
+			continue
+		}
+		if pr.Spec.Lifecycle == porchapi.PackageRevisionLifecyclePublished &&
+			pr.Labels[porchapi.LatestPackageRevisionKey] != porchapi.LatestPackageRevisionValue {
+			continue
+		}
+		downstream = append(downstream, pr)
+	}
+	sort.Slice(downstream, func(i, j int) bool { return downstream[i].Name < downstream[j].Name })
+	return downstream
+}
+
+// simulateUpdate simulates updating the local package resources from the
+// original to the updated upstream resources. It runs the resource-merge
+// update on a copy of the resources and returns the changes the update
+// can't reconcile.
+func simulateUpdate(original, local, updated map[string]string) ([]Conflict, error) {
+	conflicts, err := findConflicts(original, local, updated)
+	if err != nil {
+		return nil, err
+	}
+	if err := dryRunMerge(original, local, updated); err != nil {
+		conflicts = append(conflicts, Conflict{Message: fmt.Sprintf("update failed: %v", err)})
+	}
+	return conflicts, nil
+}
+
+// dryRunMerge merges the resources in a temporary directory.
+func dryRunMerge(original, local, updated map[string]string) error {
+	dir, err := os.MkdirTemp("", "kpt-impact-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	opts := update.Options{
+		RelPackagePath: ".",
+		LocalPath:      filepath.Join(dir, "local"),
+		OriginPath:     filepath.Join(dir, "original"),
+		UpdatedPath:    filepath.Join(dir, "updated"),
+		IsRoot:         true,
+	}
+	for path, resources := range map[string]map[string]string{
+		opts.LocalPath:   local,
+		opts.OriginPath:  original,
+		opts.UpdatedPath: updated,
+	} {
+		if err := writeResources(path, resources); err != nil {
+			return err
+		}
+	}
+	return update.ResourceMergeUpdater{}.Update(opts)
+}
+
+func writeResources(dir string, resources map[string]string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, content := range resources {
+		f := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findConflicts compares the three versions of the package. A file or a
+// resource field conflicts if it was changed both locally and upstream, to
+// different values.
+func findConflicts(original, local, updated map[string]string) ([]Conflict, error) {
+	var files []string
+	var conflicts []Conflict
+	for _, name := range unionKeys(original, local, updated) {
+		if isKRMFile(name) {
+			files = append(files, name)
+			continue
+		}
+		o, oOk := original[name]
+		l, lOk := local[name]
+		u, uOk := updated[name]
+		if changed(o, oOk, l, lOk) && changed(o, oOk, u, uOk) && changed(l, lOk, u, uOk) {
+			conflicts = append(conflicts, Conflict{File: name, Message: "changed both in the package and upstream"})
+		}
+	}
+
+	originalResources, err := parseResources(original, files)
+	if err != nil {
+		return nil, err
+	}
+	localResources, err := parseResources(local, files)
+	if err != nil {
+		return nil, err
+	}
+	updatedResources, err := parseResources(updated, files)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range unionResourceKeys(originalResources, localResources, updatedResources) {
+		oRes, oOk := originalResources[key]
+		lRes, lOk := localResources[key]
+		uRes, uOk := updatedResources[key]
+		o, l, u := oRes.fields, lRes.fields, uRes.fields
+		var conflict Conflict
+		for _, r := range []resource{oRes, lRes, uRes} {
+			if r.file != "" {
+				conflict = Conflict{File: r.file, Resource: r.name}
+			}
+		}
+		switch {
+		case oOk && !lOk && uOk && fieldsChanged(o, u):
+			conflict.Message = "deleted in the package but changed upstream"
+			conflicts = append(conflicts, conflict)
+		case oOk && lOk && !uOk && fieldsChanged(o, l):
+			conflict.Message = "changed in the package but deleted upstream"
+			conflicts = append(conflicts, conflict)
+		case lOk && uOk:
+			for _, field := range unionKeys(o, l, u) {
+				ov, oh := o[field]
+				lv, lh := l[field]
+				uv, uh := u[field]
+				if changed(ov, oh, lv, lh) && changed(ov, oh, uv, uh) && changed(lv, lh, uv, uh) {
+					conflict.Field = field
+					conflict.Message = fmt.Sprintf("%s in the package but %s upstream", describe(lv, lh), describe(uv, uh))
+					conflicts = append(conflicts, conflict)
+				}
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+func changed(a string, aOk bool, b string, bOk bool) bool {
+	return aOk != bOk || a != b
+}
+
+func fieldsChanged(a, b map[string]string) bool {
+	for _, field := range unionKeys(a, b) {
+		av, aOk := a[field]
+		bv, bOk := b[field]
+		if changed(av, aOk, bv, bOk) {
+			return true
+		}
+	}
+	return false
+}
+
+func describe(value string, ok bool) string {
+	if !ok {
+		return "removed"
+	}
+	return fmt.Sprintf("set to %q", value)
+}
+
+func unionKeys(maps ...map[string]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unionResourceKeys(maps ...map[string]resource) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resource is a resource flattened into its fields. Resources are matched
+// by file and name like the resource-merge update does.
+type resource struct {
+	file   string
+	name   string
+	fields map[string]string
+}
+
+func isKRMFile(name string) bool {
+	for _, m := range append(kio.MatchAll, kptfilev1.KptFileName) {
+		if matched, err := filepath.Match(m, filepath.Base(name)); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parseResources parses the resources in the files and flattens them into
+// their fields.
+func parseResources(resources map[string]string, files []string) (map[string]resource, error) {
+	parsed := map[string]resource{}
+	for _, name := range files {
+		content, found := resources[name]
+		if !found {
+			continue
+		}
+		nodes, err := (&kio.ByteReader{
+			Reader:                strings.NewReader(content),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", name, err)
+		}
+		for _, node := range nodes {
+			fields := map[string]string{}
+			flatten("", node.YNode(), fields)
+			r := resource{file: name, name: resourceName(node), fields: fields}
+			parsed[r.file+" "+r.name] = r
+		}
+	}
+	return parsed, nil
+}
+
+// resourceName returns the kind and name of the resource, e.g.
+// `ConfigMap/example`. The name of the Kptfile is omitted, since a
+// downstream package is named differently from its upstream.
+func resourceName(node *yaml.RNode) string {
+	kind := node.GetKind()
+	if kind == kptfilev1.KptFileKind {
+		return kind
+	}
+	if ns := node.GetNamespace(); ns != "" {
+		return kind + "/" + ns + "/" + node.GetName()
+	}
+	return kind + "/" + node.GetName()
+}
+
+// flatten records the scalar fields of the node by their path, e.g.
+// `spec.containers[name=nginx].image`. List elements with a name are
+// identified by their name, other elements by their index.
+func flatten(path string, node *yaml.Node, fields map[string]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			flatten(path, n, fields)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			flatten(key, node.Content[i+1], fields)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			flatten(fmt.Sprintf("%s[%s]", path, elementKey(n, i)), n, fields)
+		}
+	case yaml.AliasNode:
+		flatten(path, node.Alias, fields)
+	default:
+		fields[path] = node.Value
+	}
+}
+
+func elementKey(node *yaml.Node, index int) string {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "name" && node.Content[i+1].Kind == yaml.ScalarNode {
+				return "name=" + node.Content[i+1].Value
+			}
+		}
+	}
+	return fmt.Sprint(index)
+}
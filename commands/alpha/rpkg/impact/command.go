@@ -0,0 +1,217 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/rpkgdocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdrpkgimpact"
+)
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:     "impact PACKAGE [flags]",
+		Short:   rpkgdocs.ImpactShort,
+		Long:    rpkgdocs.ImpactShort + "\n" + rpkgdocs.ImpactLong,
+		Example: rpkgdocs.ImpactExamples,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+
+		ValidArgsFunction: porch.Completer{Flags: rcg}.PackageRevisionArgs(1),
+	}
+	r.Command = c
+
+	c.Flags().StringVarP(&r.output, "output", "o", "",
+		"write the results to stdout in a machine-readable format: "+output.SupportedFormatsLabel())
+	return r
+}
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+
+	// Flags
+	output       string
+	outputFormat output.Format
+}
+
+func (r *runner) preRunE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".preRunE"
+	var err error
+	r.outputFormat, err = output.ParseFormat(r.output)
+	if err != nil {
+		return errors.E(op, errors.InvalidParam, err)
+	}
+
+	client, err := porch.CreateClientWithFlags(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+	return nil
+}
+
+func (r *runner) runE(_ *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	results, err := r.analyze(args[0])
+	if err != nil {
+		err = errors.E(op, err)
+	} else if failed := len(results) - countStatus(results, StatusClean) - countStatus(results, StatusUpToDate); failed > 0 {
+		err = errors.E(op, fmt.Errorf("%d of %d downstream package revisions can't be updated cleanly", failed, len(results)))
+	}
+
+	out := printer.FromContextOrDie(r.ctx).OutStream()
+	if r.outputFormat != output.None {
+		return output.Write(out, r.outputFormat, "alpha rpkg impact", "", results, err)
+	}
+	if results != nil {
+		if werr := writeResults(out, results); werr != nil {
+			return errors.E(op, werr)
+		}
+	}
+	return err
+}
+
+// analyze simulates updating every downstream package revision of the
+// blueprint package to the blueprint revision.
+func (r *runner) analyze(name string) ([]Result, error) {
+	namespace := *r.cfg.Namespace
+
+	var blueprint porchapi.PackageRevision
+	if err := r.client.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: name}, &blueprint); err != nil {
+		return nil, err
+	}
+	var prs porchapi.PackageRevisionList
+	if err := r.client.List(r.ctx, &prs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	updated, err := r.getResources(name)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []Result{}
+	for _, pr := range findDownstream(prs.Items, &blueprint) {
+		result := Result{
+			PackageRevision: pr.Name,
+			Repository:      pr.Spec.RepositoryName,
+			Package:         pr.Spec.PackageName,
+			Upstream:        upstreamRef(&pr),
+			Status:          StatusClean,
+		}
+		if result.Upstream == name {
+			result.Status = StatusUpToDate
+			results = append(results, result)
+			continue
+		}
+		conflicts, err := r.simulate(pr.Name, result.Upstream, updated)
+		switch {
+		case err != nil:
+			result.Status = StatusError
+			result.Message = err.Error()
+		case len(conflicts) > 0:
+			result.Status = StatusConflict
+			result.Conflicts = conflicts
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r *runner) simulate(name, upstream string, updated map[string]string) ([]Conflict, error) {
+	local, err := r.getResources(name)
+	if err != nil {
+		return nil, err
+	}
+	original, err := r.getResources(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get upstream package revision: %w", err)
+	}
+	return simulateUpdate(original, local, updated)
+}
+
+func (r *runner) getResources(name string) (map[string]string, error) {
+	var resources porchapi.PackageRevisionResources
+	if err := r.client.Get(r.ctx, client.ObjectKey{
+		Namespace: *r.cfg.Namespace,
+		Name:      name,
+	}, &resources); err != nil {
+		return nil, err
+	}
+	return resources.Spec.Resources, nil
+}
+
+// writeResults writes the results with their conflicts.
+func writeResults(w io.Writer, results []Result) error {
+	for _, res := range results {
+		if _, err := fmt.Fprintf(w, "[%s] %s (%s/%s): upstream %s\n", strings.ToUpper(string(res.Status)),
+			res.PackageRevision, res.Repository, res.Package, res.Upstream); err != nil {
+			return err
+		}
+		if res.Message != "" {
+			if _, err := fmt.Fprintf(w, "    %s\n", res.Message); err != nil {
+				return err
+			}
+		}
+		for _, c := range res.Conflicts {
+			if _, err := fmt.Fprintf(w, "    %s\n", c); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d downstream package revisions: %d clean, %d conflicts, %d up-to-date, %d errors\n",
+		len(results), countStatus(results, StatusClean), countStatus(results, StatusConflict),
+		countStatus(results, StatusUpToDate), countStatus(results, StatusError))
+	return err
+}
+
+func countStatus(results []Result, status Status) int {
+	n := 0
+	for _, res := range results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,222 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const ns = "ns"
+
+func kptfile(name string) string {
+	return `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: ` + name + `
+`
+}
+
+func deployment(replicas, image string) string {
+	return `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: ` + replicas + `
+  template:
+    spec:
+      containers:
+      - name: app
+        image: ` + image + `
+`
+}
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func packageRevision(name, repo, pkg string, lifecycle porchapi.PackageRevisionLifecycle, upstream string, labels map[string]string) *porchapi.PackageRevision {
+	pr := &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: repo,
+			PackageName:    pkg,
+			Lifecycle:      lifecycle,
+		},
+	}
+	if upstream != "" {
+		pr.Spec.Tasks = []porchapi.Task{{
+			Type: porchapi.TaskTypeClone,
+			Clone: &porchapi.PackageCloneTaskSpec{
+				Upstream: porchapi.UpstreamPackage{UpstreamRef: &porchapi.PackageRevisionRef{Name: upstream}},
+			},
+		}}
+	}
+	return pr
+}
+
+func packageResources(name, pkg string, resources map[string]string) *porchapi.PackageRevisionResources {
+	resources[kptfilev1.KptFileName] = kptfile(pkg)
+	return &porchapi.PackageRevisionResources{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec:       porchapi.PackageRevisionResourcesSpec{Resources: resources},
+	}
+}
+
+func createRunner(t *testing.T, out *bytes.Buffer) *runner {
+	latest := map[string]string{porchapi.LatestPackageRevisionKey: porchapi.LatestPackageRevisionValue}
+	published := porchapi.PackageRevisionLifecyclePublished
+	objects := []client.Object{
+		// The resources of blueprint-v0 are missing.
+		packageRevision("blueprint-v0", "blueprints", "app", published, "", nil),
+		packageRevision("blueprint-v1", "blueprints", "app", published, "", latest),
+		packageRevision("blueprint-v2", "blueprints", "app", porchapi.PackageRevisionLifecycleProposed, "", nil),
+		// The upstream change is to the image, and the local change to the
+		// number of replicas.
+		packageRevision("clean-v1", "deployments", "clean", published, "blueprint-v1", latest),
+		// Both the local and the upstream change are to the image.
+		packageRevision("conflict-v1", "deployments", "conflict", porchapi.PackageRevisionLifecycleDraft, "blueprint-v1", nil),
+		packageRevision("current-v1", "deployments", "current", porchapi.PackageRevisionLifecycleDraft, "blueprint-v2", nil),
+		// Older published revisions are not checked.
+		packageRevision("old-v1", "deployments", "old", published, "blueprint-v1", nil),
+		packageRevision("missing-v1", "deployments", "missing", porchapi.PackageRevisionLifecycleDraft, "blueprint-v0", nil),
+		// Package revisions based on other packages are not checked.
+		packageRevision("other-v1", "deployments", "other", porchapi.PackageRevisionLifecycleDraft, "other-blueprint-v1", nil),
+
+		packageResources("blueprint-v1", "app", map[string]string{"deployment.yaml": deployment("1", "app:v1")}),
+		packageResources("blueprint-v2", "app", map[string]string{"deployment.yaml": deployment("1", "app:v2")}),
+		packageResources("clean-v1", "clean", map[string]string{"deployment.yaml": deployment("3", "app:v1")}),
+		packageResources("conflict-v1", "conflict", map[string]string{"deployment.yaml": deployment("1", "app:v1-patched")}),
+		packageResources("missing-v1", "missing", map[string]string{"deployment.yaml": deployment("1", "app:v1")}),
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(createScheme(t)).
+		WithObjects(objects...).
+		Build()
+
+	namespace := ns
+	return &runner{
+		ctx:    printer.WithContext(context.Background(), printer.New(out, out)),
+		cfg:    &genericclioptions.ConfigFlags{Namespace: &namespace},
+		client: c,
+	}
+}
+
+func TestCmd(t *testing.T) {
+	out := &bytes.Buffer{}
+	r := createRunner(t, out)
+	err := r.runE(nil, []string{"blueprint-v2"})
+	assert.EqualError(t, err, "cmdrpkgimpact.runE: 2 of 4 downstream package revisions can't be updated cleanly")
+
+	expected := `[CLEAN] clean-v1 (deployments/clean): upstream blueprint-v1
+[CONFLICT] conflict-v1 (deployments/conflict): upstream blueprint-v1
+    deployment.yaml Deployment/app spec.template.spec.containers[name=app].image: set to "app:v1-patched" in the package but set to "app:v2" upstream
+[UP-TO-DATE] current-v1 (deployments/current): upstream blueprint-v2
+[ERROR] missing-v1 (deployments/missing): upstream blueprint-v0
+    cannot get upstream package revision: NOT FOUND
+
+4 downstream package revisions: 1 clean, 1 conflicts, 1 up-to-date, 1 errors
+`
+	// The fake client misspells the resource in not found errors.
+	actual := regexp.MustCompile(`: \S+ "blueprint-v0" not found`).ReplaceAllString(out.String(), ": NOT FOUND")
+	assert.Equal(t, expected, actual)
+}
+
+func TestCmd_output(t *testing.T) {
+	out := &bytes.Buffer{}
+	r := createRunner(t, out)
+	r.outputFormat = output.JSON
+	err := r.runE(nil, []string{"blueprint-v2"})
+	assert.Error(t, err)
+
+	var result struct {
+		ExitCode int      `json:"exitCode"`
+		Data     []Result `json:"data"`
+	}
+	if !assert.NoError(t, json.Unmarshal(out.Bytes(), &result)) {
+		return
+	}
+	assert.Equal(t, output.ExitFailure, result.ExitCode)
+	var statuses []Status
+	for _, res := range result.Data {
+		statuses = append(statuses, res.Status)
+	}
+	assert.Equal(t, []Status{StatusClean, StatusConflict, StatusUpToDate, StatusError}, statuses)
+}
+
+func TestFindConflicts(t *testing.T) {
+	testCases := map[string]struct {
+		original, local, updated map[string]string
+		expected                 []Conflict
+	}{
+		"same change": {
+			original: map[string]string{"d.yaml": deployment("1", "app:v1")},
+			local:    map[string]string{"d.yaml": deployment("1", "app:v2")},
+			updated:  map[string]string{"d.yaml": deployment("1", "app:v2")},
+		},
+		"deleted locally, changed upstream": {
+			original: map[string]string{"d.yaml": deployment("1", "app:v1")},
+			local:    map[string]string{},
+			updated:  map[string]string{"d.yaml": deployment("1", "app:v2")},
+			expected: []Conflict{{File: "d.yaml", Resource: "Deployment/app", Message: "deleted in the package but changed upstream"}},
+		},
+		"changed locally, deleted upstream": {
+			original: map[string]string{"d.yaml": deployment("1", "app:v1")},
+			local:    map[string]string{"d.yaml": deployment("2", "app:v1")},
+			updated:  map[string]string{},
+			expected: []Conflict{{File: "d.yaml", Resource: "Deployment/app", Message: "changed in the package but deleted upstream"}},
+		},
+		"non-KRM file": {
+			original: map[string]string{"README.md": "a"},
+			local:    map[string]string{"README.md": "b"},
+			updated:  map[string]string{"README.md": "c"},
+			expected: []Conflict{{File: "README.md", Message: "changed both in the package and upstream"}},
+		},
+		"field removed upstream": {
+			original: map[string]string{"d.yaml": deployment("1", "app:v1")},
+			local:    map[string]string{"d.yaml": deployment("2", "app:v1")},
+			updated:  map[string]string{"d.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n"},
+			expected: []Conflict{
+				{File: "d.yaml", Resource: "Deployment/app", Field: "spec.replicas", Message: `set to "2" in the package but removed upstream`},
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conflicts, err := findConflicts(tc.original, tc.local, tc.updated)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, conflicts)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package init
+
+import (
+	"context"
+	"testing"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func TestParseArgs(t *testing.T) {
+	ns := "ns"
+	existing := &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "blueprints-existing-v1", Namespace: ns},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: "blueprints",
+			PackageName:    "existing",
+		},
+	}
+
+	testCases := map[string]struct {
+		args       []string
+		repository string
+		workspace  string
+		wantErr    string
+	}{
+		"new package": {
+			args:       []string{"foo"},
+			repository: "blueprints",
+			workspace:  "v1",
+		},
+		"missing name": {
+			repository: "blueprints",
+			workspace:  "v1",
+			wantErr:    "PACKAGE_NAME is a required positional argument",
+		},
+		"missing repository": {
+			args:      []string{"foo"},
+			workspace: "v1",
+			wantErr:   "--repository is required",
+		},
+		"missing workspace": {
+			args:       []string{"foo"},
+			repository: "blueprints",
+			wantErr:    "--workspace is required",
+		},
+		"package exists": {
+			args:       []string{"existing"},
+			repository: "blueprints",
+			workspace:  "v1",
+			wantErr:    "`init` cannot create a new revision for package \"existing\" that already exists in repo \"blueprints\"",
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+			r.client = fake.NewClientBuilder().WithScheme(createScheme(t)).WithObjects(existing).Build()
+			r.repository = tc.repository
+			r.workspace = tc.workspace
+			r.Keywords = []string{"test"}
+
+			err := r.parseArgs(tc.args)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			pr := r.packageRevision()
+			assert.Equal(t, porchapi.PackageRevisionSpec{
+				PackageName:    "foo",
+				WorkspaceName:  "v1",
+				RepositoryName: "blueprints",
+				Tasks: []porchapi.Task{{
+					Type: porchapi.TaskTypeInit,
+					Init: &porchapi.PackageInitTaskSpec{
+						Description: "sample description",
+						Keywords:    []string{"test"},
+					},
+				}},
+			}, pr.Spec)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleContainerTools/kpt/commands/alpha/rpkg/util"
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/rpkgdocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	command = "cmdrpkgpromote"
+)
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:     "promote PACKAGE --to REPOSITORY [flags]",
+		Short:   rpkgdocs.PromoteShort,
+		Long:    rpkgdocs.PromoteShort + "\n" + rpkgdocs.PromoteLong,
+		Example: rpkgdocs.PromoteExamples,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+		Hidden:  porch.HidePorchCommands,
+
+		ValidArgsFunction: porch.Completer{Flags: rcg}.PackageRevisionArgs(1, porchapi.PackageRevisionLifecyclePublished),
+	}
+	r.Command = c
+
+	c.Flags().StringVar(&r.repository, "to", "", "Repository to promote the package revision to (deployment repository).")
+	c.Flags().StringVar(&r.target, "name", "", "Name of the package in the target repository. Defaults to the name of the promoted package.")
+	c.Flags().StringVar(&r.workspace, "workspace", "v1", "Workspace name of the new package revision.")
+	_ = c.RegisterFlagCompletionFunc("to", porch.Completer{Flags: rcg}.Repositories)
+
+	return r
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+
+	source *porchapi.PackageRevision
+
+	// Flags
+	repository string // Target repository
+	target     string // Target package name
+	workspace  string // Target workspaceName
+}
+
+func (r *runner) preRunE(_ *cobra.Command, args []string) error {
+	const op errors.Op = command + ".preRunE"
+	client, err := porch.CreateClientWithFlags(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+	return r.parseArgs(args)
+}
+
+func (r *runner) parseArgs(args []string) error {
+	const op errors.Op = command + ".parseArgs"
+
+	if r.repository == "" {
+		return errors.E(op, fmt.Errorf("--to is required to specify the repository to promote to"))
+	}
+	if r.workspace == "" {
+		return errors.E(op, fmt.Errorf("--workspace is required to specify the workspace name"))
+	}
+
+	var source porchapi.PackageRevision
+	if err := r.client.Get(r.ctx, client.ObjectKey{
+		Namespace: *r.cfg.Namespace,
+		Name:      args[0],
+	}, &source); err != nil {
+		return errors.E(op, err)
+	}
+	if source.Spec.Lifecycle != porchapi.PackageRevisionLifecyclePublished {
+		return errors.E(op, fmt.Errorf("only published package revisions can be promoted; %q is %s", source.Name, source.Spec.Lifecycle))
+	}
+	if source.Spec.RepositoryName == r.repository {
+		return errors.E(op, fmt.Errorf("package revision %q is already in repository %q", source.Name, r.repository))
+	}
+	if r.target == "" {
+		r.target = source.Spec.PackageName
+	}
+
+	pkgExists, err := util.PackageAlreadyExists(r.ctx, r.client, r.repository, r.target, *r.cfg.Namespace)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if pkgExists {
+		return errors.E(op, fmt.Errorf("package %q already exists in repository %q; update it to %q with `rpkg copy` and `rpkg update` instead",
+			r.target, r.repository, source.Name))
+	}
+
+	r.source = &source
+	return nil
+}
+
+func (r *runner) runE(cmd *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".runE"
+
+	pr := r.packageRevision()
+	if err := r.client.Create(r.ctx, pr); err != nil {
+		return errors.E(op, err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s promoted to %s\n", r.source.Name, pr.Name)
+	return nil
+}
+
+// packageRevision returns the draft package revision cloned from the source
+// package revision, with the provenance of the promotion.
+func (r *runner) packageRevision() *porchapi.PackageRevision {
+	pr := &porchapi.PackageRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevision",
+			APIVersion: porchapi.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: *r.cfg.Namespace,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			PackageName:    r.target,
+			WorkspaceName:  porchapi.WorkspaceName(r.workspace),
+			RepositoryName: r.repository,
+			Lifecycle:      porchapi.PackageRevisionLifecycleDraft,
+			Tasks: []porchapi.Task{
+				{
+					Type: porchapi.TaskTypeClone,
+					Clone: &porchapi.PackageCloneTaskSpec{
+						Upstream: porchapi.UpstreamPackage{
+							UpstreamRef: &porchapi.PackageRevisionRef{Name: r.source.Name},
+						},
+						Strategy: porchapi.ResourceMerge,
+					},
+				},
+			},
+		},
+	}
+	porch.SetPromotionProvenance(pr, r.source)
+	return pr
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promote
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func packageRevision(ns, name, repo, pkg, revision string, lifecycle porchapi.PackageRevisionLifecycle) client.Object {
+	return &porchapi.PackageRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: porchapi.PackageRevisionSpec{
+			RepositoryName: repo,
+			PackageName:    pkg,
+			Revision:       revision,
+			Lifecycle:      lifecycle,
+		},
+	}
+}
+
+func TestPromote(t *testing.T) {
+	ns := "ns"
+	objs := []client.Object{
+		packageRevision(ns, "catalog-basens-v1", "catalog", "basens", "v1", porchapi.PackageRevisionLifecyclePublished),
+		packageRevision(ns, "catalog-basens-draft", "catalog", "basens", "", porchapi.PackageRevisionLifecycleDraft),
+		packageRevision(ns, "deployments-existing-v1", "deployments", "existing", "v1", porchapi.PackageRevisionLifecyclePublished),
+	}
+
+	testCases := map[string]struct {
+		args       []string
+		repository string
+		target     string
+		wantErr    string
+		wantName   string
+	}{
+		"promote": {
+			args:       []string{"catalog-basens-v1"},
+			repository: "deployments",
+			wantName:   "basens",
+		},
+		"promote with a new name": {
+			args:       []string{"catalog-basens-v1"},
+			repository: "deployments",
+			target:     "basens-prod",
+			wantName:   "basens-prod",
+		},
+		"missing repository": {
+			args:    []string{"catalog-basens-v1"},
+			wantErr: "--to is required to specify the repository to promote to",
+		},
+		"draft": {
+			args:       []string{"catalog-basens-draft"},
+			repository: "deployments",
+			wantErr:    `only published package revisions can be promoted; "catalog-basens-draft" is Draft`,
+		},
+		"same repository": {
+			args:       []string{"catalog-basens-v1"},
+			repository: "catalog",
+			wantErr:    `package revision "catalog-basens-v1" is already in repository "catalog"`,
+		},
+		"package exists": {
+			args:       []string{"catalog-basens-v1"},
+			repository: "deployments",
+			target:     "existing",
+			wantErr:    `package "existing" already exists in repository "deployments"`,
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(createScheme(t)).WithObjects(objs...).Build()
+			r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+			r.client = c
+			r.repository = tc.repository
+			r.target = tc.target
+
+			err := r.parseArgs(tc.args)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			pr := r.packageRevision()
+			assert.Equal(t, tc.wantName, pr.Spec.PackageName)
+			assert.Equal(t, tc.repository, pr.Spec.RepositoryName)
+			assert.Equal(t, porchapi.WorkspaceName("v1"), pr.Spec.WorkspaceName)
+			assert.Equal(t, porchapi.PackageRevisionLifecycleDraft, pr.Spec.Lifecycle)
+			if assert.Len(t, pr.Spec.Tasks, 1) {
+				assert.Equal(t, "catalog-basens-v1", pr.Spec.Tasks[0].Clone.Upstream.UpstreamRef.Name)
+			}
+			assert.Equal(t, map[string]string{
+				porch.PromotedFromAnnotation:           "catalog-basens-v1",
+				porch.PromotedFromRepositoryAnnotation: "catalog",
+				porch.PromotedFromPackageAnnotation:    "basens",
+				porch.PromotedFromRevisionAnnotation:   "v1",
+			}, pr.Annotations)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propose
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func TestCmd(t *testing.T) {
+	pkgRevName := "test-pr"
+	ns := "ns"
+
+	testCases := map[string]struct {
+		lifecycle  porchapi.PackageRevisionLifecycle
+		message    string
+		output     string
+		wantErr    bool
+		wantLife   porchapi.PackageRevisionLifecycle
+		wantAnnots map[string]string
+	}{
+		"Propose draft": {
+			lifecycle: porchapi.PackageRevisionLifecycleDraft,
+			output:    pkgRevName + " proposed\n",
+			wantLife:  porchapi.PackageRevisionLifecycleProposed,
+		},
+		"Propose draft with message": {
+			lifecycle:  porchapi.PackageRevisionLifecycleDraft,
+			message:    "ready for review",
+			output:     pkgRevName + " proposed\n",
+			wantLife:   porchapi.PackageRevisionLifecycleProposed,
+			wantAnnots: map[string]string{porch.ProposeMessageAnnotation: "ready for review"},
+		},
+		"Already proposed": {
+			lifecycle: porchapi.PackageRevisionLifecycleProposed,
+			output:    pkgRevName + " is already proposed\n",
+			wantLife:  porchapi.PackageRevisionLifecycleProposed,
+		},
+		"Cannot propose published": {
+			lifecycle: porchapi.PackageRevisionLifecyclePublished,
+			output:    "cannot propose Published package\n",
+			wantErr:   true,
+			wantLife:  porchapi.PackageRevisionLifecyclePublished,
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(createScheme(t)).
+				WithObjects(&porchapi.PackageRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      pkgRevName,
+						Namespace: ns,
+					},
+					Spec: porchapi.PackageRevisionSpec{
+						Lifecycle: tc.lifecycle,
+					},
+				}).
+				Build()
+
+			output := &bytes.Buffer{}
+			r := &runner{
+				ctx: context.Background(),
+				cfg: &genericclioptions.ConfigFlags{
+					Namespace: &ns,
+				},
+				client:  c,
+				message: tc.message,
+				Command: newRunner(context.Background(), nil).Command,
+			}
+			r.Command.SetOut(output)
+			r.Command.SetErr(output)
+
+			err := r.runE(r.Command, []string{pkgRevName})
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.output, output.String())
+
+			var pr porchapi.PackageRevision
+			if err := c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: pkgRevName}, &pr); err != nil {
+				t.Fatalf("failed to get package revision: %v", err)
+			}
+			assert.Equal(t, tc.wantLife, pr.Spec.Lifecycle)
+			for k, v := range tc.wantAnnots {
+				assert.Equal(t, v, pr.Annotations[k])
+			}
+		})
+	}
+}
+
+func TestCmdSelector(t *testing.T) {
+	ns := "ns"
+	pr := func(name string, lifecycle porchapi.PackageRevisionLifecycle) client.Object {
+		return &porchapi.PackageRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: map[string]string{"app": "app"}},
+			Spec: porchapi.PackageRevisionSpec{
+				RepositoryName: "deployments",
+				Lifecycle:      lifecycle,
+			},
+		}
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(createScheme(t)).
+		WithObjects(
+			pr("deployments-east", porchapi.PackageRevisionLifecycleDraft),
+			pr("deployments-west", porchapi.PackageRevisionLifecycleDraft),
+			pr("deployments-north", porchapi.PackageRevisionLifecyclePublished),
+		).
+		Build()
+
+	output := &bytes.Buffer{}
+	r := newRunner(context.Background(), &genericclioptions.ConfigFlags{Namespace: &ns})
+	r.client = c
+	r.Command.SetOut(output)
+	r.Command.SetErr(output)
+	assert.NoError(t, r.Command.Flags().Parse([]string{"-l", "app=app"}))
+
+	err := r.runE(r.Command, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `deployments-east proposed
+deployments-west proposed
+2 of 2 package revisions proposed
+`, output.String())
+
+	for name, lifecycle := range map[string]porchapi.PackageRevisionLifecycle{
+		"deployments-east":  porchapi.PackageRevisionLifecycleProposed,
+		"deployments-west":  porchapi.PackageRevisionLifecycleProposed,
+		"deployments-north": porchapi.PackageRevisionLifecyclePublished,
+	} {
+		var pr porchapi.PackageRevision
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: name}, &pr))
+		assert.Equal(t, lifecycle, pr.Spec.Lifecycle)
+	}
+
+	err = r.runE(r.Command, []string{"deployments-east"})
+	assert.ErrorContains(t, err, "PACKAGE arguments can't be combined with --selector, --repository or --name")
+}
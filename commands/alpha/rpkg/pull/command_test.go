@@ -0,0 +1,139 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const kptfile = `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: test-package
+info:
+  description: sample description
+`
+
+const configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: example
+data:
+  key: value
+`
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+func createRunner(t *testing.T, ns string, out *bytes.Buffer) *runner {
+	c := fake.NewClientBuilder().
+		WithScheme(createScheme(t)).
+		WithObjects(&porchapi.PackageRevisionResources{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pr",
+				Namespace: ns,
+			},
+			Spec: porchapi.PackageRevisionResourcesSpec{
+				Resources: map[string]string{
+					kptfilev1.KptFileName: kptfile,
+					"cm/cm.yaml":          configMap,
+					"README.md":           "# test package\n",
+				},
+			},
+		}).
+		Build()
+
+	ctx := printer.WithContext(context.Background(), printer.New(out, out))
+	return &runner{
+		ctx:     ctx,
+		cfg:     &genericclioptions.ConfigFlags{Namespace: &ns},
+		client:  c,
+		Command: newRunner(ctx, nil).Command,
+	}
+}
+
+func TestPullToWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	r := createRunner(t, "ns", out)
+
+	if err := r.runE(r.Command, []string{"test-pr"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	assert.Contains(t, got, "kind: ResourceList")
+	assert.Contains(t, got, "kind: "+kptfilev1.RevisionMetaDataKind)
+	assert.Contains(t, got, "internal.config.kubernetes.io/path: 'cm/cm.yaml'")
+	assert.Contains(t, got, "internal.config.kubernetes.io/path: 'Kptfile'")
+	assert.Contains(t, got, "resourceVersion:")
+	// Files that aren't KRM resources are only written to a directory.
+	assert.NotContains(t, got, "# test package")
+}
+
+func TestPullToDir(t *testing.T) {
+	out := &bytes.Buffer{}
+	r := createRunner(t, "ns", out)
+	dir := filepath.Join(t.TempDir(), "pkg")
+
+	if err := r.runE(r.Command, []string{"test-pr", dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, f := range []string{kptfilev1.KptFileName, kptfilev1.RevisionMetaDataFileName, "README.md", "cm/cm.yaml"} {
+		_, err := os.Stat(filepath.Join(dir, f))
+		assert.NoError(t, err, "expected %s to be written", f)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "cm", "cm.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, configMap, string(b))
+
+	// Pulling into an existing directory must fail.
+	err = r.runE(r.Command, []string{"test-pr", dir})
+	assert.Error(t, err)
+}
+
+func TestPullToDir_InvalidPath(t *testing.T) {
+	for _, path := range []string{"../escape.yaml", "cm/../../escape.yaml", "/tmp/escape.yaml"} {
+		dir := filepath.Join(t.TempDir(), "pkg")
+		err := writeToDir(map[string]string{
+			kptfilev1.KptFileName: kptfile,
+			path:                  configMap,
+		}, dir)
+		if assert.Error(t, err, path) {
+			assert.Contains(t, err.Error(), "invalid path")
+		}
+		// Nothing must be written if any of the paths is invalid.
+		_, err = os.Stat(dir)
+		assert.True(t, os.IsNotExist(err), path)
+	}
+}
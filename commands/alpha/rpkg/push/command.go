@@ -0,0 +1,305 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/commands/alpha/rpkg/util"
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/rpkgdocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
+	"github.com/GoogleContainerTools/kpt/internal/util/porch"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	command = "cmdrpkgpush"
+)
+
+func newRunner(ctx context.Context, rcg *genericclioptions.ConfigFlags) *runner {
+	r := &runner{
+		ctx: ctx,
+		cfg: rcg,
+	}
+	c := &cobra.Command{
+		Use:        "push PACKAGE [DIR]",
+		Aliases:    []string{"sink", "write"},
+		SuggestFor: []string{},
+		Short:      rpkgdocs.PushShort,
+		Long:       rpkgdocs.PushShort + "\n" + rpkgdocs.PushLong,
+		Example:    rpkgdocs.PushExamples,
+		PreRunE:    r.preRunE,
+		RunE:       r.runE,
+		Hidden:     porch.HidePorchCommands,
+	}
+	r.Command = c
+
+	completePackage := porch.Completer{Flags: rcg}.PackageRevisionArgs(1, porchapi.PackageRevisionLifecycleDraft)
+	c.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+		return completePackage(cmd, args, toComplete)
+	}
+	return r
+}
+
+func NewCommand(ctx context.Context, rcg *genericclioptions.ConfigFlags) *cobra.Command {
+	return newRunner(ctx, rcg).Command
+}
+
+type runner struct {
+	ctx     context.Context
+	cfg     *genericclioptions.ConfigFlags
+	client  client.Client
+	Command *cobra.Command
+}
+
+func (r *runner) preRunE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".preRunE"
+
+	client, err := porch.CreateClientWithFlags(r.cfg)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.client = client
+	return nil
+}
+
+func (r *runner) runE(cmd *cobra.Command, args []string) error {
+	const op errors.Op = command + ".runE"
+
+	if len(args) == 0 {
+		return errors.E(op, "PACKAGE is a required positional argument")
+	}
+
+	packageName := args[0]
+	var resources map[string]string
+	var err error
+
+	if len(args) > 1 {
+		resources, err = readFromDir(args[1])
+	} else {
+		resources, err = readFromReader(cmd.InOrStdin())
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	pkgResources := porchapi.PackageRevisionResources{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PackageRevisionResources",
+			APIVersion: porchapi.SchemeGroupVersion.Identifier(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      packageName,
+			Namespace: *r.cfg.Namespace,
+		},
+		Spec: porchapi.PackageRevisionResourcesSpec{
+			Resources: resources,
+		},
+	}
+
+	rv, err := util.GetResourceVersion(&pkgResources)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	pkgResources.ResourceVersion = rv
+	if err := util.RemoveRevisionMetadata(&pkgResources); err != nil {
+		return errors.E(op, err)
+	}
+
+	if err := r.client.Update(r.ctx, &pkgResources); err != nil {
+		return errors.E(op, err)
+	}
+	r.printRenderStatus(pkgResources.Status.RenderStatus)
+	return nil
+}
+
+// printRenderStatus prints the outcome of the render that porch performs
+// when the package resources are updated.
+func (r *runner) printRenderStatus(rs porchapi.RenderStatus) {
+	pr := printer.FromContextOrDie(r.ctx)
+	if rs.Err != "" {
+		pr.Printf("Package is updated, but failed to render the package.\n")
+		pr.Printf("Error: %s\n", rs.Err)
+	}
+	for _, result := range rs.Result.Items {
+		pr.Printf("[RUNNING] %q\n", result.Image)
+		opt := printer.NewOpt()
+		if result.ExitCode != 0 {
+			pr.OptPrintf(opt, "[FAIL] %q\n", result.Image)
+		} else {
+			pr.OptPrintf(opt, "[PASS] %q\n", result.Image)
+		}
+		if len(result.Results) > 0 {
+			var lines []string
+			for _, item := range result.Results {
+				lines = append(lines, str(item))
+			}
+			ri := &fnruntime.MultiLineFormatter{
+				Title:          "Results",
+				Lines:          lines,
+				TruncateOutput: printer.TruncateOutput,
+			}
+			pr.OptPrintf(opt, "%s", ri.String())
+		}
+	}
+}
+
+// str formats a porch function result item the same way kpt formats
+// results of functions executed locally.
+func str(i porchapi.ResultItem) string {
+	severity := i.Severity
+	if severity == "" {
+		severity = "info"
+	}
+	formatString := "[%s]"
+	list := []interface{}{severity}
+
+	var idStringList []string
+	if identifier := i.ResourceRef; identifier != nil {
+		for _, s := range []string{identifier.APIVersion, identifier.Kind, identifier.Namespace, identifier.Name} {
+			if s != "" {
+				idStringList = append(idStringList, s)
+			}
+		}
+	}
+	if len(idStringList) > 0 {
+		formatString += " %s"
+		list = append(list, strings.Join(idStringList, "/"))
+	}
+	if i.Field != nil {
+		formatString += " %s"
+		list = append(list, i.Field.Path)
+	}
+	formatString += ": %s"
+	list = append(list, i.Message)
+	return fmt.Sprintf(formatString, list...)
+}
+
+func readFromDir(dir string) (map[string]string, error) {
+	resources := map[string]string{}
+	if err := filepath.WalkDir(dir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		resources[filepath.ToSlash(rel)] = string(contents)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func readFromReader(in io.Reader) (map[string]string, error) {
+	rw := &resourceWriter{
+		resources: map[string]string{},
+	}
+
+	if err := (kio.Pipeline{
+		Inputs: []kio.Reader{&kio.ByteReader{
+			Reader:            in,
+			PreserveSeqIndent: true,
+			WrapBareSeqNode:   true,
+		}},
+		Outputs: []kio.Writer{rw},
+	}.Execute()); err != nil {
+		return nil, err
+	}
+	return rw.resources, nil
+}
+
+// resourceWriter groups the resources read from a ResourceList by the
+// file they belong to, as recorded in the path annotation.
+type resourceWriter struct {
+	resources map[string]string
+}
+
+var _ kio.Writer = &resourceWriter{}
+
+func (w *resourceWriter) Write(nodes []*yaml.RNode) error {
+	paths := map[string][]*yaml.RNode{}
+	for _, node := range nodes {
+		path := getPath(node)
+		paths[path] = append(paths[path], node)
+	}
+
+	buf := &bytes.Buffer{}
+	for path, nodes := range paths {
+		bw := kio.ByteWriter{
+			Writer: buf,
+			ClearAnnotations: []string{
+				kioutil.PathAnnotation,
+				kioutil.IndexAnnotation,
+				kioutil.LegacyPathAnnotation,
+				kioutil.LegacyIndexAnnotation,
+			},
+		}
+		if err := bw.Write(nodes); err != nil {
+			return err
+		}
+		w.resources[path] = buf.String()
+		buf.Reset()
+	}
+	return nil
+}
+
+func getPath(node *yaml.RNode) string {
+	ann := node.GetAnnotations()
+	if path, ok := ann[kioutil.PathAnnotation]; ok {
+		return path
+	}
+	if path, ok := ann[kioutil.LegacyPathAnnotation]; ok {
+		return path
+	}
+	ns := node.GetNamespace()
+	if ns == "" {
+		ns = "non-namespaced"
+	}
+	name := node.GetName()
+	if name == "" {
+		name = "unnamed"
+	}
+	// TODO: harden for escaping etc.
+	return filepath.Join(ns, fmt.Sprintf("%s.yaml", name))
+}
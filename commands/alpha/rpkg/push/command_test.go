@@ -0,0 +1,162 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func createScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := porchapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("error adding porch types to scheme: %v", err)
+	}
+	return scheme
+}
+
+const resourceList = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: porch.kpt.dev/v1alpha1
+  kind: KptRevisionMetadata
+  metadata:
+    name: test-pr
+    namespace: ns
+    resourceVersion: "%s"
+    annotations:
+      config.kubernetes.io/path: .KptRevisionMetadata
+- apiVersion: kpt.dev/v1
+  kind: Kptfile
+  metadata:
+    name: test-package
+    annotations:
+      config.kubernetes.io/path: Kptfile
+  info:
+    description: updated description
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: example
+    namespace: default
+  data:
+    key: value
+`
+
+func TestReadFromReader(t *testing.T) {
+	resources, err := readFromReader(strings.NewReader(strings.Replace(resourceList, "%s", "1", 1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.ElementsMatch(t, []string{".KptRevisionMetadata", "Kptfile", "default/example.yaml"}, keys(resources))
+	assert.NotContains(t, resources["Kptfile"], "config.kubernetes.io/path")
+	assert.Contains(t, resources["Kptfile"], "description: updated description")
+}
+
+func TestReadFromDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Kptfile"), []byte("kind: Kptfile\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "cm.yaml"), []byte("kind: ConfigMap\n"), 0644))
+
+	resources, err := readFromDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, map[string]string{
+		"Kptfile":     "kind: Kptfile\n",
+		"sub/cm.yaml": "kind: ConfigMap\n",
+	}, resources)
+}
+
+func TestPush(t *testing.T) {
+	ns := "ns"
+	c := fake.NewClientBuilder().
+		WithScheme(createScheme(t)).
+		WithObjects(&porchapi.PackageRevisionResources{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pr",
+				Namespace: ns,
+			},
+			Spec: porchapi.PackageRevisionResourcesSpec{
+				Resources: map[string]string{
+					"Kptfile": "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: test-package\n",
+				},
+			},
+		}).
+		Build()
+
+	var current porchapi.PackageRevisionResources
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "test-pr"}, &current); err != nil {
+		t.Fatalf("failed to get package revision resources: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	ctx := printer.WithContext(context.Background(), printer.New(out, out))
+	r := &runner{
+		ctx:     ctx,
+		cfg:     &genericclioptions.ConfigFlags{Namespace: &ns},
+		client:  c,
+		Command: newRunner(ctx, nil).Command,
+	}
+	r.Command.SetIn(strings.NewReader(strings.Replace(resourceList, "%s", current.ResourceVersion, 1)))
+
+	if err := r.runE(r.Command, []string{"test-pr"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated porchapi.PackageRevisionResources
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: "test-pr"}, &updated); err != nil {
+		t.Fatalf("failed to get package revision resources: %v", err)
+	}
+	assert.ElementsMatch(t, []string{"Kptfile", "default/example.yaml"}, keys(updated.Spec.Resources))
+	assert.Contains(t, updated.Spec.Resources["Kptfile"], "description: updated description")
+}
+
+func TestStr(t *testing.T) {
+	item := porchapi.ResultItem{
+		Message:  "field is required",
+		Severity: "error",
+		ResourceRef: &porchapi.ResourceIdentifier{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			NameMeta: porchapi.NameMeta{Name: "example"},
+		},
+		Field: &porchapi.Field{Path: "data.key"},
+	}
+	assert.Equal(t, "[error] v1/ConfigMap/example data.key: field is required", str(item))
+	assert.Equal(t, "[info]: done", str(porchapi.ResultItem{Message: "done"}))
+}
+
+func keys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// AddRevisionMetadata adds a KptRevisionMetadata resource holding the
+// object metadata of the package revision to the package resources. It
+// allows pushing locally edited resources back without losing track of
+// the resourceVersion they were pulled at.
+func AddRevisionMetadata(prr *porchapi.PackageRevisionResources) error {
+	meta := metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: prr.APIVersion,
+			Kind:       kptfilev1.RevisionMetaDataKind,
+		},
+		ObjectMeta: prr.ObjectMeta,
+	}
+	b, err := yaml.Marshal(&meta)
+	if err != nil {
+		return fmt.Errorf("cannot serialize revision metadata: %w", err)
+	}
+	if prr.Spec.Resources == nil {
+		prr.Spec.Resources = map[string]string{}
+	}
+	prr.Spec.Resources[kptfilev1.RevisionMetaDataFileName] = string(b)
+	return nil
+}
+
+// RemoveRevisionMetadata removes the KptRevisionMetadata resource from the
+// package resources.
+func RemoveRevisionMetadata(prr *porchapi.PackageRevisionResources) error {
+	delete(prr.Spec.Resources, kptfilev1.RevisionMetaDataFileName)
+	return nil
+}
+
+// GetResourceVersion returns the resourceVersion recorded in the
+// KptRevisionMetadata resource of the package resources, or an empty
+// string if the package resources don't include one.
+func GetResourceVersion(prr *porchapi.PackageRevisionResources) (string, error) {
+	content, found := prr.Spec.Resources[kptfilev1.RevisionMetaDataFileName]
+	if !found {
+		return "", nil
+	}
+	var meta metav1.PartialObjectMetadata
+	if err := yaml.Unmarshal([]byte(content), &meta); err != nil {
+		return "", fmt.Errorf("cannot parse %s: %w", kptfilev1.RevisionMetaDataFileName, err)
+	}
+	return meta.ResourceVersion, nil
+}
+
+// PackageAlreadyExists returns true if a package revision for the given
+// package already exists in the repository.
+func PackageAlreadyExists(ctx context.Context, c client.Client, repository, packageName, namespace string) (bool, error) {
+	// only the first package revision can be created from init or clone, so
+	// we need to check that the package doesn't already exist.
+	packageRevisionList := porchapi.PackageRevisionList{}
+	if err := c.List(ctx, &packageRevisionList, &client.ListOptions{
+		Namespace: namespace,
+	}); err != nil {
+		return false, err
+	}
+	for _, pr := range packageRevisionList.Items {
+		if pr.Spec.RepositoryName == repository && pr.Spec.PackageName == packageName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
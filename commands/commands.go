@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/GoogleContainerTools/kpt/commands/alpha"
+	"github.com/GoogleContainerTools/kpt/commands/doctor"
 	"github.com/GoogleContainerTools/kpt/commands/fn"
 	"github.com/GoogleContainerTools/kpt/commands/live"
 	"github.com/GoogleContainerTools/kpt/commands/pkg"
@@ -40,8 +41,9 @@ func GetKptCommands(ctx context.Context, name, version string) []*cobra.Command
 	pkgCmd := pkg.GetCommand(ctx, name)
 	liveCmd := live.GetCommand(ctx, name, version)
 	alphaCmd := alpha.GetCommand(ctx, name, version)
+	doctorCmd := doctor.NewCommand(ctx, version)
 
-	c = append(c, pkgCmd, fnCmd, liveCmd, alphaCmd)
+	c = append(c, pkgCmd, fnCmd, liveCmd, alphaCmd, doctorCmd)
 
 	// apply cross-cutting issues to commands
 	NormalizeCommand(c...)
@@ -0,0 +1,392 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"golang.org/x/mod/semver"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusError   Status = "error"
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the result of a check.
+type Result struct {
+	// Name identifies the check, e.g. `git`.
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	// Message describes what was found.
+	Message string `json:"message"`
+	// Remediation describes how to fix the problem, if any.
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// minSupportedGitVersion is the oldest git version supporting all the git
+// commands used by kpt, e.g. `git ls-remote --symref`.
+const minSupportedGitVersion = "v2.8.0"
+
+// DefaultRegistries are the registries checked for reachability by default.
+// The kpt function catalog is published to gcr.io.
+var DefaultRegistries = []string{"gcr.io", "ghcr.io", "registry-1.docker.io"}
+
+// porchRules are the permissions needed to use the `kpt alpha rpkg` and
+// `kpt alpha repo` commands.
+var porchRules = []authorizationv1.ResourceAttributes{
+	{Group: porchapi.SchemeGroupVersion.Group, Resource: "packagerevisions", Verb: "list"},
+	{Group: porchapi.SchemeGroupVersion.Group, Resource: "packagerevisions", Verb: "create"},
+	{Group: porchapi.SchemeGroupVersion.Group, Resource: "packagerevisions", Verb: "update"},
+	{Group: porchapi.SchemeGroupVersion.Group, Resource: "packagerevisions", Verb: "delete"},
+	{Group: porchapi.SchemeGroupVersion.Group, Resource: "packagerevisions", Subresource: "approval", Verb: "update"},
+	{Group: porchapi.SchemeGroupVersion.Group, Resource: "packagerevisionresources", Verb: "update"},
+	{Group: configapi.GroupVersion.Group, Resource: "repositories", Verb: "list"},
+	{Group: configapi.GroupVersion.Group, Resource: "repositories", Verb: "create"},
+}
+
+// checker runs the environment checks. The external commands and the HTTP
+// client are fields so they can be replaced in tests.
+type checker struct {
+	flags      *genericclioptions.ConfigFlags
+	registries []string
+	timeout    time.Duration
+
+	runCommand func(ctx context.Context, name string, args ...string) (string, error)
+	httpClient *http.Client
+	// runtimeAvailable checks the availability of a container runtime.
+	runtimeAvailable func(runtime fnruntime.ContainerRuntime) error
+	lookPath         func(file string) (string, error)
+
+	// checks are the checks to run, in order.
+	checks []func(ctx context.Context) []Result
+}
+
+func newChecker(flags *genericclioptions.ConfigFlags, registries []string, timeout time.Duration) *checker {
+	c := &checker{
+		flags:            flags,
+		registries:       registries,
+		timeout:          timeout,
+		runCommand:       runCommand,
+		httpClient:       &http.Client{Timeout: timeout},
+		runtimeAvailable: fnruntime.ContainerRuntimeAvailable,
+		lookPath:         exec.LookPath,
+	}
+	c.checks = []func(context.Context) []Result{
+		single(c.checkContainerRuntime),
+		single(c.checkGit),
+		single(c.checkGitCredentialHelper),
+		c.checkRegistries,
+		c.checkPorch,
+	}
+	return c
+}
+
+func single(check func(ctx context.Context) Result) func(ctx context.Context) []Result {
+	return func(ctx context.Context) []Result {
+		return []Result{check(ctx)}
+	}
+}
+
+// Run runs all checks and returns their results.
+func (c *checker) Run(ctx context.Context) []Result {
+	var results []Result
+	for _, check := range c.checks {
+		results = append(results, check(ctx)...)
+	}
+	return results
+}
+
+func (c *checker) checkContainerRuntime(_ context.Context) Result {
+	const name = "container runtime"
+	var runtime fnruntime.ContainerRuntime
+	var err error
+	if v := os.Getenv(fnruntime.ContainerRuntimeEnv); v != "" {
+		runtime, err = fnruntime.StringToContainerRuntime(v)
+	} else {
+		runtime = fnruntime.DetectContainerRuntime(c.lookPath)
+	}
+	if err != nil {
+		return Result{
+			Name:        name,
+			Status:      StatusError,
+			Message:     err.Error(),
+			Remediation: fmt.Sprintf("Set %s to one of %s, %s or %s.", fnruntime.ContainerRuntimeEnv, fnruntime.Docker, fnruntime.Podman, fnruntime.Nerdctl),
+		}
+	}
+	err = c.runtimeAvailable(runtime)
+	if err == nil {
+		return Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("%s is available", runtime.GetBin())}
+	}
+	if !fnruntime.HasFnImages() {
+		// the catalog function images can't run on this architecture anyway.
+		return Result{
+			Name:        name,
+			Status:      StatusWarning,
+			Message:     fmt.Sprintf("%s is not available, and no function images are published for this architecture", runtime.GetBin()),
+			Remediation: fmt.Sprintf("Catalog functions with a builtin implementation run without containers (see %s); use exec functions (--exec) for other functions.", fnruntime.PreferBuiltinEnv),
+		}
+	}
+
+	remediation := fmt.Sprintf("Install and start %s to run container functions.", runtime.GetBin())
+	for _, alt := range []fnruntime.ContainerRuntime{fnruntime.Docker, fnruntime.Podman, fnruntime.Nerdctl} {
+		if alt == runtime {
+			continue
+		}
+		if _, err := c.lookPath(alt.GetBin()); err == nil {
+			remediation += fmt.Sprintf(" Alternatively, use %s by setting %s=%s.", alt.GetBin(), fnruntime.ContainerRuntimeEnv, alt)
+			break
+		}
+	}
+	return Result{
+		Name:        name,
+		Status:      StatusError,
+		Message:     fmt.Sprintf("%s is not available: %s", runtime.GetBin(), firstLine(err.Error())),
+		Remediation: remediation,
+	}
+}
+
+var gitVersionRegexp = regexp.MustCompile(`git version (\d+)\.(\d+)(?:\.(\d+))?`)
+
+func (c *checker) checkGit(ctx context.Context) Result {
+	const name = "git"
+	out, err := c.runCommand(ctx, "git", "version")
+	if err != nil {
+		return Result{
+			Name:        name,
+			Status:      StatusError,
+			Message:     fmt.Sprintf("git is not available: %v", err),
+			Remediation: "Install git from https://git-scm.com/downloads and make sure it is on the PATH.",
+		}
+	}
+	version, ok := parseGitVersion(out)
+	if !ok {
+		return Result{
+			Name:    name,
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("unable to determine the git version from %q", strings.TrimSpace(out)),
+		}
+	}
+	if semver.Compare(version, minSupportedGitVersion) < 0 {
+		return Result{
+			Name:        name,
+			Status:      StatusError,
+			Message:     fmt.Sprintf("git %s is older than the minimum supported version %s", version, minSupportedGitVersion),
+			Remediation: "Upgrade git from https://git-scm.com/downloads.",
+		}
+	}
+	return Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("git %s", version)}
+}
+
+// parseGitVersion returns the semantic version of the output of
+// `git version`, e.g. `v2.39.2` for `git version 2.39.2.windows.1`.
+func parseGitVersion(out string) (string, bool) {
+	m := gitVersionRegexp.FindStringSubmatch(out)
+	if m == nil {
+		return "", false
+	}
+	patch := m[3]
+	if patch == "" {
+		patch = "0"
+	}
+	return fmt.Sprintf("v%s.%s.%s", m[1], m[2], patch), true
+}
+
+func (c *checker) checkGitCredentialHelper(ctx context.Context) Result {
+	const name = "git credential helper"
+	// `git config --get-all` fails if the key is not set.
+	out, _ := c.runCommand(ctx, "git", "config", "--get-all", "credential.helper")
+	var helpers []string
+	for _, l := range strings.Split(out, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			helpers = append(helpers, l)
+		}
+	}
+	if len(helpers) == 0 {
+		return Result{
+			Name:        name,
+			Status:      StatusWarning,
+			Message:     "no git credential helper is configured",
+			Remediation: "Configure a credential helper to fetch packages from private repositories, e.g. `git config --global credential.helper cache`.",
+		}
+	}
+	return Result{Name: name, Status: StatusOK, Message: strings.Join(helpers, ", ")}
+}
+
+func (c *checker) checkRegistries(ctx context.Context) []Result {
+	var results []Result
+	for _, registry := range c.registries {
+		results = append(results, c.checkRegistry(ctx, registry))
+	}
+	return results
+}
+
+func (c *checker) checkRegistry(ctx context.Context, registry string) Result {
+	name := "registry " + registry
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+registry+"/v2/", nil)
+	if err != nil {
+		return Result{Name: name, Status: StatusError, Message: err.Error()}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Result{
+			Name:        name,
+			Status:      StatusWarning,
+			Message:     fmt.Sprintf("%s is not reachable: %v", registry, err),
+			Remediation: "Check your network connection and proxy settings (HTTPS_PROXY), or mirror the function images to a reachable registry.",
+		}
+	}
+	defer resp.Body.Close()
+	// Registries require authentication for the API root, so any response
+	// means the registry is reachable.
+	return Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("%s is reachable (%s)", registry, resp.Status)}
+}
+
+func (c *checker) checkPorch(ctx context.Context) []Result {
+	const name = "porch"
+	if !c.clusterConfigured() {
+		return []Result{{
+			Name:        name,
+			Status:      StatusSkipped,
+			Message:     "no cluster configured",
+			Remediation: "Set KUBECONFIG or pass --kubeconfig to check the connectivity to porch.",
+		}}
+	}
+	config, err := c.flags.ToRESTConfig()
+	if err != nil {
+		return []Result{{
+			Name:        name,
+			Status:      StatusSkipped,
+			Message:     fmt.Sprintf("no cluster configured: %s", firstLine(err.Error())),
+			Remediation: "Set KUBECONFIG or pass --kubeconfig to check the connectivity to porch.",
+		}}
+	}
+	config.Timeout = c.timeout
+	cs, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return []Result{{Name: name, Status: StatusError, Message: err.Error()}}
+	}
+	if _, err := cs.Discovery().ServerResourcesForGroupVersion(porchapi.SchemeGroupVersion.String()); err != nil {
+		return []Result{{
+			Name:        name,
+			Status:      StatusError,
+			Message:     fmt.Sprintf("the porch API is not available on %s: %s", config.Host, firstLine(err.Error())),
+			Remediation: "Check that the cluster is reachable and that porch is installed, see https://kpt.dev/guides/porch-installation.",
+		}}
+	}
+	results := []Result{{Name: name, Status: StatusOK, Message: fmt.Sprintf("the porch API is available on %s", config.Host)}}
+
+	namespace, _, err := c.flags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return append(results, Result{Name: "porch RBAC", Status: StatusError, Message: err.Error()})
+	}
+	var denied []string
+	for _, rule := range porchRules {
+		rule := rule
+		rule.Namespace = namespace
+		review, err := cs.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &rule},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return append(results, Result{
+				Name:    "porch RBAC",
+				Status:  StatusWarning,
+				Message: fmt.Sprintf("unable to check permissions: %s", firstLine(err.Error())),
+			})
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, describeRule(rule))
+		}
+	}
+	if len(denied) > 0 {
+		return append(results, Result{
+			Name:        "porch RBAC",
+			Status:      StatusWarning,
+			Message:     fmt.Sprintf("missing permissions in namespace %q: %s", namespace, strings.Join(denied, ", ")),
+			Remediation: "Ask your cluster administrator to grant these permissions, or use --namespace to select a namespace you have access to.",
+		})
+	}
+	return append(results, Result{
+		Name:    "porch RBAC",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("all permissions needed by the rpkg and repo commands are granted in namespace %q", namespace),
+	})
+}
+
+// clusterConfigured returns true if a kubeconfig context or an API server
+// is set, since the client otherwise falls back to localhost.
+func (c *checker) clusterConfigured() bool {
+	if c.flags.APIServer != nil && *c.flags.APIServer != "" {
+		return true
+	}
+	raw, err := c.flags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return false
+	}
+	if c.flags.Context != nil && *c.flags.Context != "" {
+		return true
+	}
+	return raw.CurrentContext != ""
+}
+
+// describeRule returns the rule in the form `verb resource[/subresource]`.
+func describeRule(rule authorizationv1.ResourceAttributes) string {
+	resource := rule.Resource
+	if rule.Subresource != "" {
+		resource += "/" + rule.Subresource
+	}
+	return rule.Verb + " " + resource
+}
+
+func runCommand(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%w: %s", err, msg)
+		}
+		return stdout.String(), err
+	}
+	return stdout.String(), nil
+}
+
+func firstLine(s string) string {
+	l, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return l
+}
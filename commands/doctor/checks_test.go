@@ -0,0 +1,261 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
+	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestParseGitVersion(t *testing.T) {
+	testCases := map[string]struct {
+		out      string
+		expected string
+		ok       bool
+	}{
+		"linux":    {out: "git version 2.39.2\n", expected: "v2.39.2", ok: true},
+		"windows":  {out: "git version 2.42.0.windows.2\n", expected: "v2.42.0", ok: true},
+		"apple":    {out: "git version 2.37.1 (Apple Git-137.1)\n", expected: "v2.37.1", ok: true},
+		"no patch": {out: "git version 2.8\n", expected: "v2.8.0", ok: true},
+		"invalid":  {out: "hub version 2.14.2\n"},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			v, ok := parseGitVersion(tc.out)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
+
+// fakeCommands returns a runCommand function returning the output for the
+// given command lines, and failing for any other command.
+func fakeCommands(outputs map[string]string) func(context.Context, string, ...string) (string, error) {
+	return func(_ context.Context, name string, args ...string) (string, error) {
+		cmd := strings.Join(append([]string{name}, args...), " ")
+		if out, found := outputs[cmd]; found {
+			return out, nil
+		}
+		return "", fmt.Errorf("exit status 1")
+	}
+}
+
+func TestCheckGit(t *testing.T) {
+	testCases := map[string]struct {
+		outputs  map[string]string
+		expected Status
+	}{
+		"supported":   {outputs: map[string]string{"git version": "git version 2.39.2\n"}, expected: StatusOK},
+		"unsupported": {outputs: map[string]string{"git version": "git version 1.8.3.1\n"}, expected: StatusError},
+		"missing":     {expected: StatusError},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			c := &checker{runCommand: fakeCommands(tc.outputs)}
+			res := c.checkGit(context.Background())
+			assert.Equal(t, tc.expected, res.Status)
+			if tc.expected != StatusOK {
+				assert.NotEmpty(t, res.Remediation)
+			}
+		})
+	}
+}
+
+func TestCheckGitCredentialHelper(t *testing.T) {
+	c := &checker{runCommand: fakeCommands(map[string]string{
+		"git config --get-all credential.helper": "\nosxkeychain\nstore\n",
+	})}
+	assert.Equal(t, Result{
+		Name:    "git credential helper",
+		Status:  StatusOK,
+		Message: "osxkeychain, store",
+	}, c.checkGitCredentialHelper(context.Background()))
+
+	c = &checker{runCommand: fakeCommands(nil)}
+	res := c.checkGitCredentialHelper(context.Background())
+	assert.Equal(t, StatusWarning, res.Status)
+	assert.NotEmpty(t, res.Remediation)
+}
+
+func TestCheckContainerRuntime(t *testing.T) {
+	if !fnruntime.HasFnImages() {
+		t.Skip("no function images for this architecture")
+	}
+	t.Setenv(fnruntime.ContainerRuntimeEnv, "")
+
+	c := &checker{
+		runtimeAvailable: func(fnruntime.ContainerRuntime) error { return nil },
+		lookPath:         func(file string) (string, error) { return "/usr/bin/" + file, nil },
+	}
+	assert.Equal(t, Result{Name: "container runtime", Status: StatusOK, Message: "docker is available"},
+		c.checkContainerRuntime(context.Background()))
+
+	// the runtime found in PATH is checked if the env var isn't set.
+	c = &checker{
+		runtimeAvailable: func(fnruntime.ContainerRuntime) error { return nil },
+		lookPath: func(file string) (string, error) {
+			if file == "nerdctl" {
+				return "/usr/bin/nerdctl", nil
+			}
+			return "", errors.New("not found")
+		},
+	}
+	assert.Equal(t, Result{Name: "container runtime", Status: StatusOK, Message: "nerdctl is available"},
+		c.checkContainerRuntime(context.Background()))
+
+	// other runtimes found in PATH are suggested if the configured one is
+	// not available.
+	t.Setenv(fnruntime.ContainerRuntimeEnv, "docker")
+	c = &checker{
+		runtimeAvailable: func(fnruntime.ContainerRuntime) error { return errors.New("docker must be running") },
+		lookPath: func(file string) (string, error) {
+			if file == "podman" {
+				return "/usr/bin/podman", nil
+			}
+			return "", errors.New("not found")
+		},
+	}
+	assert.Equal(t, Result{
+		Name:        "container runtime",
+		Status:      StatusError,
+		Message:     "docker is not available: docker must be running",
+		Remediation: "Install and start docker to run container functions. Alternatively, use podman by setting KPT_FN_RUNTIME=podman.",
+	}, c.checkContainerRuntime(context.Background()))
+
+	t.Setenv(fnruntime.ContainerRuntimeEnv, "rkt")
+	assert.Equal(t, StatusError, c.checkContainerRuntime(context.Background()).Status)
+}
+
+func TestCheckRegistry(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/", r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer s.Close()
+
+	c := &checker{httpClient: s.Client(), timeout: time.Second}
+	registry := strings.TrimPrefix(s.URL, "https://")
+	assert.Equal(t, Result{
+		Name:    "registry " + registry,
+		Status:  StatusOK,
+		Message: registry + " is reachable (401 Unauthorized)",
+	}, c.checkRegistry(context.Background(), registry))
+
+	s.Close()
+	res := c.checkRegistry(context.Background(), registry)
+	assert.Equal(t, StatusWarning, res.Status)
+	assert.NotEmpty(t, res.Remediation)
+}
+
+func TestCheckPorchSkipped(t *testing.T) {
+	flags := genericclioptions.NewConfigFlags(false)
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	flags.KubeConfig = &kubeconfig
+
+	c := &checker{flags: flags}
+	results := c.checkPorch(context.Background())
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, StatusSkipped, results[0].Status)
+	}
+}
+
+func TestCmd(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		status   Status
+		expected string
+		err      string
+	}{
+		"ok": {
+			status: StatusOK,
+			expected: `[OK] git: git v2.39.2
+[OK] git credential helper: store
+
+2 ok, 0 warnings, 0 errors, 0 skipped
+`,
+		},
+		"error": {
+			status: StatusError,
+			expected: `[ERROR] git: git is not available: exit status 1
+    Install git from https://git-scm.com/downloads and make sure it is on the PATH.
+[WARNING] git credential helper: no git credential helper is configured
+    Configure a credential helper to fetch packages from private repositories, e.g. ` + "`git config --global credential.helper cache`" + `.
+
+0 ok, 1 warnings, 1 errors, 0 skipped
+`,
+			err: "1 of 2 checks failed",
+		},
+		"json": {
+			args:   []string{"-o", "json"},
+			status: StatusOK,
+			expected: `{
+  "apiVersion": "kpt.dev/v1",
+  "kind": "CommandResult",
+  "command": "doctor",
+  "exitCode": 0,
+  "data": [
+    {
+      "name": "git",
+      "status": "ok",
+      "message": "git v2.39.2"
+    },
+    {
+      "name": "git credential helper",
+      "status": "ok",
+      "message": "store"
+    }
+  ]
+}
+`,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			outputs := map[string]string{}
+			if tc.status == StatusOK {
+				outputs["git version"] = "git version 2.39.2"
+				outputs["git config --get-all credential.helper"] = "store"
+			}
+			out := &bytes.Buffer{}
+			r := newRunner(fake.CtxWithPrinter(out, &bytes.Buffer{}), "test")
+			c := &checker{runCommand: fakeCommands(outputs)}
+			c.checks = []func(context.Context) []Result{single(c.checkGit), single(c.checkGitCredentialHelper)}
+			r.checker = c
+			r.Command.SilenceErrors = true
+			r.Command.SilenceUsage = true
+			r.Command.SetArgs(tc.args)
+			err := r.Command.Execute()
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doctor contains the `kpt doctor` command, which diagnoses the
+// environment kpt runs in.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/internal/docs/generated/doctordocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+const command = "cmddoctor"
+
+func newRunner(ctx context.Context, version string) *runner {
+	r := &runner{
+		ctx:   ctx,
+		flags: genericclioptions.NewConfigFlags(true),
+	}
+	c := &cobra.Command{
+		Use:     "doctor [flags]",
+		Short:   doctordocs.DoctorShort,
+		Long:    doctordocs.DoctorShort + "\n" + doctordocs.DoctorLong,
+		Example: doctordocs.DoctorExamples,
+		Args:    cobra.NoArgs,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+	r.flags.AddFlags(c.Flags())
+	r.flags.WrapConfigFn = func(rc *rest.Config) *rest.Config {
+		rc.UserAgent = fmt.Sprintf("kpt/%s", version)
+		return rc
+	}
+	c.Flags().StringSliceVar(&r.registries, "registries", DefaultRegistries,
+		"container registries to check for reachability")
+	c.Flags().DurationVar(&r.timeout, "check-timeout", 5*time.Second,
+		"timeout of each network check")
+	c.Flags().StringVarP(&r.output, "output", "o", "",
+		"write the results to stdout in a machine-readable format: "+output.SupportedFormatsLabel())
+	r.Command = c
+	return r
+}
+
+func NewCommand(ctx context.Context, version string) *cobra.Command {
+	return newRunner(ctx, version).Command
+}
+
+type runner struct {
+	ctx          context.Context
+	flags        *genericclioptions.ConfigFlags
+	registries   []string
+	timeout      time.Duration
+	output       string
+	outputFormat output.Format
+	Command      *cobra.Command
+
+	// checker is set in tests.
+	checker *checker
+}
+
+func (r *runner) preRunE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".preRunE"
+	var err error
+	r.outputFormat, err = output.ParseFormat(r.output)
+	if err != nil {
+		return errors.E(op, errors.InvalidParam, err)
+	}
+	if r.checker == nil {
+		r.checker = newChecker(r.flags, r.registries, r.timeout)
+	}
+	return nil
+}
+
+func (r *runner) runE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = command + ".runE"
+	results := r.checker.Run(r.ctx)
+
+	var err error
+	if failed := countStatus(results, StatusError); failed > 0 {
+		err = errors.E(op, fmt.Errorf("%d of %d checks failed", failed, len(results)))
+	}
+	out := printer.FromContextOrDie(r.ctx).OutStream()
+	if r.outputFormat != output.None {
+		return output.Write(out, r.outputFormat, "doctor", "", results, err)
+	}
+	if werr := writeResults(out, results); werr != nil {
+		return errors.E(op, werr)
+	}
+	return err
+}
+
+// writeResults writes the results with their remediation steps.
+func writeResults(w io.Writer, results []Result) error {
+	for _, res := range results {
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", strings.ToUpper(string(res.Status)), res.Name, res.Message); err != nil {
+			return err
+		}
+		if res.Remediation != "" {
+			if _, err := fmt.Fprintf(w, "    %s\n", res.Remediation); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d ok, %d warnings, %d errors, %d skipped\n",
+		countStatus(results, StatusOK), countStatus(results, StatusWarning),
+		countStatus(results, StatusError), countStatus(results, StatusSkipped))
+	return err
+}
+
+func countStatus(results []Result, status Status) int {
+	n := 0
+	for _, res := range results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
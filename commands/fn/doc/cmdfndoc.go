@@ -19,12 +19,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/fndocs"
 	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/spf13/cobra"
 )
@@ -43,6 +43,8 @@ func NewRunner(ctx context.Context, parent string) *Runner {
 	}
 	r.Command = c
 	c.Flags().StringVarP(&r.Image, "image", "i", "", "kpt function image name")
+	c.Flags().StringVarP(&r.Output, "output", "o", "",
+		"write the usage of the function to stdout in a machine-readable format: "+output.SupportedFormatsLabel())
 	_ = r.Command.RegisterFlagCompletionFunc("image", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return cmdutil.SuggestFunctions(cmd), cobra.ShellCompDirectiveDefault
 	})
@@ -56,19 +58,50 @@ func NewCommand(ctx context.Context, parent string) *cobra.Command {
 
 type Runner struct {
 	Image   string
+	Output  string
 	Command *cobra.Command
 	Ctx     context.Context
 }
 
+// Usage is the machine-readable output of `fn doc`.
+type Usage struct {
+	// Image is the resolved image of the function.
+	Image string `json:"image"`
+	// Help is the output of the function for the --help flag.
+	Help string `json:"help"`
+}
+
 func (r *Runner) runE(c *cobra.Command, _ []string) error {
+	format, err := output.ParseFormat(r.Output)
+	if err != nil {
+		return err
+	}
 	if r.Image == "" {
 		return errors.New("image must be specified")
 	}
-	// TODO: We probably should be going through the runner
-	image, err := fnruntime.ResolveToImageForCLI(c.Context(), r.Image)
+	pr := printer.FromContextOrDie(r.Ctx)
+	usage, err := r.usage(c.Context())
+	if format != output.None {
+		var data interface{}
+		if usage != nil {
+			data = usage
+		}
+		return output.Write(pr.OutStream(), format, "fn doc", "", data, err)
+	}
 	if err != nil {
 		return err
 	}
+	fmt.Fprintln(pr.OutStream(), usage.Help)
+	return nil
+}
+
+// usage runs the function image with the --help flag.
+func (r *Runner) usage(ctx context.Context) (*Usage, error) {
+	// TODO: We probably should be going through the runner
+	image, err := fnruntime.ResolveToImageForCLI(ctx, r.Image)
+	if err != nil {
+		return nil, err
+	}
 	var out, errout bytes.Buffer
 	dockerRunArgs := []string{
 		"run",
@@ -76,26 +109,23 @@ func (r *Runner) runE(c *cobra.Command, _ []string) error {
 		image,
 		"--help",
 	}
-	// If the env var is empty, stringToContainerRuntime defaults it to docker.
-	runtime, err := fnruntime.StringToContainerRuntime(os.Getenv(fnruntime.ContainerRuntimeEnv))
+	runtime, err := fnruntime.GetContainerRuntime()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = fnruntime.ContainerRuntimeAvailable(runtime)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	cmd := exec.Command(runtime.GetBin(), dockerRunArgs...)
 	cmd.Stdout = &out
 	cmd.Stderr = &errout
 	err = cmd.Run()
-	pr := printer.FromContextOrDie(r.Ctx)
 	if err != nil {
-		pr.Printf(errout.String())
-		return fmt.Errorf("please ensure the container has an entrypoint and it supports --help flag: %w", err)
+		printer.FromContextOrDie(r.Ctx).Printf(errout.String())
+		return nil, fmt.Errorf("please ensure the container has an entrypoint and it supports --help flag: %w", err)
 	}
-	fmt.Fprintln(pr.OutStream(), out.String())
-	return nil
+	return &Usage{Image: image, Help: out.String()}, nil
 }
@@ -27,6 +27,7 @@ import (
 func TestFnDoc(t *testing.T) {
 	type testcase struct {
 		image     string
+		output    string
 		expectErr string
 	}
 	testcases := []testcase{
@@ -41,12 +42,18 @@ func TestFnDoc(t *testing.T) {
 			image:     "",
 			expectErr: "image must be specified",
 		},
+		{
+			image:     "gcr.io/kpt-fn/set-namespace:v0.1.1",
+			output:    "xml",
+			expectErr: `invalid output "xml": supported outputs are: json, yaml`,
+		},
 	}
 
 	for _, tc := range testcases {
 		b := &bytes.Buffer{}
 		runner := doc.NewRunner(fake.CtxWithPrinter(b, b), "kpt")
 		runner.Image = tc.image
+		runner.Output = tc.output
 		err := runner.Command.Execute()
 		if tc.expectErr == "" {
 			testutil.AssertNoError(t, err)
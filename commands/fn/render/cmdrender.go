@@ -26,6 +26,7 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	"github.com/GoogleContainerTools/kpt/internal/util/pathutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/render"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
@@ -50,6 +51,9 @@ func NewRunner(ctx context.Context, parent string) *Runner {
 		"path to a directory to save function results")
 	c.Flags().StringVarP(&r.dest, "output", "o", "",
 		fmt.Sprintf("output resources are written to provided location. Allowed values: %s|%s|<OUT_DIR_PATH>", cmdutil.Stdout, cmdutil.Unwrap))
+	c.Flags().StringVar(&r.resultsFormat, "results-format", "",
+		fmt.Sprintf("write the function results to stdout in a machine-readable format. Allowed values: %s|%s|%s",
+			output.JSON, output.YAML, output.SARIF))
 
 	c.Flags().Var(&r.RunnerOptions.ImagePullPolicy, "image-pull-policy",
 		"pull image before running the container "+r.RunnerOptions.ImagePullPolicy.HelpAllowedValues())
@@ -57,12 +61,31 @@ func NewRunner(ctx context.Context, parent string) *Runner {
 		return r.RunnerOptions.ImagePullPolicy.AllStrings(), cobra.ShellCompDirectiveDefault
 	})
 
+	c.Flags().Var(&r.RunnerOptions.Runtime, "runtime",
+		"container runtime running container functions "+r.RunnerOptions.Runtime.HelpAllowedValues()+
+			". Defaults to the "+fnruntime.ContainerRuntimeEnv+" env var, or the first runtime found in PATH.")
+	_ = c.RegisterFlagCompletionFunc("runtime", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return r.RunnerOptions.Runtime.AllStrings(), cobra.ShellCompDirectiveDefault
+	})
+
 	c.Flags().BoolVar(&r.RunnerOptions.AllowExec, "allow-exec", r.RunnerOptions.AllowExec,
 		"allow binary executable to be run during pipeline execution.")
+	c.Flags().BoolVar(&r.RunnerOptions.ResolveExecToPkg, "resolve-exec-to-pkg", r.RunnerOptions.ResolveExecToPkg,
+		"resolve relative paths of binary executables against the package declaring the function.")
 	c.Flags().BoolVar(&r.RunnerOptions.AllowNetwork, "allow-network", false,
 		"allow functions to access network during pipeline execution.")
 	c.Flags().BoolVar(&r.RunnerOptions.AllowWasm, "allow-alpha-wasm", r.RunnerOptions.AllowWasm,
 		"allow wasm to be used during pipeline execution.")
+	c.Flags().BoolVar(&r.RunnerOptions.PreferBuiltin, "prefer-builtin", r.RunnerOptions.PreferBuiltin,
+		"execute catalog functions with a builtin implementation compatible with their version without containers. "+
+			"Defaults to the "+fnruntime.PreferBuiltinEnv+" env var.")
+	c.Flags().BoolVar(&r.allowClusterAccess, "allow-cluster-access", false,
+		"allow functions declaring cluster access to read from the cluster of the current kubeconfig context.")
+	c.Flags().BoolVar(&r.report, "report", false,
+		"write the wall-clock time of each function, and the image pull time and image cache status of container functions, "+
+			"to "+fnruntime.ReportFileName+" in the results directory. Requires --results-dir.")
+	c.Flags().IntVar(&r.parallel, "parallel", 1,
+		"maximum number of package pipelines to run concurrently. Sibling subpackages are rendered in parallel if greater than 1.")
 	cmdutil.FixDocs("kpt", parent, c)
 	r.Command = c
 	return r
@@ -77,10 +100,22 @@ type Runner struct {
 	pkgPath        string
 	resultsDirPath string
 	dest           string
+	resultsFormat  string
 	Command        *cobra.Command
 	ctx            context.Context
 
 	RunnerOptions fnruntime.RunnerOptions
+
+	// allowClusterAccess gives functions declaring cluster access a
+	// kubeconfig for the current context.
+	allowClusterAccess bool
+
+	// parallel is the maximum number of package pipelines to run
+	// concurrently.
+	parallel int
+
+	// report writes the render report to the results directory.
+	report bool
 }
 
 func (r *Runner) InitDefaults() {
@@ -104,11 +139,26 @@ func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if r.resultsFormat != "" {
+		if !output.IsFnResultsFormat(r.resultsFormat) {
+			return fmt.Errorf("invalid --results-format %q: must be one of %s, %s, %s",
+				r.resultsFormat, output.JSON, output.YAML, output.SARIF)
+		}
+		if r.dest == cmdutil.Stdout || r.dest == cmdutil.Unwrap {
+			return fmt.Errorf("--results-format cannot be used with --output %s", r.dest)
+		}
+	}
 	if r.dest != "" && r.dest != cmdutil.Stdout && r.dest != cmdutil.Unwrap {
 		if err := cmdutil.CheckDirectoryNotPresent(r.dest); err != nil {
 			return err
 		}
 	}
+	if r.parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	if r.report && r.resultsDirPath == "" {
+		return fmt.Errorf("--report requires --results-dir")
+	}
 	if r.resultsDirPath != "" {
 		err := os.MkdirAll(r.resultsDirPath, 0755)
 		if err != nil {
@@ -119,25 +169,54 @@ func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
 }
 
 func (r *Runner) runE(_ *cobra.Command, _ []string) error {
-	var output io.Writer
+	var out io.Writer
 	outContent := bytes.Buffer{}
 	if r.dest != "" {
 		// this means the output should be written to another destination
 		// capture the content to be written
-		output = &outContent
+		out = &outContent
 	}
 	absPkgPath, _, err := pathutil.ResolveAbsAndRelPaths(r.pkgPath)
 	if err != nil {
 		return err
 	}
+	if r.allowClusterAccess {
+		kubeconfig, cleanup, err := fnruntime.WriteClusterKubeconfig()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		r.RunnerOptions.ClusterKubeconfig = kubeconfig
+	}
+	if r.report {
+		// the report is written before the functions run, so it exists
+		// even if the render fails early.
+		report := fnruntime.NewReport(filesys.FileSystemOrOnDisk{}, r.resultsDirPath, absPkgPath)
+		if err := report.Write(); err != nil {
+			return fmt.Errorf("failed to write the render report: %w", err)
+		}
+		r.RunnerOptions.Report = report
+	}
 	executor := render.Renderer{
 		PkgPath:        absPkgPath,
 		ResultsDirPath: r.resultsDirPath,
-		Output:         output,
+		Output:         out,
 		RunnerOptions:  r.RunnerOptions,
 		FileSystem:     filesys.FileSystemOrOnDisk{},
+		Parallel:       r.parallel,
+	}
+	results, err := executor.Execute(r.ctx)
+	if r.resultsFormat != "" {
+		// the result is written to stdout whether the rendering failed
+		// or not.
+		if output.Format(r.resultsFormat) == output.SARIF {
+			err = output.WriteSARIF(printer.FromContextOrDie(r.ctx).OutStream(), absPkgPath, results, err)
+		} else {
+			err = output.Write(printer.FromContextOrDie(r.ctx).OutStream(), output.Format(r.resultsFormat),
+				"fn render", absPkgPath, output.FnResultsData(results), err)
+		}
 	}
-	if _, err := executor.Execute(r.ctx); err != nil {
+	if err != nil {
 		return err
 	}
 
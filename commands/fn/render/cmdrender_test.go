@@ -17,6 +17,7 @@ package render
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/GoogleContainerTools/kpt/internal/testutil"
@@ -43,5 +44,26 @@ func TestCmd_flagAndArgParsing_Symlink(t *testing.T) {
 	assert.Equal(t, filepath.Join("path", "to", "pkg", "dir"), r.pkgPath)
 }
 
+func TestCmd_resultsFormat(t *testing.T) {
+	dir := t.TempDir()
+	for args, wantErr := range map[string]string{
+		"--results-format=table":          `invalid --results-format "table": must be one of json, yaml, sarif`,
+		"--results-format=json -o unwrap": "--results-format cannot be used with --output unwrap",
+		"--results-format=sarif -o out":   "",
+	} {
+		r := NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+		r.Command.RunE = NoOpRunE
+		r.Command.SetArgs(append([]string{dir}, strings.Fields(args)...))
+		err := r.Command.Execute()
+		if wantErr == "" {
+			assert.NoError(t, err, args)
+			continue
+		}
+		if assert.Error(t, err, args) {
+			assert.Equal(t, wantErr, err.Error(), args)
+		}
+	}
+}
+
 // NoOpRunE is a noop function to replace the run function of a command.  Useful for testing argument parsing.
 var NoOpRunE = func(cmd *cobra.Command, args []string) error { return nil }
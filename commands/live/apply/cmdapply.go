@@ -15,9 +15,12 @@
 package apply
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"text/tabwriter"
 	"time"
 
 	alphaprinterstable "github.com/GoogleContainerTools/kpt/internal/alpha/printers/table"
@@ -90,6 +93,8 @@ func NewRunner(
 	c.Flags().StringVar(&r.statusPolicyString, "status-policy", "all",
 		"It determines which status information should be saved in the inventory (if compatible). Available options "+
 			fmt.Sprintf("%q and %q.", "all", "none"))
+	c.Flags().StringSliceVar(&r.contexts, "contexts", nil,
+		"Comma separated list of kubeconfig contexts to apply the package to, one cluster after the other.")
 	return r
 }
 
@@ -104,8 +109,11 @@ type Runner struct {
 	alpha      bool
 	Command    *cobra.Command
 	PreProcess func(info inventory.Info, strategy common.DryRunStrategy) (inventory.Policy, error)
-	ioStreams  genericclioptions.IOStreams
-	factory    util.Factory
+	// ContextFactory returns the factory for a kubeconfig context. It is
+	// required for --contexts.
+	ContextFactory func(kubeContext string) util.Factory
+	ioStreams      genericclioptions.IOStreams
+	factory        util.Factory
 
 	installCRD                   bool
 	serverSideOptions            common.ServerSideOptions
@@ -117,6 +125,7 @@ type Runner struct {
 	dryRun                       bool
 	printStatusEvents            bool
 	statusPolicyString           string
+	contexts                     []string
 
 	inventoryPolicy inventory.Policy
 	prunePropPolicy metav1.DeletionPropagation
@@ -147,12 +156,32 @@ func (r *Runner) preRunE(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("unknown output type %q", r.output)
 	}
 
+	if !r.serverSideOptions.ServerSideApply {
+		if r.serverSideOptions.ForceConflicts {
+			return fmt.Errorf("--force-conflicts only works with --server-side")
+		}
+		if cmd.Flags().Changed("field-manager") {
+			return fmt.Errorf("--field-manager only works with --server-side")
+		}
+	}
+
 	// We default the install-resource-group flag to false if we are doing
 	// dry-run, unless the user has explicitly used the install-resource-group flag.
 	if r.dryRun && !cmd.Flags().Changed("install-resource-group") {
 		r.installCRD = false
 	}
 
+	if len(r.contexts) > 0 {
+		if r.ContextFactory == nil {
+			return fmt.Errorf("--contexts is not supported by this command")
+		}
+		if cmd.Flags().Changed("context") {
+			return fmt.Errorf("--context and --contexts cannot be used together")
+		}
+		// the ResourceGroup CRD is verified in each cluster when applying.
+		return nil
+	}
+
 	if !r.installCRD {
 		err := cmdutil.VerifyResourceGroupCRD(r.factory)
 		if err != nil {
@@ -180,7 +209,16 @@ func (r *Runner) runE(c *cobra.Command, args []string) error {
 		}
 	}
 
-	objs, inv, err := live.Load(r.factory, path, c.InOrStdin())
+	if len(r.contexts) > 0 {
+		return r.applyContexts(path, c.InOrStdin())
+	}
+	return r.apply(path, c.InOrStdin())
+}
+
+// apply applies the package at path, or read from in if path is "-", to
+// the cluster of the factory of the runner.
+func (r *Runner) apply(path string, in io.Reader) error {
+	objs, inv, err := live.Load(r.factory, path, in)
 	if err != nil {
 		return err
 	}
@@ -216,6 +254,67 @@ func (r *Runner) runE(c *cobra.Command, args []string) error {
 	return r.applyRunner(r, invInfo, objs, dryRunStrategy)
 }
 
+// applyContexts applies the package to the cluster of each context of
+// --contexts. Each cluster gets its own copy of the inventory object. A
+// failure in one cluster doesn't stop the others, and the outcome in every
+// cluster is summarized at the end.
+func (r *Runner) applyContexts(path string, in io.Reader) error {
+	// the package is read once per cluster, so stdin has to be buffered.
+	var stdin []byte
+	if path == "-" {
+		var err error
+		stdin, err = io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+	}
+
+	// keep stdout a stream of JSON events with the json output.
+	out := r.ioStreams.Out
+	if r.output == printers.JSONPrinter {
+		out = r.ioStreams.ErrOut
+	}
+
+	errs := make([]error, len(r.contexts))
+	var failed int
+	for i, kubeContext := range r.contexts {
+		fmt.Fprintf(out, "Applying to context %q:\n", kubeContext)
+		cr := *r
+		cr.factory = r.ContextFactory(kubeContext)
+		errs[i] = func() error {
+			if !cr.installCRD {
+				if err := cmdutil.VerifyResourceGroupCRD(cr.factory); err != nil {
+					return err
+				}
+			}
+			return cr.apply(path, bytes.NewReader(stdin))
+		}()
+		if errs[i] != nil {
+			failed++
+			fmt.Fprintf(out, "error: %v\n", errs[i])
+		}
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tSTATUS")
+	for i, kubeContext := range r.contexts {
+		result := "Succeeded"
+		if errs[i] != nil {
+			result = "Failed"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", kubeContext, result)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("apply failed in %d of %d contexts", failed, len(r.contexts))
+	}
+	return nil
+}
+
 func runApply(r *Runner, invInfo inventory.Info, objs []*unstructured.Unstructured,
 	dryRunStrategy common.DryRunStrategy) error {
 	if r.installCRD {
@@ -15,6 +15,7 @@
 package apply
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
 
@@ -26,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 )
@@ -68,6 +70,46 @@ func TestCmd(t *testing.T) {
 			},
 			expectedErrorMsg: "unknown output type \"foo\"",
 		},
+		"force-conflicts without server-side": {
+			args: []string{
+				"--force-conflicts",
+			},
+			namespace: "testns",
+			applyCallbackFunc: func(t *testing.T, _ *Runner, _ inventory.Info) {
+				t.FailNow()
+			},
+			expectedErrorMsg: "--force-conflicts only works with --server-side",
+		},
+		"field-manager without server-side": {
+			args: []string{
+				"--field-manager", "platform-team",
+			},
+			namespace: "testns",
+			applyCallbackFunc: func(t *testing.T, _ *Runner, _ inventory.Info) {
+				t.FailNow()
+			},
+			expectedErrorMsg: "--field-manager only works with --server-side",
+		},
+		"server-side apply with conflict handling": {
+			args: []string{
+				"--server-side",
+				"--force-conflicts",
+				"--field-manager", "platform-team",
+			},
+			inventory: &kptfilev1.Inventory{
+				Namespace:   "my-ns",
+				Name:        "my-name",
+				InventoryID: "my-inv-id",
+			},
+			namespace: "testns",
+			applyCallbackFunc: func(t *testing.T, r *Runner, _ inventory.Info) {
+				assert.Equal(t, common.ServerSideOptions{
+					ServerSideApply: true,
+					ForceConflicts:  true,
+					FieldManager:    "platform-team",
+				}, r.serverSideOptions)
+			},
+		},
 		"fetches the correct inventory information from the Kptfile": {
 			args: []string{
 				"--inventory-policy", "adopt",
@@ -167,3 +209,68 @@ func TestCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestCmd_contexts(t *testing.T) {
+	factories := map[string]*cmdtesting.TestFactory{}
+	for _, kubeContext := range []string{"east", "west"} {
+		tf := cmdtesting.NewTestFactory().WithNamespace("testns")
+		defer tf.Cleanup()
+		factories[kubeContext] = tf
+	}
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	w, clean := testutil.SetupWorkspace(t)
+	defer clean()
+	kf := kptfileutil.DefaultKptfile(filepath.Base(w.WorkspaceDirectory))
+	kf.Inventory = &kptfilev1.Inventory{
+		Namespace:   "my-ns",
+		Name:        "my-name",
+		InventoryID: "my-inv-id",
+	}
+	testutil.AddKptfileToWorkspace(t, w, kf)
+
+	revert := testutil.Chdir(t, w.WorkspaceDirectory)
+	defer revert()
+
+	runner := NewRunner(fake.CtxWithDefaultPrinter(), cmdtesting.NewTestFactory(), ioStreams, false)
+	runner.ContextFactory = func(kubeContext string) util.Factory {
+		return factories[kubeContext]
+	}
+	runner.Command.SetArgs([]string{"--contexts", "east,west"})
+	var applied []util.Factory
+	runner.applyRunner = func(r *Runner, inv inventory.Info,
+		_ []*unstructured.Unstructured, _ common.DryRunStrategy) error {
+		assert.Equal(t, "my-inv-id", inv.ID())
+		applied = append(applied, r.factory)
+		if r.factory == factories["west"] {
+			return fmt.Errorf("cluster unreachable")
+		}
+		return nil
+	}
+	err := runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Equal(t, "apply failed in 1 of 2 contexts", err.Error())
+	}
+	assert.Equal(t, []util.Factory{factories["east"], factories["west"]}, applied)
+	assert.Equal(t, `Applying to context "east":
+Applying to context "west":
+error: cluster unreachable
+
+CONTEXT  STATUS
+east     Succeeded
+west     Failed
+`, out.String())
+}
+
+func TestCmd_contextsNotSupported(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("testns")
+	defer tf.Cleanup()
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams() //nolint:dogsled
+
+	runner := NewRunner(fake.CtxWithDefaultPrinter(), tf, ioStreams, false)
+	runner.Command.SetArgs([]string{"--contexts", "east,west"})
+	err := runner.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--contexts is not supported by this command")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/livedocs"
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
@@ -25,6 +26,7 @@ import (
 	"github.com/GoogleContainerTools/kpt/pkg/live"
 	"github.com/GoogleContainerTools/kpt/pkg/status"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/cli-utils/cmd/flagutils"
@@ -64,6 +66,10 @@ func NewRunner(
 		"dry-run apply for the resources in the package.")
 	c.Flags().BoolVar(&r.printStatusEvents, "show-status-events", false,
 		"Print status events (always enabled for table output)")
+	c.Flags().StringVar(&r.deletePropagationPolicyString, "delete-propagation-policy",
+		"Background", "Propagation policy for deleting resources")
+	c.Flags().DurationVar(&r.deleteTimeout, "delete-timeout", time.Duration(0),
+		"Timeout threshold for waiting for each wave of deleted resources to be removed from the cluster")
 	c.Flags().StringVar(&r.statusPolicyString, "status-policy", "all",
 		"It determines which status information should be saved in the inventory (if compatible). Available options "+
 			fmt.Sprintf("%q and %q.", "all", "none"))
@@ -91,8 +97,12 @@ type Runner struct {
 	printStatusEvents     bool
 	statusPolicyString    string
 
-	inventoryPolicy inventory.Policy
-	statusPolicy    inventory.StatusPolicy
+	deletePropagationPolicyString string
+	deleteTimeout                 time.Duration
+
+	inventoryPolicy  inventory.Policy
+	statusPolicy     inventory.StatusPolicy
+	deletePropPolicy metav1.DeletionPropagation
 
 	// TODO(mortent): This is needed for now since we don't have a good way to
 	// stub out the Destroyer with an interface for testing purposes.
@@ -110,6 +120,10 @@ func (r *Runner) preRunE(_ *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	r.deletePropPolicy, err = flagutils.ConvertPropagationPolicy(r.deletePropagationPolicyString)
+	if err != nil {
+		return err
+	}
 
 	if found := printers.ValidatePrinterType(r.output); !found {
 		return fmt.Errorf("unknown output type %q", r.output)
@@ -188,9 +202,11 @@ func runDestroy(r *Runner, inv inventory.Info, dryRunStrategy common.DryRunStrat
 	}
 
 	options := apply.DestroyerOptions{
-		InventoryPolicy:  r.inventoryPolicy,
-		DryRunStrategy:   dryRunStrategy,
-		EmitStatusEvents: true,
+		InventoryPolicy:         r.inventoryPolicy,
+		DryRunStrategy:          dryRunStrategy,
+		DeleteTimeout:           r.deleteTimeout,
+		DeletePropagationPolicy: r.deletePropPolicy,
+		EmitStatusEvents:        true,
 	}
 	ch := destroyer.Run(context.Background(), inv, options)
 
@@ -17,12 +17,14 @@ package destroy
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/GoogleContainerTools/kpt/internal/testutil"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
 	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 	"sigs.k8s.io/cli-utils/pkg/common"
@@ -34,7 +36,7 @@ func TestCmd(t *testing.T) {
 		args                []string
 		namespace           string
 		inventory           *kptfilev1.Inventory
-		destroyCallbackFunc func(*testing.T, inventory.Info)
+		destroyCallbackFunc func(*testing.T, *Runner, inventory.Info)
 		expectedErrorMsg    string
 	}{
 		"invalid inventory policy": {
@@ -42,7 +44,7 @@ func TestCmd(t *testing.T) {
 				"--inventory-policy", "noSuchPolicy",
 			},
 			namespace: "testns",
-			destroyCallbackFunc: func(t *testing.T, _ inventory.Info) {
+			destroyCallbackFunc: func(t *testing.T, _ *Runner, _ inventory.Info) {
 				t.FailNow()
 			},
 			expectedErrorMsg: "inventory policy must be one of strict, adopt",
@@ -52,7 +54,7 @@ func TestCmd(t *testing.T) {
 				"--status-policy", "noSuchPolicy",
 			},
 			namespace: "testns",
-			destroyCallbackFunc: func(t *testing.T, _ inventory.Info) {
+			destroyCallbackFunc: func(t *testing.T, _ *Runner, _ inventory.Info) {
 				t.FailNow()
 			},
 			expectedErrorMsg: "status policy must be one of none, all",
@@ -62,11 +64,37 @@ func TestCmd(t *testing.T) {
 				"--output", "foo",
 			},
 			namespace: "testns",
-			destroyCallbackFunc: func(t *testing.T, _ inventory.Info) {
+			destroyCallbackFunc: func(t *testing.T, _ *Runner, _ inventory.Info) {
 				t.FailNow()
 			},
 			expectedErrorMsg: "unknown output type \"foo\"",
 		},
+		"invalid delete propagation policy": {
+			args: []string{
+				"--delete-propagation-policy", "noSuchPolicy",
+			},
+			namespace: "testns",
+			destroyCallbackFunc: func(t *testing.T, _ *Runner, _ inventory.Info) {
+				t.FailNow()
+			},
+			expectedErrorMsg: "prune propagation policy must be one of Background, Foreground, Orphan",
+		},
+		"delete propagation policy and timeout": {
+			args: []string{
+				"--delete-propagation-policy", "Foreground",
+				"--delete-timeout", "2m",
+			},
+			inventory: &kptfilev1.Inventory{
+				Namespace:   "my-ns",
+				Name:        "my-name",
+				InventoryID: "my-inv-id",
+			},
+			namespace: "testns",
+			destroyCallbackFunc: func(t *testing.T, r *Runner, _ inventory.Info) {
+				assert.Equal(t, metav1.DeletePropagationForeground, r.deletePropPolicy)
+				assert.Equal(t, 2*time.Minute, r.deleteTimeout)
+			},
+		},
 		"fetches the correct inventory information from the Kptfile": {
 			args: []string{
 				"--inventory-policy", "adopt",
@@ -78,7 +106,7 @@ func TestCmd(t *testing.T) {
 				InventoryID: "my-inv-id",
 			},
 			namespace: "testns",
-			destroyCallbackFunc: func(t *testing.T, inv inventory.Info) {
+			destroyCallbackFunc: func(t *testing.T, _ *Runner, inv inventory.Info) {
 				assert.Equal(t, "my-ns", inv.Namespace())
 				assert.Equal(t, "my-name", inv.Name())
 				assert.Equal(t, "my-inv-id", inv.ID())
@@ -103,8 +131,8 @@ func TestCmd(t *testing.T) {
 
 			runner := NewRunner(fake.CtxWithDefaultPrinter(), tf, ioStreams)
 			runner.Command.SetArgs(tc.args)
-			runner.destroyRunner = func(_ *Runner, inv inventory.Info, _ common.DryRunStrategy) error {
-				tc.destroyCallbackFunc(t, inv)
+			runner.destroyRunner = func(r *Runner, inv inventory.Info, _ common.DryRunStrategy) error {
+				tc.destroyCallbackFunc(t, r, inv)
 				return nil
 			}
 			err := runner.Command.Execute()
@@ -175,6 +175,9 @@ type ConfigureInventoryInfo struct {
 	Name        string
 	InventoryID string
 	RGFileName  string
+	// Namespace of the inventory object. Defaults to the namespace of the
+	// package resources.
+	Namespace string
 
 	Force bool
 }
@@ -184,9 +187,13 @@ func (c *ConfigureInventoryInfo) Run(ctx context.Context) error {
 	const op errors.Op = "cmdliveinit.Run"
 	pr := printer.FromContextOrDie(ctx)
 
-	namespace, err := config.FindNamespace(c.Factory.ToRawKubeConfigLoader(), c.Pkg.UniquePath.String())
-	if err != nil {
-		return errors.E(op, c.Pkg.UniquePath, err)
+	var err error
+	namespace := c.Namespace
+	if namespace == "" {
+		namespace, err = config.FindNamespace(c.Factory.ToRawKubeConfigLoader(), c.Pkg.UniquePath.String())
+		if err != nil {
+			return errors.E(op, c.Pkg.UniquePath, err)
+		}
 	}
 	namespace = strings.TrimSpace(namespace)
 	if !c.Quiet {
@@ -46,14 +46,16 @@ func GetCommand(ctx context.Context, _, version string) *cobra.Command {
 		ErrOut: os.Stderr,
 	}
 
-	f := util.NewFactory(liveCmd, version)
+	f, contextFactory := util.NewFactoryWithContexts(liveCmd, version)
 	invFactory := live.NewClusterClientFactory()
 	loader := status.NewRGInventoryLoader(ctx, f)
 
 	// Init command which updates a Kptfile for the ResourceGroup inventory object.
 	klog.V(2).Infoln("init command updates Kptfile for ResourceGroup inventory")
 	initCmd := initialization.NewCommand(ctx, f, ioStreams)
-	applyCmd := apply.NewCommand(ctx, f, ioStreams, false)
+	applyRunner := apply.NewRunner(ctx, f, ioStreams, false)
+	applyRunner.ContextFactory = contextFactory
+	applyCmd := applyRunner.Command
 	destroyCmd := destroy.NewCommand(ctx, f, ioStreams)
 	statusCmd := status.NewCommand(ctx, f, invFactory, loader)
 	installRGCmd := installrg.NewCommand(ctx, f, ioStreams)
@@ -30,10 +30,12 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/types"
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/pathutil"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	rgfilev1alpha1 "github.com/GoogleContainerTools/kpt/pkg/api/resourcegroup/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
 	"github.com/GoogleContainerTools/kpt/pkg/live"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/klog/v2"
@@ -56,6 +58,7 @@ type Runner struct {
 	dir             string
 	dryRun          bool
 	name            string
+	namespace       string
 	rgFile          string
 	force           bool
 	rgInvClientFunc func(util.Factory) (inventory.Client, error)
@@ -102,6 +105,7 @@ func NewRunner(
 		},
 	}
 	cmd.Flags().StringVar(&r.name, "name", "", "Inventory object name")
+	cmd.Flags().StringVar(&r.namespace, "namespace", "", "Inventory object namespace")
 	cmd.Flags().BoolVar(&r.force, "force", false, "Set inventory values even if already set in Kptfile")
 	cmd.Flags().BoolVar(&r.dryRun, "dry-run", false, "Do not actually migrate, but show steps")
 	cmd.Flags().StringVar(&r.rgFile, "rg-file", rgfilev1alpha1.RGFileName, "The file path to the ResourceGroup object.")
@@ -156,10 +160,13 @@ func (mr *Runner) Run(reader io.Reader, args []string) error {
 
 	// Migrate from Kptfile instead.
 	if mr.cmNotMigrated {
-		return mr.migrateKptfileToRG(args)
+		if err := mr.migrateKptfileToRG(args); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	// Move the ResourceGroup inventory if a new name or namespace is set.
+	return mr.relocateRG()
 }
 
 // applyCRD applies the ResourceGroup custom resource definition, returning an
@@ -267,6 +274,9 @@ func (mr *Runner) migrateObjs(rgInvClient inventory.Client,
 		return err
 	}
 
+	if err := checkInventoryConflict(rgInvClient, invInfo); err != nil {
+		return err
+	}
 	_, err = rgInvClient.Merge(invInfo, cmObjs, mr.dryRunStrategy())
 	if err != nil {
 		return err
@@ -471,6 +481,8 @@ func (mr *Runner) createRGfile(ctx context.Context, args []string, prevID string
 			Pkg:         p,
 			Factory:     mr.factory,
 			Quiet:       true,
+			Name:        mr.name,
+			Namespace:   mr.namespace,
 			InventoryID: prevID,
 			RGFileName:  mr.rgFile,
 			Force:       mr.force,
@@ -488,3 +500,118 @@ func (mr *Runner) createRGfile(ctx context.Context, args []string, prevID string
 	fmt.Fprint(mr.ioStreams.Out, "success\n")
 	return nil
 }
+
+// relocateRG moves the ResourceGroup inventory of the package to the name
+// and namespace set by the flags. The objects of the current inventory are
+// merged into the inventory object at the new location, and the current
+// inventory object is deleted before the local ResourceGroup file is updated.
+// As long as the local file points to the current inventory, running the
+// migration again resumes it, also when the current inventory object is
+// already gone. This operation only happens if the input was a directory
+// argument.
+func (mr *Runner) relocateRG() error {
+	if len(mr.dir) == 0 || (mr.name == "" && mr.namespace == "") {
+		return nil
+	}
+	dir, _, err := pathutil.ResolveAbsAndRelPaths(mr.dir)
+	if err != nil {
+		return err
+	}
+	rg, err := pkg.ReadRGFile(dir, mr.rgFile)
+	if err != nil {
+		if goerrors.Is(err, os.ErrNotExist) {
+			// No ResourceGroup inventory in the package: nothing to move.
+			return nil
+		}
+		return err
+	}
+	from := kptfilev1.Inventory{
+		Name:        rg.Name,
+		Namespace:   rg.Namespace,
+		InventoryID: rg.Labels[rgfilev1alpha1.RGInventoryIDLabel],
+	}
+	to := from
+	if mr.name != "" {
+		to.Name = mr.name
+	}
+	if mr.namespace != "" {
+		to.Namespace = mr.namespace
+	}
+	if to.Name == from.Name && to.Namespace == from.Namespace {
+		return nil
+	}
+
+	fmt.Fprintf(mr.ioStreams.Out, "  move ResourceGroup inventory from %s/%s to %s/%s...",
+		from.Namespace, from.Name, to.Namespace, to.Name)
+	fromInfo, err := live.ToInventoryInfo(from)
+	if err != nil {
+		return err
+	}
+	toInfo, err := live.ToInventoryInfo(to)
+	if err != nil {
+		return err
+	}
+	rgInvClient, err := mr.rgInvClientFunc(mr.factory)
+	if err != nil {
+		return err
+	}
+	if err := checkInventoryConflict(rgInvClient, toInfo); err != nil {
+		fmt.Fprintln(mr.ioStreams.Out, "failed")
+		return err
+	}
+	objs, err := rgInvClient.GetClusterObjs(fromInfo)
+	if err != nil {
+		fmt.Fprintln(mr.ioStreams.Out, "failed")
+		return err
+	}
+	if mr.dryRun {
+		fmt.Fprintf(mr.ioStreams.Out, "success (%d inventory objects)\n", len(objs))
+		return nil
+	}
+	if _, err := rgInvClient.Merge(toInfo, objs, common.DryRunNone); err != nil {
+		fmt.Fprintln(mr.ioStreams.Out, "failed")
+		return err
+	}
+	if err := rgInvClient.DeleteInventoryObj(fromInfo, common.DryRunNone); err != nil && !apierrors.IsNotFound(err) {
+		fmt.Fprintln(mr.ioStreams.Out, "failed")
+		return err
+	}
+	p, err := pkg.New(filesys.FileSystemOrOnDisk{}, dir)
+	if err != nil {
+		return err
+	}
+	err = (&initialization.ConfigureInventoryInfo{
+		Pkg:         p,
+		Factory:     mr.factory,
+		Quiet:       true,
+		Name:        to.Name,
+		Namespace:   to.Namespace,
+		InventoryID: to.InventoryID,
+		RGFileName:  mr.rgFile,
+		Force:       true,
+	}).Run(mr.ctx)
+	if err != nil {
+		fmt.Fprintln(mr.ioStreams.Out, "failed")
+		return err
+	}
+	fmt.Fprintf(mr.ioStreams.Out, "success (%d inventory objects)\n", len(objs))
+	return nil
+}
+
+// checkInventoryConflict returns an error if the inventory object of inv
+// already exists in the cluster and belongs to another package, i.e. has a
+// different inventory id.
+func checkInventoryConflict(rgInvClient inventory.Client, inv inventory.Info) error {
+	obj, err := rgInvClient.GetClusterInventoryInfo(inv)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		return nil
+	}
+	if id := obj.GetLabels()[common.InventoryLabel]; id != inv.ID() {
+		return fmt.Errorf("inventory object %s/%s already exists in the cluster with inventory id %q",
+			inv.Namespace(), inv.Name(), id)
+	}
+	return nil
+}
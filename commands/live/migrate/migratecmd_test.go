@@ -24,7 +24,9 @@ import (
 	rgfilev1alpha1 "github.com/GoogleContainerTools/kpt/pkg/api/resourcegroup/v1alpha1"
 	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
 	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 	"k8s.io/kubectl/pkg/cmd/util"
@@ -402,6 +404,228 @@ func TestKptMigrate_migrateObjs(t *testing.T) {
 	}
 }
 
+// clusterInvClient is a fake inventory client returning obj as the inventory
+// object in the cluster.
+type clusterInvClient struct {
+	*inventory.FakeClient
+	obj *unstructured.Unstructured
+}
+
+func (c *clusterInvClient) GetClusterInventoryInfo(inventory.Info) (*unstructured.Unstructured, error) {
+	return c.obj, nil
+}
+
+func movedRGInvObj(name, id string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "kpt.dev/v1alpha1",
+			"kind":       "ResourceGroup",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": inventoryNamespace,
+				"labels": map[string]interface{}{
+					common.InventoryLabel: id,
+				},
+			},
+		},
+	}
+}
+
+func TestKptMigrate_relocateRG(t *testing.T) {
+	testCases := map[string]struct {
+		name          string
+		namespace     string
+		dryRun        bool
+		clusterInv    *unstructured.Unstructured
+		expectedName  string
+		expectedNs    string
+		expectedError string
+	}{
+		"No name or namespace keeps the inventory": {
+			expectedName: "foo",
+			expectedNs:   "test-namespace",
+		},
+		"Same name and namespace keeps the inventory": {
+			name:         "foo",
+			namespace:    "test-namespace",
+			expectedName: "foo",
+			expectedNs:   "test-namespace",
+		},
+		"New name and namespace moves the inventory": {
+			name:         "bar",
+			namespace:    "other-namespace",
+			expectedName: "bar",
+			expectedNs:   "other-namespace",
+		},
+		"Dry-run does not move the inventory": {
+			namespace:    "other-namespace",
+			dryRun:       true,
+			expectedName: "foo",
+			expectedNs:   "test-namespace",
+		},
+		"Inventory of the same package at the new location is resumed": {
+			name:         "bar",
+			clusterInv:   movedRGInvObj("bar", testInventoryID),
+			expectedName: "bar",
+			expectedNs:   "test-namespace",
+		},
+		"Inventory of another package at the new location is a conflict": {
+			name:          "bar",
+			clusterInv:    movedRGInvObj("bar", "other-id"),
+			expectedName:  "foo",
+			expectedNs:    "test-namespace",
+			expectedError: `inventory object test-namespace/bar already exists in the cluster with inventory id "other-id"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace(inventoryNamespace)
+			defer tf.Cleanup()
+			ioStreams, _, _, _ := genericclioptions.NewTestIOStreams() //nolint:dogsled
+
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "Kptfile"), []byte(kptFile), 0600)
+			assert.NoError(t, err)
+			err = os.WriteFile(filepath.Join(dir, rgfilev1alpha1.RGFileName), []byte(resourceGroupInventory), 0600)
+			assert.NoError(t, err)
+
+			objs := []object.ObjMetadata{object.UnstructuredToObjMetadata(pod1)}
+			rgInvClient := &clusterInvClient{FakeClient: inventory.NewFakeClient(objs), obj: tc.clusterInv}
+
+			ctx := fake.CtxWithDefaultPrinter()
+			cmLoader := manifestreader.NewManifestLoader(tf)
+			migrateRunner := NewRunner(ctx, tf, cmLoader, ioStreams)
+			migrateRunner.dir = dir
+			migrateRunner.name = tc.name
+			migrateRunner.namespace = tc.namespace
+			migrateRunner.dryRun = tc.dryRun
+			migrateRunner.rgFile = rgfilev1alpha1.RGFileName
+			migrateRunner.rgInvClientFunc = func(util.Factory) (inventory.Client, error) {
+				return rgInvClient, nil
+			}
+
+			err = migrateRunner.relocateRG()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			rg, err := pkg.ReadRGFile(dir, rgfilev1alpha1.RGFileName)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, tc.expectedName, rg.Name)
+			assert.Equal(t, tc.expectedNs, rg.Namespace)
+			assert.Equal(t, testInventoryID, rg.Labels[rgfilev1alpha1.RGInventoryIDLabel])
+
+			// The objects of the inventory are kept.
+			clusterObjs, err := rgInvClient.GetClusterObjs(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, object.ObjMetadataSet(objs), clusterObjs)
+		})
+	}
+}
+
+// invClient is a fake inventory client keeping the objects of several
+// inventory objects in the cluster, keyed by namespace and name.
+type invClient struct {
+	*inventory.FakeClient
+	invs map[string]object.ObjMetadataSet
+}
+
+func invKey(inv inventory.Info) string {
+	return inv.Namespace() + "/" + inv.Name()
+}
+
+func (c *invClient) GetClusterInventoryInfo(inv inventory.Info) (*unstructured.Unstructured, error) {
+	if _, found := c.invs[invKey(inv)]; !found {
+		return nil, nil
+	}
+	obj := movedRGInvObj(inv.Name(), inv.ID())
+	obj.SetNamespace(inv.Namespace())
+	return obj, nil
+}
+
+func (c *invClient) GetClusterObjs(inv inventory.Info) (object.ObjMetadataSet, error) {
+	return c.invs[invKey(inv)], nil
+}
+
+func (c *invClient) Merge(inv inventory.Info, objs object.ObjMetadataSet, _ common.DryRunStrategy) (object.ObjMetadataSet, error) {
+	c.invs[invKey(inv)] = c.invs[invKey(inv)].Union(objs)
+	return nil, nil
+}
+
+func (c *invClient) DeleteInventoryObj(inv inventory.Info, _ common.DryRunStrategy) error {
+	if _, found := c.invs[invKey(inv)]; !found {
+		return apierrors.NewNotFound(schema.GroupResource{Group: "kpt.dev", Resource: "resourcegroups"}, inv.Name())
+	}
+	delete(c.invs, invKey(inv))
+	return nil
+}
+
+func TestKptMigrate_relocateRGInterrupted(t *testing.T) {
+	objs := object.ObjMetadataSet{object.UnstructuredToObjMetadata(pod1)}
+	testCases := map[string]struct {
+		clusterInvs map[string]object.ObjMetadataSet
+	}{
+		"Not interrupted": {
+			clusterInvs: map[string]object.ObjMetadataSet{
+				"test-namespace/foo": objs,
+			},
+		},
+		"Interrupted before deleting the current inventory": {
+			clusterInvs: map[string]object.ObjMetadataSet{
+				"test-namespace/foo": objs,
+				"test-namespace/bar": objs,
+			},
+		},
+		"Interrupted before updating the local file": {
+			clusterInvs: map[string]object.ObjMetadataSet{
+				"test-namespace/bar": objs,
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace(inventoryNamespace)
+			defer tf.Cleanup()
+			ioStreams, _, _, _ := genericclioptions.NewTestIOStreams() //nolint:dogsled
+
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "Kptfile"), []byte(kptFile), 0600)
+			assert.NoError(t, err)
+			err = os.WriteFile(filepath.Join(dir, rgfilev1alpha1.RGFileName), []byte(resourceGroupInventory), 0600)
+			assert.NoError(t, err)
+
+			rgInvClient := &invClient{FakeClient: inventory.NewFakeClient(nil), invs: tc.clusterInvs}
+
+			ctx := fake.CtxWithDefaultPrinter()
+			cmLoader := manifestreader.NewManifestLoader(tf)
+			migrateRunner := NewRunner(ctx, tf, cmLoader, ioStreams)
+			migrateRunner.dir = dir
+			migrateRunner.name = "bar"
+			migrateRunner.rgFile = rgfilev1alpha1.RGFileName
+			migrateRunner.rgInvClientFunc = func(util.Factory) (inventory.Client, error) {
+				return rgInvClient, nil
+			}
+
+			assert.NoError(t, migrateRunner.relocateRG())
+
+			rg, err := pkg.ReadRGFile(dir, rgfilev1alpha1.RGFileName)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, "bar", rg.Name)
+			assert.Equal(t, "test-namespace", rg.Namespace)
+			// Only the inventory object at the new location is left.
+			assert.Equal(t, map[string]object.ObjMetadataSet{"test-namespace/bar": objs}, rgInvClient.invs)
+		})
+	}
+}
+
 var kptFileWithInventory = `
 apiVersion: kpt.dev/v1
 kind: Kptfile
@@ -16,6 +16,7 @@ package status
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/livedocs"
@@ -37,6 +38,31 @@ func NewRunner(ctx context.Context, factory util.Factory,
 	r.Command.Short = livedocs.StatusShort
 	r.Command.Long = livedocs.StatusShort + "\n" + livedocs.StatusLong
 	r.Command.Example = livedocs.StatusExamples
+
+	w := &watcher{
+		ctx:        ctx,
+		factory:    factory,
+		invFactory: invFactory,
+		loader:     loader,
+		runner:     r,
+	}
+	var watch bool
+	r.Command.Flags().BoolVar(&watch, "watch", false,
+		"Stream the status transitions of the resources as JSON lines until interrupted, "+
+			"or until the condition of an explicitly set --poll-until is met.")
+	preRunE, runE := r.Command.PreRunE, r.Command.RunE
+	r.Command.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if watch && cmd.Flags().Changed("output") {
+			return fmt.Errorf("--output can't be used with --watch, which always writes JSON lines")
+		}
+		return preRunE(cmd, args)
+	}
+	r.Command.RunE = func(cmd *cobra.Command, args []string) error {
+		if watch {
+			return w.runE(cmd, args)
+		}
+		return runE(cmd, args)
+	}
 	return r
 }
 
@@ -27,6 +27,7 @@ import (
 	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
 	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -298,6 +299,112 @@ foo/deployment.apps/default/foo is InProgress: inProgress
 	}
 }
 
+func TestStatusCommandWatch(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	reconciling := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":       "foo",
+			"namespace":  "default",
+			"generation": int64(2),
+		},
+		"status": map[string]interface{}{
+			"observedGeneration": int64(1),
+		},
+	}}
+	update := func(id object.ObjMetadata, s status.Status, msg string, u *unstructured.Unstructured) pollevent.Event {
+		return pollevent.Event{
+			Type: pollevent.ResourceUpdateEvent,
+			Resource: &pollevent.ResourceStatus{
+				Identifier: id,
+				Status:     s,
+				Message:    msg,
+				Resource:   u,
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		args           []string
+		events         []pollevent.Event
+		expectedErrMsg string
+		expectedOutput string
+	}{
+		"transitions until current": {
+			args: []string{"--poll-until", "current"},
+			events: []pollevent.Event{
+				update(depObject, status.InProgressStatus, "inProgress", reconciling),
+				update(depObject, status.InProgressStatus, "inProgress", reconciling),
+				update(stsObject, status.CurrentStatus, "current", nil),
+				update(depObject, status.CurrentStatus, "current", nil),
+			},
+			expectedOutput: `
+{"type":"status","timestamp":"2024-01-02T03:04:05Z","inventoryName":"foo","group":"apps","kind":"Deployment","namespace":"default","name":"foo","status":"InProgress","message":"inProgress","condition":{"type":"Reconciling","status":"True","reason":"LatestGenerationNotObserved","message":"Deployment generation is 2, but latest observed generation is 1"}}
+{"type":"status","timestamp":"2024-01-02T03:04:05Z","inventoryName":"foo","group":"apps","kind":"StatefulSet","namespace":"default","name":"bar","status":"Current","message":"current"}
+{"type":"status","timestamp":"2024-01-02T03:04:05Z","inventoryName":"foo","group":"apps","kind":"Deployment","namespace":"default","name":"foo","status":"Current","previousStatus":"InProgress","message":"current"}
+`,
+		},
+		"filtered statuses": {
+			args: []string{"--poll-until", "current", "--statuses", "Current"},
+			events: []pollevent.Event{
+				update(depObject, status.InProgressStatus, "inProgress", nil),
+				update(stsObject, status.CurrentStatus, "current", nil),
+				update(depObject, status.CurrentStatus, "current", nil),
+			},
+			expectedOutput: `
+{"type":"status","timestamp":"2024-01-02T03:04:05Z","inventoryName":"foo","group":"apps","kind":"StatefulSet","namespace":"default","name":"bar","status":"Current","message":"current"}
+{"type":"status","timestamp":"2024-01-02T03:04:05Z","inventoryName":"foo","group":"apps","kind":"Deployment","namespace":"default","name":"foo","status":"Current","previousStatus":"InProgress","message":"current"}
+`,
+		},
+		"output flag": {
+			args:           []string{"--output", "table"},
+			expectedErrMsg: "--output can't be used with --watch",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace("namespace")
+			defer tf.Cleanup()
+
+			w, clean := testutil.SetupWorkspace(t)
+			defer clean()
+			kf := kptfileutil.DefaultKptfile(filepath.Base(w.WorkspaceDirectory))
+			kf.Inventory = &kptfilev1.Inventory{
+				Name:        "foo",
+				Namespace:   "default",
+				InventoryID: "test",
+			}
+			testutil.AddKptfileToWorkspace(t, w, kf)
+
+			revert := testutil.Chdir(t, w.WorkspaceDirectory)
+			defer revert()
+
+			inv := []object.ObjMetadata{depObject, stsObject}
+			var outBuf bytes.Buffer
+			ctx := fake.CtxWithPrinter(&outBuf, &outBuf)
+			runner := NewRunner(ctx, tf, inventory.FakeClientFactory(inv), NewFakeLoader(ctx, tf, inv))
+			runner.PollerFactoryFunc = func(c cmdutil.Factory) (poller.Poller, error) {
+				return &fakePoller{tc.events}, nil
+			}
+
+			runner.Command.SetArgs(append([]string{"--watch"}, tc.args...))
+			runner.Command.SetOut(&outBuf)
+			err := runner.Command.Execute()
+
+			if tc.expectedErrMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(tc.expectedOutput), strings.TrimSpace(outBuf.String()))
+		})
+	}
+}
+
 type fakePoller struct {
 	events []pollevent.Event
 }
@@ -0,0 +1,251 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/cli-utils/cmd/status"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/aggregator"
+	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// now returns the timestamp of watch events. It is replaced in tests.
+var now = time.Now
+
+// watchEvent is a line of the `kpt live status --watch` output. Status
+// events are written when the status or message of a resource changes.
+type watchEvent struct {
+	Type          string          `json:"type"`
+	Timestamp     string          `json:"timestamp"`
+	InventoryName string          `json:"inventoryName,omitempty"`
+	Group         string          `json:"group,omitempty"`
+	Kind          string          `json:"kind,omitempty"`
+	Namespace     string          `json:"namespace,omitempty"`
+	Name          string          `json:"name,omitempty"`
+	Status        string          `json:"status,omitempty"`
+	PrevStatus    string          `json:"previousStatus,omitempty"`
+	Message       string          `json:"message,omitempty"`
+	Condition     *watchCondition `json:"condition,omitempty"`
+}
+
+// watchCondition is the condition explaining why a resource is not
+// Current, e.g. Reconciling or Stalled.
+type watchCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// watcher streams status transitions of the inventory objects as JSON lines.
+type watcher struct {
+	ctx        context.Context
+	factory    util.Factory
+	invFactory inventory.ClientFactory
+	loader     status.Loader
+	runner     *status.Runner
+}
+
+// transition returns the status event of the update if the status or
+// message of the resource changed since the previous one.
+func transition(prev, rs *pollevent.ResourceStatus, invName string) (watchEvent, bool) {
+	if prev != nil && prev.Status == rs.Status && prev.Message == rs.Message {
+		return watchEvent{}, false
+	}
+	e := watchEvent{
+		Type:          "status",
+		Timestamp:     now().UTC().Format(time.RFC3339),
+		InventoryName: invName,
+		Group:         rs.Identifier.GroupKind.Group,
+		Kind:          rs.Identifier.GroupKind.Kind,
+		Namespace:     rs.Identifier.Namespace,
+		Name:          rs.Identifier.Name,
+		Status:        rs.Status.String(),
+		Message:       rs.Message,
+	}
+	if prev != nil && prev.Status != rs.Status {
+		e.PrevStatus = prev.Status.String()
+	}
+	if rs.Resource != nil {
+		if res, err := kstatus.Compute(rs.Resource); err == nil && len(res.Conditions) > 0 {
+			c := res.Conditions[0]
+			e.Condition = &watchCondition{
+				Type:    c.Type.String(),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
+			}
+		}
+	}
+	return e, true
+}
+
+func (w *watcher) runE(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	period, _ := flags.GetDuration("poll-period")
+	timeout, _ := flags.GetDuration("timeout")
+	pollUntil := status.Forever
+	if flags.Changed("poll-until") {
+		pollUntil, _ = flags.GetString("poll-until")
+	}
+	statuses := map[string]bool{}
+	for s := range setFromFlag(cmd, "statuses") {
+		statuses[strings.ToLower(s)] = true
+	}
+
+	identifiers, invNames, err := w.inventoryObjects(cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(identifiers) == 0 {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), "no resources found in the inventory\n")
+		return nil
+	}
+
+	statusPoller, err := w.runner.PollerFactoryFunc(w.factory)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(cmd.Context())
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(cmd.Context(), timeout)
+	}
+	defer cancel()
+
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	latest := map[object.ObjMetadata]*pollevent.ResourceStatus{}
+	for e := range statusPoller.Poll(ctx, identifiers, polling.PollOptions{PollInterval: period}) {
+		switch e.Type {
+		case pollevent.ErrorEvent:
+			_ = enc.Encode(watchEvent{
+				Type:      "error",
+				Timestamp: now().UTC().Format(time.RFC3339),
+				Message:   e.Error.Error(),
+			})
+			return e.Error
+		case pollevent.ResourceUpdateEvent:
+			id := e.Resource.Identifier
+			we, changed := transition(latest[id], e.Resource, invNames[id])
+			latest[id] = e.Resource
+			if changed && (len(statuses) == 0 || statuses[strings.ToLower(we.Status)]) {
+				if err := enc.Encode(we); err != nil {
+					return err
+				}
+			}
+			if done(pollUntil, identifiers, latest) {
+				cancel()
+			}
+		}
+	}
+	return nil
+}
+
+// done returns true if the resources reached the state the watch waits for.
+func done(pollUntil string, identifiers object.ObjMetadataSet, latest map[object.ObjMetadata]*pollevent.ResourceStatus) bool {
+	if pollUntil == status.Forever || len(latest) < len(identifiers) {
+		return false
+	}
+	var rss []*pollevent.ResourceStatus
+	for _, rs := range latest {
+		rss = append(rss, rs)
+	}
+	switch pollUntil {
+	case status.Known:
+		for _, rs := range rss {
+			if rs.Status == kstatus.UnknownStatus {
+				return false
+			}
+		}
+		return true
+	case status.Current:
+		return aggregator.AggregateStatus(rss, kstatus.CurrentStatus) == kstatus.CurrentStatus
+	case status.Deleted:
+		return aggregator.AggregateStatus(rss, kstatus.NotFoundStatus) == kstatus.NotFoundStatus
+	}
+	return false
+}
+
+// inventoryObjects returns the objects to watch and the names of their
+// inventories, using the same inventory flags as the status command.
+func (w *watcher) inventoryObjects(cmd *cobra.Command, args []string) (object.ObjMetadataSet, map[object.ObjMetadata]string, error) {
+	invType, _ := cmd.Flags().GetString("inv-type")
+	namespaces := setFromFlag(cmd, "namespaces")
+	invClient, err := w.invFactory.NewClient(w.factory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byInv := map[string]object.ObjMetadataSet{}
+	switch invType {
+	case status.Local:
+		inv, err := w.loader.GetInvInfo(cmd, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		objs, err := invClient.GetClusterObjs(inv)
+		if err != nil {
+			return nil, nil, err
+		}
+		byInv[inv.Name()] = objs
+	case status.Remote:
+		invNames := setFromFlag(cmd, "inv-names")
+		all, err := invClient.ListClusterInventoryObjs(w.ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, objs := range all {
+			if len(invNames) == 0 || invNames[name] {
+				byInv[name] = objs
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("invType must be either local or remote")
+	}
+
+	var identifiers object.ObjMetadataSet
+	names := map[object.ObjMetadata]string{}
+	for name, objs := range byInv {
+		for _, obj := range objs {
+			if len(namespaces) == 0 || namespaces[obj.Namespace] {
+				identifiers = append(identifiers, obj)
+				names[obj] = name
+			}
+		}
+	}
+	return identifiers, names, nil
+}
+
+func setFromFlag(cmd *cobra.Command, name string) map[string]bool {
+	v, _ := cmd.Flags().GetString(name)
+	if v == "" {
+		return nil
+	}
+	set := map[string]bool{}
+	for _, s := range strings.Split(v, ",") {
+		set[s] = true
+	}
+	return set
+}
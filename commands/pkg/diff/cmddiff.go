@@ -23,6 +23,7 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/diff"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	"github.com/GoogleContainerTools/kpt/internal/util/pathutil"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/spf13/cobra"
@@ -54,6 +55,8 @@ func NewRunner(ctx context.Context, parent string) *Runner {
 		"diff tool to use to show the changes")
 	c.Flags().StringVar(&r.DiffToolOpts, "diff-tool-opts", diffToolOpts,
 		"diff tool commandline options to use to show the changes")
+	c.Flags().StringVarP(&r.output, "output", "o", "",
+		"render the changes with kpt instead of the diff tool, in one of these formats: "+diff.SupportedOutputFormatsLabel())
 	c.Flags().BoolVar(&r.Debug, "debug", false,
 		"when true, prints additional debug information and do not delete staged pkg dirs")
 	r.C = c
@@ -73,6 +76,7 @@ type Runner struct {
 	diff.Command
 	C        *cobra.Command
 	diffType string
+	output   string
 }
 
 func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
@@ -112,11 +116,17 @@ func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
 	}
 	r.Path = string(p.UniquePath)
 	r.Ref = version
+	r.OutputFormat = diff.OutputFormat(r.output)
 	r.Output = printer.FromContextOrDie(r.ctx).OutStream()
 
 	return r.Validate()
 }
 
 func (r *Runner) runE(_ *cobra.Command, _ []string) error {
-	return r.Run(r.ctx)
+	err := r.Run(r.ctx)
+	if err != nil && output.IsFormat(r.output) {
+		// the result is written by the differ on success only.
+		return output.Write(r.Output, output.Format(r.output), "pkg diff", r.Path, nil, err)
+	}
+	return err
 }
@@ -15,6 +15,8 @@
 package diff_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -22,6 +24,7 @@ import (
 	"github.com/GoogleContainerTools/kpt/commands/pkg/diff"
 	"github.com/GoogleContainerTools/kpt/commands/pkg/get"
 	"github.com/GoogleContainerTools/kpt/internal/testutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -37,7 +40,7 @@ func TestCmdInvalidDiffType(t *testing.T) {
 	err := runner.C.Execute()
 	assert.EqualError(t,
 		err,
-		"invalid diff-type 'invalid': supported diff-types are: local, remote, combined, 3way")
+		"invalid diff-type 'invalid': supported diff-types are: local, remote, combined, 3way, rendered")
 }
 
 func TestCmdInvalidDiffTool(t *testing.T) {
@@ -49,6 +52,33 @@ func TestCmdInvalidDiffTool(t *testing.T) {
 		"diff-tool 'nodiff' not found in the PATH")
 }
 
+func TestCmdInvalidOutput(t *testing.T) {
+	runner := diff.NewRunner(fake.CtxWithDefaultPrinter(), "")
+	runner.C.SetArgs([]string{"--output", "invalid"})
+	err := runner.C.Execute()
+	assert.EqualError(t,
+		err,
+		"invalid output 'invalid': supported outputs are: unified, html, patch, json, yaml")
+}
+
+func TestCmdOutput3Way(t *testing.T) {
+	runner := diff.NewRunner(fake.CtxWithDefaultPrinter(), "")
+	runner.C.SetArgs([]string{"--output", "html", "--diff-type", "3way"})
+	err := runner.C.Execute()
+	assert.EqualError(t,
+		err,
+		"diff-type '3way' is not supported with output 'html'")
+}
+
+func TestCmdOutputRenderedPatch(t *testing.T) {
+	runner := diff.NewRunner(fake.CtxWithDefaultPrinter(), "")
+	runner.C.SetArgs([]string{"--output", "patch", "--diff-type", "rendered"})
+	err := runner.C.Execute()
+	assert.EqualError(t,
+		err,
+		"diff-type 'rendered' is not supported with output 'patch'")
+}
+
 func TestCmdExecute(t *testing.T) {
 	g, w, clean := testutil.SetupRepoAndWorkspace(t, testutil.Content{
 		Data:   testutil.Dataset1,
@@ -71,6 +101,72 @@ func TestCmdExecute(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCmdExecuteOutput(t *testing.T) {
+	g, w, clean := testutil.SetupRepoAndWorkspace(t, testutil.Content{
+		Data:   testutil.Dataset1,
+		Branch: "master",
+	})
+	defer clean()
+
+	defer testutil.Chdir(t, w.WorkspaceDirectory)()
+
+	dest := filepath.Join(w.WorkspaceDirectory, g.RepoName)
+
+	getRunner := get.NewRunner(fake.CtxWithDefaultPrinter(), "")
+	getRunner.Command.SetArgs([]string{"file://" + g.RepoDirectory + ".git/", "./"})
+	err := getRunner.Command.Execute()
+	assert.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dest, "new.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new
+`), 0644)
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	runner := diff.NewRunner(fake.CtxWithPrinter(out, out), "")
+	runner.C.SetArgs([]string{dest, "--diff-type", "local", "--output", "unified"})
+	err = runner.C.Execute()
+	assert.NoError(t, err)
+	// like with the diff tool, local changes are shown relative to the local
+	// package, so resources only in the local package show up as deleted.
+	assert.Contains(t, out.String(), "# v1/ConfigMap new (new.yaml) deleted\n--- a/new.yaml\n+++ /dev/null\n")
+
+	// the patch applies to the files of the local package as they are.
+	out.Reset()
+	runner = diff.NewRunner(fake.CtxWithPrinter(out, &bytes.Buffer{}), "")
+	runner.C.SetArgs([]string{dest, "--diff-type", "local", "--output", "patch"})
+	err = runner.C.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `diff -u a/new.yaml /dev/null
+--- a/new.yaml
++++ /dev/null
+@@ -1,4 +0,0 @@
+-apiVersion: v1
+-kind: ConfigMap
+-metadata:
+-  name: new
+`, out.String())
+
+	out.Reset()
+	runner = diff.NewRunner(fake.CtxWithPrinter(out, &bytes.Buffer{}), "")
+	runner.C.SetArgs([]string{dest, "--diff-type", "local", "--output", "json"})
+	err = runner.C.Execute()
+	assert.NoError(t, err)
+	var result output.CommandResult
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, "pkg diff", result.Command)
+	assert.Equal(t, output.ExitSuccess, result.ExitCode)
+	if assert.Len(t, result.Data, 1) {
+		d := result.Data.([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "v1/ConfigMap new", d["id"])
+		assert.Equal(t, "new.yaml", d["path"])
+		assert.Equal(t, "deleted", d["change"])
+		assert.Contains(t, d["unified"], "--- a/new.yaml\n+++ /dev/null\n")
+	}
+}
+
 func TestCmd_flagAndArgParsing_Symlink(t *testing.T) {
 	dir := t.TempDir()
 	defer testutil.Chdir(t, dir)()
@@ -26,9 +26,11 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/get"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	"github.com/GoogleContainerTools/kpt/internal/util/parse"
 	"github.com/GoogleContainerTools/kpt/internal/util/pathutil"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
@@ -55,6 +57,14 @@ func NewRunner(ctx context.Context, parent string) *Runner {
 			strings.Join(kptfilev1.UpdateStrategiesAsStrings(), ","))
 	c.Flags().BoolVar(&r.isDeploymentInstance, "for-deployment", false,
 		"(Experimental) indicates if this package will be deployed to a cluster.")
+	c.Flags().StringSliceVar(&r.Get.Include, "include", nil,
+		"glob patterns of the paths of the subpackages to fetch, relative to the package. All subpackages are fetched by default.")
+	c.Flags().StringSliceVar(&r.Get.Exclude, "exclude", nil,
+		"glob patterns of the paths of the subpackages which should not be fetched, relative to the package.")
+	c.Flags().IntVar(&r.Get.Depth, "depth", 0,
+		"maximum nesting depth of the fetched subpackages. Subpackages at any depth are fetched if 0.")
+	c.Flags().StringVarP(&r.output, "output", "o", "",
+		"write the result of the command to stdout in a machine-readable format: "+output.SupportedFormatsLabel())
 	_ = c.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return kptfilev1.UpdateStrategiesAsStrings(), cobra.ShellCompDirectiveDefault
 	})
@@ -72,9 +82,29 @@ type Runner struct {
 	Command              *cobra.Command
 	strategy             string
 	isDeploymentInstance bool
+	output               string
+	outputFormat         output.Format
 }
 
 func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
+	const op errors.Op = "cmdget.preRunE"
+	var err error
+	r.outputFormat, err = output.ParseFormat(r.output)
+	if err != nil {
+		return errors.E(op, errors.InvalidParam, err)
+	}
+	if err := r.parseArgs(args); err != nil {
+		if r.outputFormat != output.None {
+			return output.Write(printer.FromContextOrDie(r.ctx).OutStream(), r.outputFormat,
+				"pkg get", "", nil, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// parseArgs sets up the command from the arguments and flags.
+func (r *Runner) parseArgs(args []string) error {
 	const op errors.Op = "cmdget.preRunE"
 	if len(args) == 1 {
 		args = append(args, pkg.CurDir)
@@ -88,20 +118,31 @@ func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
 			args[1] = resolvedPath
 		}
 	}
-	t, err := parse.GitParseArgs(r.ctx, args)
-	if err != nil {
-		return errors.E(op, err)
+	var destination string
+	if parse.IsOciArg(args[0]) {
+		t, err := parse.OciParseArgs(args)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		r.Get.Oci = &t.Oci
+		destination = t.Destination
+	} else {
+		t, err := parse.GitParseArgs(r.ctx, args)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		r.Get.Git = &t.Git
+		destination = t.Destination
 	}
 
-	r.Get.Git = &t.Git
-	absDestPath, _, err := pathutil.ResolveAbsAndRelPaths(t.Destination)
+	absDestPath, _, err := pathutil.ResolveAbsAndRelPaths(destination)
 	if err != nil {
 		return err
 	}
 
 	p, err := pkg.New(filesys.FileSystemOrOnDisk{}, absDestPath)
 	if err != nil {
-		return errors.E(op, types.UniquePath(t.Destination), err)
+		return errors.E(op, types.UniquePath(destination), err)
 	}
 	r.Get.Destination = string(p.UniquePath)
 
@@ -116,9 +157,29 @@ func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
 
 func (r *Runner) runE(_ *cobra.Command, _ []string) error {
 	const op errors.Op = "cmdget.runE"
-	if err := r.Get.Run(r.ctx); err != nil {
-		return errors.E(op, types.UniquePath(r.Get.Destination), err)
+	err := r.Get.Run(r.ctx)
+	if err != nil {
+		err = errors.E(op, types.UniquePath(r.Get.Destination), err)
 	}
+	if r.outputFormat != output.None {
+		return output.Write(printer.FromContextOrDie(r.ctx).OutStream(), r.outputFormat,
+			"pkg get", r.Get.Destination, upstreamData(r.Get.Destination, err), err)
+	}
+	return err
+}
 
-	return nil
+// upstreamData returns the upstream of the fetched package, or nil if
+// fetching it failed.
+func upstreamData(path string, err error) interface{} {
+	if err != nil {
+		return nil
+	}
+	kf, err := pkg.ReadKptfile(filesys.FileSystemOrOnDisk{}, path)
+	if err != nil {
+		return nil
+	}
+	return output.UpstreamData{
+		Upstream:     kf.Upstream,
+		UpstreamLock: kf.UpstreamLock,
+	}
 }
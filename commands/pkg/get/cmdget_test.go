@@ -15,6 +15,8 @@
 package get_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -23,6 +25,7 @@ import (
 
 	"github.com/GoogleContainerTools/kpt/commands/pkg/get"
 	"github.com/GoogleContainerTools/kpt/internal/testutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
 	"github.com/spf13/cobra"
@@ -168,6 +171,49 @@ func TestCmd_fail(t *testing.T) {
 	assert.Contains(t, err.Error(), "'/real/dir' does not appear to be a git repository")
 }
 
+// TestCmd_output verifies that the result is written to stdout with --output.
+func TestCmd_output(t *testing.T) {
+	g, w, clean := testutil.SetupRepoAndWorkspace(t, testutil.Content{
+		Data:   testutil.Dataset1,
+		Branch: "master",
+	})
+	defer clean()
+
+	defer testutil.Chdir(t, w.WorkspaceDirectory)()
+
+	out := &bytes.Buffer{}
+	r := get.NewRunner(fake.CtxWithPrinter(out, &bytes.Buffer{}), "kpt")
+	r.Command.SetArgs([]string{"file://" + g.RepoDirectory + ".git/", "--output", "json"})
+	err := r.Command.Execute()
+	assert.NoError(t, err)
+
+	commit, err := g.GetCommit()
+	assert.NoError(t, err)
+	var result struct {
+		output.CommandResult
+		Data output.UpstreamData `json:"data"`
+	}
+	if !assert.NoError(t, json.Unmarshal(out.Bytes(), &result)) {
+		return
+	}
+	assert.Equal(t, "pkg get", result.Command)
+	assert.Equal(t, output.ExitSuccess, result.ExitCode)
+	assert.Equal(t, filepath.Join(w.WorkspaceDirectory, g.RepoName), result.Package)
+	assert.Equal(t, commit, result.Data.UpstreamLock.Git.Commit)
+
+	// the result is written on failure too
+	out.Reset()
+	r = get.NewRunner(fake.CtxWithPrinter(out, &bytes.Buffer{}), "kpt")
+	r.Command.SilenceErrors = true
+	r.Command.SilenceUsage = true
+	r.Command.SetArgs([]string{"file://" + g.RepoDirectory + ".git/", "--output", "json"})
+	err = r.Command.Execute()
+	assert.Error(t, err)
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &result.CommandResult))
+	assert.Equal(t, output.ExitFailure, result.ExitCode)
+	assert.Equal(t, err.Error(), result.Error)
+}
+
 // NoOpRunE is a noop function to replace the run function of a command.  Useful for testing argument parsing.
 var NoOpRunE = func(cmd *cobra.Command, args []string) error { return nil }
 
@@ -16,6 +16,8 @@ package init
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	docs "github.com/GoogleContainerTools/kpt/internal/docs/generated/pkgdocs"
 	"github.com/GoogleContainerTools/kpt/internal/pkg"
@@ -43,6 +45,8 @@ func NewRunner(ctx context.Context, parent string) *Runner {
 	c.Flags().StringVar(&r.Description, "description", "sample description", "short description of the package.")
 	c.Flags().StringSliceVar(&r.Keywords, "keywords", []string{}, "list of keywords for the package.")
 	c.Flags().StringVar(&r.Site, "site", "", "link to page with information about the package.")
+	c.Flags().StringVar(&r.Template, "template", "",
+		fmt.Sprintf("template to scaffold the package from, one of: %s.", strings.Join(kptpkg.InitTemplateNames(), ", ")))
 	cmdutil.FixDocs("kpt", parent, c)
 	r.Command = c
 	return r
@@ -59,6 +63,7 @@ type Runner struct {
 	Name        string
 	Description string
 	Site        string
+	Template    string
 	Ctx         context.Context
 }
 
@@ -79,6 +84,7 @@ func (r *Runner) runE(_ *cobra.Command, args []string) error {
 		Desc:     r.Description,
 		Keywords: r.Keywords,
 		Site:     r.Site,
+		Template: r.Template,
 	}
 
 	return pkgIniter.Initialize(r.Ctx, filesys.FileSystemOrOnDisk{}, initOps)
@@ -171,3 +171,83 @@ func TestCmd_failNotExists(t *testing.T) {
 		assert.Contains(t, err.Error(), "does not exist")
 	}
 }
+
+// TestCmd_template verifies the package is scaffolded from the template
+func TestCmd_template(t *testing.T) {
+	d := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(d, "my-pkg"), 0700))
+
+	r := initialization.NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+	r.Command.SetArgs([]string{filepath.Join(d, "my-pkg"), "--description", "my description", "--template", "app-with-pipeline"})
+	err := r.Command.Execute()
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(d, "my-pkg", "Kptfile"))
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: my-pkg
+  annotations:
+    config.kubernetes.io/local-config: "true"
+info:
+  description: my description
+pipeline:
+  mutators:
+  - image: gcr.io/kpt-fn/set-namespace:v0.4.1
+    configPath: package-context.yaml
+  - image: gcr.io/kpt-fn/set-labels:v0.1.5
+    configMap:
+      app: my-pkg
+  validators:
+  - image: gcr.io/kpt-fn/kubeval:v0.3
+`, string(b))
+
+	b, err = os.ReadFile(filepath.Join(d, "my-pkg", man.ManFilename))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "This package was scaffolded from the app-with-pipeline template.")
+
+	for _, f := range []string{"configmap.yaml", "deployment.yaml", "service.yaml", builtins.PkgContextFile} {
+		_, err := os.Stat(filepath.Join(d, "my-pkg", f))
+		assert.NoError(t, err)
+	}
+	b, err = os.ReadFile(filepath.Join(d, "my-pkg", "service.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "name: my-pkg\n")
+}
+
+// TestCmd_unknownTemplate verifies the command fails before writing anything
+func TestCmd_unknownTemplate(t *testing.T) {
+	d := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(d, "my-pkg"), 0700))
+
+	r := initialization.NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+	r.Command.SetArgs([]string{filepath.Join(d, "my-pkg"), "--template", "statefulset"})
+	err := r.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unknown template "statefulset", must be one of app-with-pipeline, deployment, namespace-provisioning`)
+	}
+	_, err = os.Stat(filepath.Join(d, "my-pkg", "Kptfile"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+// TestCmd_templateKptfileExists verifies the command fails before writing
+// anything if the package already has a Kptfile
+func TestCmd_templateKptfileExists(t *testing.T) {
+	d := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(d, "my-pkg"), 0700))
+	kptfile := "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: my-pkg\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(d, "my-pkg", "Kptfile"), []byte(kptfile), 0600))
+
+	r := initialization.NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+	r.Command.SetArgs([]string{filepath.Join(d, "my-pkg"), "--template", "deployment"})
+	err := r.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "already has a Kptfile, --template can only be used for a new package")
+	}
+	b, err := os.ReadFile(filepath.Join(d, "my-pkg", "Kptfile"))
+	assert.NoError(t, err)
+	assert.Equal(t, kptfile, string(b))
+	_, err = os.Stat(filepath.Join(d, "my-pkg", man.ManFilename))
+	assert.True(t, os.IsNotExist(err))
+}
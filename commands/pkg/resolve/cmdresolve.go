@@ -0,0 +1,148 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	docs "github.com/GoogleContainerTools/kpt/internal/docs/generated/pkgdocs"
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/pkg"
+	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/merge"
+	"github.com/GoogleContainerTools/kpt/internal/util/pathutil"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+// NewRunner returns a command runner.
+func NewRunner(ctx context.Context, parent string) *Runner {
+	r := &Runner{
+		ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:     "resolve [PKG_PATH] [flags]",
+		Args:    cobra.MaximumNArgs(1),
+		Short:   docs.ResolveShort,
+		Long:    docs.ResolveShort + "\n" + docs.ResolveLong,
+		Example: docs.ResolveExamples,
+		PreRunE: r.preRunE,
+		RunE:    r.runE,
+	}
+
+	c.Flags().BoolVar(&r.ours, "ours", false, "resolve the conflicts by keeping the local version of the resources.")
+	c.Flags().BoolVar(&r.theirs, "theirs", false, "resolve the conflicts by accepting the upstream version of the resources.")
+	c.Flags().StringSliceVar(&r.files, "file", nil, "only resolve the conflicts in the given files, relative to the package.")
+	cmdutil.FixDocs("kpt", parent, c)
+	r.Command = c
+	return r
+}
+
+func NewCommand(ctx context.Context, parent string) *cobra.Command {
+	return NewRunner(ctx, parent).Command
+}
+
+// Runner contains the run function.
+type Runner struct {
+	ctx     context.Context
+	ours    bool
+	theirs  bool
+	files   []string
+	pkgPath string
+	Command *cobra.Command
+}
+
+func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
+	const op errors.Op = "cmdresolve.preRunE"
+	if r.ours && r.theirs {
+		return errors.E(op, errors.InvalidParam, fmt.Errorf("only one of --ours and --theirs may be specified"))
+	}
+	if len(r.files) > 0 && !r.ours && !r.theirs {
+		return errors.E(op, errors.InvalidParam, fmt.Errorf("--file requires --ours or --theirs"))
+	}
+	if len(args) == 0 {
+		args = append(args, pkg.CurDir)
+	}
+	absPath, _, err := pathutil.ResolveAbsAndRelPaths(args[0])
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r.pkgPath = absPath
+	return nil
+}
+
+func (r *Runner) runE(_ *cobra.Command, _ []string) error {
+	const op errors.Op = "cmdresolve.runE"
+	pr := printer.FromContextOrDie(r.ctx)
+
+	files, err := merge.FilesWithConflictMarkers(r.pkgPath)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if !r.ours && !r.theirs {
+		if len(files) == 0 {
+			pr.Printf("No conflicts found.\n")
+			return nil
+		}
+		pr.Printf("Conflicts found in:\n")
+		for _, f := range files {
+			pr.Printf("  %s\n", f)
+		}
+		return nil
+	}
+
+	side := merge.Ours
+	if r.theirs {
+		side = merge.Theirs
+	}
+	if len(r.files) > 0 {
+		withConflicts := map[string]bool{}
+		for _, f := range files {
+			withConflicts[f] = true
+		}
+		files = nil
+		for _, f := range r.files {
+			f = filepath.Clean(f)
+			if !withConflicts[f] {
+				return errors.E(op, fmt.Errorf("file %q has no conflicts", f))
+			}
+			files = append(files, f)
+		}
+	}
+
+	for _, f := range files {
+		path := filepath.Join(r.pkgPath, f)
+		info, err := os.Stat(path)
+		if err != nil {
+			return errors.E(op, errors.IO, err)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return errors.E(op, errors.IO, err)
+		}
+		resolved, err := merge.ResolveConflicts(string(b), side)
+		if err != nil {
+			return errors.E(op, fmt.Errorf("%s: %w", f, err))
+		}
+		if err := os.WriteFile(path, []byte(resolved), info.Mode()); err != nil {
+			return errors.E(op, errors.IO, err)
+		}
+		pr.Printf("Resolved conflicts in %s.\n", f)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/commands/pkg/resolve"
+	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+const conflicted = `<<<<<<< local
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: local
+=======
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: upstream
+>>>>>>> upstream
+`
+
+func TestCmd(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    map[string]string
+		wantErr string
+	}{
+		"list conflicts": {
+			want: map[string]string{"a.yaml": conflicted, "sub/b.yaml": conflicted},
+		},
+		"ours": {
+			args: []string{"--ours"},
+			want: map[string]string{
+				"a.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: local\n",
+				"sub/b.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: local\n",
+			},
+		},
+		"theirs for a file": {
+			args: []string{"--theirs", "--file", "sub/b.yaml"},
+			want: map[string]string{
+				"a.yaml":     conflicted,
+				"sub/b.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: upstream\n",
+			},
+		},
+		"file without conflicts": {
+			args:    []string{"--ours", "--file", "c.yaml"},
+			wantErr: `file "c.yaml" has no conflicts`,
+		},
+		"both sides": {
+			args:    []string{"--ours", "--theirs"},
+			wantErr: "only one of --ours and --theirs may be specified",
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			d := t.TempDir()
+			assert.NoError(t, os.Mkdir(filepath.Join(d, "sub"), 0700))
+			for _, f := range []string{"a.yaml", "sub/b.yaml"} {
+				assert.NoError(t, os.WriteFile(filepath.Join(d, f), []byte(conflicted), 0600))
+			}
+
+			r := resolve.NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+			r.Command.SetArgs(append([]string{d}, tc.args...))
+			err := r.Command.Execute()
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			for f, want := range tc.want {
+				b, err := os.ReadFile(filepath.Join(d, f))
+				assert.NoError(t, err)
+				assert.Equal(t, want, string(b), f)
+			}
+		})
+	}
+}
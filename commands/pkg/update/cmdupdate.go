@@ -27,9 +27,11 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/types"
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	"github.com/GoogleContainerTools/kpt/internal/util/pathutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/update"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
@@ -57,6 +59,11 @@ func NewRunner(ctx context.Context, parent string) *Runner {
 	_ = c.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return kptfilev1.UpdateStrategiesAsStrings(), cobra.ShellCompDirectiveDefault
 	})
+	c.Flags().BoolVar(&r.Update.MarkConflicts, "mark-conflicts", false,
+		"write conflicting local and upstream changes between conflict markers instead of "+
+			"keeping the upstream changes. Only supported with the resource-merge strategy.")
+	c.Flags().StringVarP(&r.output, "output", "o", "",
+		"write the result of the command to stdout in a machine-readable format: "+output.SupportedFormatsLabel())
 	cmdutil.FixDocs("kpt", parent, c)
 	r.Command = c
 	return r
@@ -69,13 +76,33 @@ func NewCommand(ctx context.Context, parent string) *cobra.Command {
 // Runner contains the run function.
 // TODO, support listing versions
 type Runner struct {
-	ctx      context.Context
-	strategy string
-	Update   update.Command
-	Command  *cobra.Command
+	ctx          context.Context
+	strategy     string
+	output       string
+	outputFormat output.Format
+	Update       update.Command
+	Command      *cobra.Command
 }
 
 func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
+	const op errors.Op = "cmdupdate.preRunE"
+	var err error
+	r.outputFormat, err = output.ParseFormat(r.output)
+	if err != nil {
+		return errors.E(op, errors.InvalidParam, err)
+	}
+	if err := r.parseArgs(args); err != nil {
+		if r.outputFormat != output.None {
+			return output.Write(printer.FromContextOrDie(r.ctx).OutStream(), r.outputFormat,
+				"pkg update", "", nil, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// parseArgs sets up the command from the arguments and flags.
+func (r *Runner) parseArgs(args []string) error {
 	const op errors.Op = "cmdupdate.preRunE"
 	if len(args) == 0 {
 		args = append(args, pkg.CurDir)
@@ -85,6 +112,10 @@ func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
 	} else {
 		r.Update.Strategy = kptfilev1.UpdateStrategyType(r.strategy)
 	}
+	if r.Update.MarkConflicts && r.Update.Strategy != kptfilev1.ResourceMerge {
+		return errors.E(op, errors.InvalidParam,
+			fmt.Errorf("--mark-conflicts is only supported with the %s strategy", kptfilev1.ResourceMerge))
+	}
 
 	parts := strings.Split(args[0], "@")
 	if len(parts) > 2 {
@@ -124,11 +155,24 @@ func (r *Runner) preRunE(_ *cobra.Command, args []string) error {
 
 func (r *Runner) runE(_ *cobra.Command, _ []string) error {
 	const op errors.Op = "cmdupdate.runE"
-	if err := r.Update.Run(r.ctx); err != nil {
-		return errors.E(op, r.Update.Pkg.UniquePath, err)
+	err := r.Update.Run(r.ctx)
+	if err != nil {
+		err = errors.E(op, r.Update.Pkg.UniquePath, err)
 	}
-
-	return nil
+	if r.outputFormat != output.None {
+		var data interface{}
+		if err == nil {
+			if kf, kfErr := pkg.ReadKptfile(filesys.FileSystemOrOnDisk{}, r.Update.Pkg.UniquePath.String()); kfErr == nil {
+				data = output.UpstreamData{
+					Upstream:     kf.Upstream,
+					UpstreamLock: kf.UpstreamLock,
+				}
+			}
+		}
+		return output.Write(printer.FromContextOrDie(r.ctx).OutStream(), r.outputFormat,
+			"pkg update", r.Update.Pkg.UniquePath.String(), data, err)
+	}
+	return err
 }
 
 func resolveRelPath(path types.UniquePath) (string, error) {
@@ -132,6 +132,82 @@ func TestCmd_execute(t *testing.T) {
 	}
 }
 
+func TestCmd_markConflicts(t *testing.T) {
+	g, w, clean := testutil.SetupRepoAndWorkspace(t, testutil.Content{
+		Data:   testutil.Dataset1,
+		Branch: "master",
+	})
+	defer clean()
+
+	defer testutil.Chdir(t, w.WorkspaceDirectory)()
+
+	dest := filepath.Join(w.WorkspaceDirectory, g.RepoName)
+
+	getCmd := get.NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+	getCmd.Command.SetArgs([]string{"file://" + g.RepoDirectory + ".git", w.WorkspaceDirectory})
+	if !assert.NoError(t, getCmd.Command.Execute()) {
+		return
+	}
+
+	// change the mysql image locally, while upstream changes it to 8.0
+	statefulSet := filepath.Join(dest, "mysql", "mysql-statefulset.resource.yaml")
+	b, err := os.ReadFile(statefulSet)
+	if !assert.NoError(t, err) {
+		return
+	}
+	b = bytes.Replace(b, []byte("- name: mysql\n          image: mysql:5.7"), []byte("- name: mysql\n          image: mysql:5.8"), 1)
+	if !assert.NoError(t, os.WriteFile(statefulSet, b, 0600)) {
+		return
+	}
+	gitRunner, err := gitutil.NewLocalGitRunner(w.WorkspaceDirectory)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = gitRunner.Run(fake.CtxWithDefaultPrinter(), "add", ".")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = gitRunner.Run(fake.CtxWithDefaultPrinter(), "commit", "-m", "commit local package -- ds1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.NoError(t, g.ReplaceData(testutil.Dataset2)) {
+		return
+	}
+	_, err = g.Commit("modify upstream package -- ds2")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	updateCmd := update.NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+	updateCmd.Command.SetArgs([]string{g.RepoName, "--mark-conflicts"})
+	if !assert.NoError(t, updateCmd.Command.Execute()) {
+		return
+	}
+	b, err = os.ReadFile(statefulSet)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(b), "<<<<<<< local\n")
+	assert.Contains(t, string(b), "image: mysql:5.8")
+	assert.Contains(t, string(b), "image: mysql:8.0")
+	assert.Contains(t, string(b), ">>>>>>> upstream\n")
+
+	// other upstream changes are merged
+	b, err = os.ReadFile(filepath.Join(dest, "java", "java-service.resource.yaml"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(b), "port: 80\n")
+
+	// the package can't be updated until the conflicts are resolved
+	updateCmd = update.NewRunner(fake.CtxWithDefaultPrinter(), "kpt")
+	updateCmd.Command.SetArgs([]string{g.RepoName})
+	err = updateCmd.Command.Execute()
+	assert.ErrorContains(t, err, "package has unresolved conflicts in mysql/mysql-statefulset.resource.yaml")
+}
+
 func TestCmd_successUnCommitted(t *testing.T) {
 	g, w, clean := testutil.SetupRepoAndWorkspace(t, testutil.Content{
 		Data:   testutil.Dataset1,
@@ -30,17 +30,49 @@ import (
 )
 
 func NewFactory(cmd *cobra.Command, version string) cluster.Factory {
+	f, _ := NewFactoryWithContexts(cmd, version)
+	return f
+}
+
+// ContextFactoryFunc returns a factory for the named kubeconfig context.
+type ContextFactoryFunc func(kubeContext string) cluster.Factory
+
+// NewFactoryWithContexts is like NewFactory, and also returns a function
+// creating factories for other kubeconfig contexts. These use the values of
+// the kubeconfig flags of cmd, except for the flags selecting the cluster
+// and user, which come from the context.
+func NewFactoryWithContexts(cmd *cobra.Command, version string) (cluster.Factory, ContextFactoryFunc) {
 	flags := cmd.PersistentFlags()
 	kubeConfigFlags := genericclioptions.NewConfigFlags(true).
 		WithDeprecatedPasswordFlag()
 	kubeConfigFlags.AddFlags(flags)
 	UpdateQPS(kubeConfigFlags)
+	userAgent := fmt.Sprintf("kpt/%s", version)
 	userAgentKubeConfigFlags := &cfgflags.UserAgentKubeConfigFlags{
 		Delegate:  kubeConfigFlags,
-		UserAgent: fmt.Sprintf("kpt/%s", version),
+		UserAgent: userAgent,
 	}
 	cmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
-	return cluster.NewFactory(userAgentKubeConfigFlags)
+
+	contextFactory := func(kubeContext string) cluster.Factory {
+		contextFlags := genericclioptions.NewConfigFlags(true)
+		contextFlags.CacheDir = kubeConfigFlags.CacheDir
+		contextFlags.KubeConfig = kubeConfigFlags.KubeConfig
+		contextFlags.Context = &kubeContext
+		contextFlags.Namespace = kubeConfigFlags.Namespace
+		contextFlags.Insecure = kubeConfigFlags.Insecure
+		contextFlags.Impersonate = kubeConfigFlags.Impersonate
+		contextFlags.ImpersonateUID = kubeConfigFlags.ImpersonateUID
+		contextFlags.ImpersonateGroup = kubeConfigFlags.ImpersonateGroup
+		contextFlags.Timeout = kubeConfigFlags.Timeout
+		contextFlags.DisableCompression = kubeConfigFlags.DisableCompression
+		UpdateQPS(contextFlags)
+		return cluster.NewFactory(&cfgflags.UserAgentKubeConfigFlags{
+			Delegate:  contextFlags,
+			UserAgent: userAgent,
+		})
+	}
+	return cluster.NewFactory(userAgentKubeConfigFlags), contextFactory
 }
 
 // UpdateQPS modifies a genericclioptions.ConfigFlags to update the client-side
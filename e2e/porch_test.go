@@ -20,24 +20,93 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"flag"
 	"io/fs"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
 	"github.com/GoogleContainerTools/kpt/pkg/test/porch"
 	"github.com/google/go-cmp/cmp"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 const (
-	updateGoldenFiles = "UPDATE_GOLDEN_FILES"
-	testGitNamespace  = "test-git-namespace"
+	testGitNamespace = "test-git-namespace"
+
+	// inClusterE2E selects the in-cluster git server and github.com instead
+	// of the in-process git server preloaded with the fixtures in
+	// testdata/porch-blueprints.
+	inClusterE2E = "KPT_E2E_IN_CLUSTER_GIT"
+	// hermeticGitServerAddr sets the listen address of the in-process git
+	// server, which must be reachable from porch.
+	hermeticGitServerAddr = "KPT_E2E_GIT_SERVER_ADDR"
+	// kindNetwork is the docker network of kind clusters. Pods of kind
+	// clusters reach the host through its gateway.
+	kindNetwork = "kind"
+
+	inClusterGitServerURL = "http://git-server." + testGitNamespace + ".svc.cluster.local:8080"
+	testBlueprintsRepoURL = "https://github.com/platkrm/test-blueprints.git"
 )
 
+// upstreamCommitPattern matches the upstream commit recorded in Kptfiles,
+// which differs between the fixtures and the real test-blueprints repo.
+var upstreamCommitPattern = regexp.MustCompile(`commit: [0-9a-f]{40}`)
+
+// urlRewriter maps repository URLs used in the golden files to the URLs of
+// the git server the tests actually run against, and back.
+type urlRewriter struct {
+	forward, reverse *strings.Replacer
+	hermetic         bool
+}
+
+func newURLRewriter(pairs ...string) *urlRewriter {
+	reversed := make([]string, len(pairs))
+	for i := 0; i < len(pairs); i += 2 {
+		reversed[i], reversed[i+1] = pairs[i+1], pairs[i]
+	}
+	return &urlRewriter{
+		forward: strings.NewReplacer(pairs...),
+		reverse: strings.NewReplacer(reversed...),
+	}
+}
+
+func (r *urlRewriter) args(args []string) []string {
+	rewritten := make([]string, len(args))
+	for i, arg := range args {
+		rewritten[i] = r.forward.Replace(arg)
+	}
+	return rewritten
+}
+
+// output converts command output back into its golden form.
+func (r *urlRewriter) output(s string) string {
+	s = r.reverse.Replace(s)
+	if r.hermetic {
+		s = upstreamCommitPattern.ReplaceAllString(s, "commit: <commit>")
+	}
+	return s
+}
+
+// golden normalizes expected output the same way output normalizes actual
+// output.
+func (r *urlRewriter) golden(s string) string {
+	if r.hermetic {
+		s = upstreamCommitPattern.ReplaceAllString(s, "commit: <commit>")
+	}
+	return s
+}
+
+func init() {
+	flag.BoolVar(&porch.UpdateGoldenFiles, "update", false, "update the golden files instead of comparing against them")
+}
+
 func TestPorch(t *testing.T) {
 	abs, err := filepath.Abs(filepath.Join(".", "testdata", "porch"))
 	if err != nil {
@@ -53,7 +122,32 @@ func runUtilityCommand(t *testing.T, command string, args ...string) error {
 }
 
 func runTests(t *testing.T, path string) {
-	gitServerURL := startGitServer(t, path)
+	var gitServerURL string
+	var gs *porch.GitServer
+	var rewriter *urlRewriter
+	remote := porch.RemoteGitProviderFromEnv(t)
+	if remote != nil {
+		if os.Getenv(inClusterE2E) != "" {
+			t.Fatalf("%s and %s cannot be set together", inClusterE2E, porch.RemoteGitProviderEnv)
+		}
+		if porch.ShouldUpdateGoldenFiles() {
+			t.Fatalf("golden files cannot be updated with %s set; they must be generated with %s set", porch.RemoteGitProviderEnv, inClusterE2E)
+		}
+	} else if os.Getenv(inClusterE2E) != "" {
+		gitServerURL = startGitServer(t, path)
+		rewriter = newURLRewriter()
+	} else {
+		if porch.ShouldUpdateGoldenFiles() {
+			t.Fatalf("golden files cannot be updated against the in-process git server; they must be generated with %s set", inClusterE2E)
+		}
+		gs = startHermeticGitServer(t, filepath.Join(path, "..", "porch-blueprints"))
+		gitServerURL = gs.URL
+		rewriter = newURLRewriter(
+			testBlueprintsRepoURL, gs.RepoURL("test-blueprints.git"),
+			inClusterGitServerURL, gs.URL,
+		)
+		rewriter.hermetic = true
+	}
 	testCases := scanTestCases(t, path)
 
 	// remove any tmp files from previous test runs
@@ -71,30 +165,50 @@ func runTests(t *testing.T, path string) {
 			if tc.Skip != "" {
 				t.Skipf("Skipping test: %s", tc.Skip)
 			}
-			repoURL := gitServerURL + "/" + strings.ReplaceAll(tc.TestCase, "/", "-")
-			runTestCase(t, repoURL, tc)
+			repoName := strings.ReplaceAll(tc.TestCase, "/", "-")
+			var registerArgs []string
+			if remote != nil {
+				if tc.GitAuth != nil {
+					t.Skipf("Skipping test: repository credentials are only supported by the in-process git server")
+				}
+				// the default repo of the test is created on the provider,
+				// and the golden files keep referring to the in-cluster git
+				// server.
+				repoURL := remote.CreateRepo(t, repoName)
+				rewriter := newURLRewriter(inClusterGitServerURL+"/"+repoName, repoURL)
+				runTestCase(t, repoURL, remote.Auth().RegisterArgs(), rewriter, tc)
+				return
+			}
+			if tc.GitAuth != nil {
+				if gs == nil {
+					t.Skipf("Skipping test: repository credentials are only supported by the in-process git server")
+				}
+				gs.SetRepoAuth(repoName, *tc.GitAuth)
+				registerArgs = tc.GitAuth.RegisterArgs()
+			}
+			runTestCase(t, gitServerURL+"/"+repoName, registerArgs, rewriter, tc)
 		})
 	}
 }
 
-func runTestCase(t *testing.T, repoURL string, tc porch.TestCaseConfig) {
+func runTestCase(t *testing.T, repoURL string, registerArgs []string, rewriter *urlRewriter, tc porch.TestCaseConfig) {
 	porch.KubectlCreateNamespace(t, tc.TestCase)
 	t.Cleanup(func() {
 		porch.KubectlDeleteNamespace(t, tc.TestCase)
 	})
 
 	if tc.Repository != "" {
-		porch.RegisterRepository(t, repoURL, tc.TestCase, tc.Repository)
+		porch.RegisterRepository(t, repoURL, tc.TestCase, tc.Repository, registerArgs...)
 	}
 
 	for i := range tc.Commands {
 		time.Sleep(1 * time.Second)
 		command := &tc.Commands[i]
-		cmd := exec.Command("kpt", command.Args...)
+		cmd := exec.Command("kpt", rewriter.args(command.Args)...)
 
 		var stdout, stderr bytes.Buffer
 		if command.Stdin != "" {
-			cmd.Stdin = strings.NewReader(command.Stdin)
+			cmd.Stdin = strings.NewReader(rewriter.forward.Replace(command.Stdin))
 		}
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
@@ -107,18 +221,23 @@ func runTestCase(t *testing.T, repoURL string, tc porch.TestCaseConfig) {
 		}
 
 		cleanupStderr(t, &stderr)
+		gotStdout, gotStderr := rewriter.output(stdout.String()), rewriter.output(stderr.String())
+		if command.Golden != "" {
+			assertPackageGolden(t, gotStdout, filepath.Join(filepath.Dir(tc.ConfigFile), command.Golden))
+			gotStdout = ""
+		}
 
-		if os.Getenv(updateGoldenFiles) != "" {
-			updateCommand(command, err, stdout.String(), stderr.String())
+		if porch.ShouldUpdateGoldenFiles() {
+			updateCommand(command, err, gotStdout, gotStderr)
 		}
 
 		if got, want := exitCode(err), command.ExitCode; got != want {
 			t.Errorf("unexpected exit code from 'kpt %s'; got %d, want %d", strings.Join(command.Args, " "), got, want)
 		}
-		if got, want := stdout.String(), command.Stdout; got != want {
+		if got, want := gotStdout, rewriter.golden(command.Stdout); got != want {
 			t.Errorf("unexpected stdout content from 'kpt %s'; (-want, +got) %s", strings.Join(command.Args, " "), cmp.Diff(want, got))
 		}
-		if got, want := stderr.String(), command.Stderr; got != want {
+		if got, want := gotStderr, rewriter.golden(command.Stderr); got != want {
 			t.Errorf("unexpected stderr content from 'kpt %s'; (-want, +got) %s", strings.Join(command.Args, " "), cmp.Diff(want, got))
 		}
 
@@ -130,11 +249,24 @@ func runTestCase(t *testing.T, repoURL string, tc porch.TestCaseConfig) {
 		}
 	}
 
-	if os.Getenv(updateGoldenFiles) != "" {
+	if porch.ShouldUpdateGoldenFiles() {
 		porch.WriteTestCaseConfig(t, &tc)
 	}
 }
 
+// assertPackageGolden compares the package in the ResourceList a command
+// wrote to stdout against the golden directory. Upstream commits are
+// normalized, so that the golden packages hold for all git servers.
+func assertPackageGolden(t *testing.T, stdout, goldenDir string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "package")
+	stdout = upstreamCommitPattern.ReplaceAllString(stdout, "commit: <commit>")
+	if err := cmdutil.WriteToOutput(strings.NewReader(stdout), nil, dir); err != nil {
+		t.Fatalf("Failed to write package to %q: %v", dir, err)
+	}
+	porch.AssertDirGolden(t, dir, goldenDir)
+}
+
 // remove PASS lines from kpt fn eval, which includes a duration and will vary
 func cleanupStderr(t *testing.T, buf *bytes.Buffer) {
 	scanner := bufio.NewScanner(buf)
@@ -215,6 +347,44 @@ func startGitServer(t *testing.T, path string) string {
 	return gitServerURL
 }
 
+// startHermeticGitServer starts an in-process git server and loads each
+// directory under fixtures into a repository named after it.
+func startHermeticGitServer(t *testing.T, fixtures string) *porch.GitServer {
+	gs := porch.StartGitServer(t, hermeticGitServerListenAddr(t))
+
+	entries, err := os.ReadDir(fixtures)
+	if err != nil {
+		t.Fatalf("Failed to read git fixtures directory %q: %v", fixtures, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			gs.LoadFixture(t, e.Name()+".git", filepath.Join(fixtures, e.Name()))
+		}
+	}
+	return gs
+}
+
+// hermeticGitServerListenAddr returns the address the in-process git server
+// listens on. Unless set explicitly, it is a random port on the gateway of
+// the kind network, since a loopback address isn't reachable from porch.
+func hermeticGitServerListenAddr(t *testing.T) string {
+	if addr := os.Getenv(hermeticGitServerAddr); addr != "" {
+		return addr
+	}
+	out, err := exec.Command("docker", "network", "inspect", kindNetwork,
+		"--format", "{{range .IPAM.Config}}{{.Gateway}} {{end}}").Output()
+	if err == nil {
+		for _, gateway := range strings.Fields(string(out)) {
+			if ip := net.ParseIP(gateway); ip != nil && ip.To4() != nil {
+				return net.JoinHostPort(gateway, "0")
+			}
+		}
+	}
+	t.Fatalf("Failed to find the gateway of the %q docker network to serve git repositories to porch on; "+
+		"set %s to an address reachable from porch, or %s to use the in-cluster git server", kindNetwork, hermeticGitServerAddr, inClusterE2E)
+	return ""
+}
+
 func scanTestCases(t *testing.T, root string) []porch.TestCaseConfig {
 	testCases := []porch.TestCaseConfig{}
 
@@ -232,7 +402,8 @@ func scanTestCases(t *testing.T, root string) []porch.TestCaseConfig {
 		tc := porch.ReadTestCaseConfig(t, info.Name(), path)
 		testCases = append(testCases, tc)
 
-		return nil
+		// nested directories, like golden packages, belong to the test case.
+		return filepath.SkipDir
 	}); err != nil {
 		t.Fatalf("Failed to scan test cases: %v", err)
 	}
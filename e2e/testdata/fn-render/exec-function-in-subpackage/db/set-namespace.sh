@@ -0,0 +1,2 @@
+#!/bin/sh
+sed -e 's/namespace: staging/namespace: prod/'
@@ -0,0 +1,71 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/filters/replacement"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// ApplyReplacements is a builtin implementation of the apply-replacements
+// catalog function. It copies fields of source resources to fields of target
+// resources as configured by the `replacements` of the ApplyReplacements
+// function config, like kustomize replacements. Sources may be local config
+// resources.
+type ApplyReplacements struct{}
+
+// Process implements framework.ResourceListProcessor interface.
+func (ar *ApplyReplacements) Process(resourceList *framework.ResourceList) error {
+	f, err := replacementsFromConfig(resourceList.FunctionConfig)
+	if err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	items, err := f.Filter(resourceList.Items)
+	if err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	resourceList.Items = items
+	resourceList.Results = append(resourceList.Results, &framework.Result{
+		Message:  fmt.Sprintf("%d replacements applied", len(f.Replacements)),
+		Severity: framework.Info,
+	})
+	return nil
+}
+
+func replacementsFromConfig(fnConfig *yaml.RNode) (*replacement.Filter, error) {
+	if fnConfig == nil || fnConfig.IsNilOrEmpty() {
+		return nil, fmt.Errorf("function config of kind ApplyReplacements must be specified")
+	}
+	if fnConfig.GetKind() != "ApplyReplacements" {
+		return nil, fmt.Errorf("unknown function config kind %q, expected ApplyReplacements", fnConfig.GetKind())
+	}
+	s, err := fnConfig.String()
+	if err != nil {
+		return nil, err
+	}
+	f := &replacement.Filter{}
+	if err := yaml.Unmarshal([]byte(s), f); err != nil {
+		return nil, fmt.Errorf("invalid ApplyReplacements function config: %w", err)
+	}
+	if len(f.Replacements) == 0 {
+		return nil, fmt.Errorf("`replacements` must be specified in the function config")
+	}
+	return f, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// catalogImagePrefix is the prefix of the images of the functions in the
+// kpt function catalog.
+const catalogImagePrefix = "gcr.io/kpt-fn/"
+
+// RunFunc is the signature of a builtin function. It reads the function
+// input `resourceList` from r and writes the function output to w. What the
+// function image would write to stderr, like the output of starlark print
+// statements, is written to stderr.
+type RunFunc func(r io.Reader, w, stderr io.Writer) error
+
+// catalogFunctions returns the builtin implementations of catalog functions,
+// keyed by image name without the registry prefix and tag.
+func catalogFunctions(stderr io.Writer) map[string]framework.ResourceListProcessor {
+	return map[string]framework.ResourceListProcessor{
+		"set-namespace":      &SetNamespace{},
+		"set-labels":         &SetLabels{},
+		"set-annotations":    &SetAnnotations{},
+		"render-helm-chart":  &RenderHelmChart{},
+		"apply-replacements": &ApplyReplacements{},
+		"starlark":           &StarlarkRun{Stderr: stderr},
+	}
+}
+
+// compatibleVersions are the versions of the catalog functions whose
+// behaviour the builtin implementations match. Other versions differ in their
+// output, e.g. set-labels v0.1 does not set the labels of selectors, so they
+// run in a container.
+var compatibleVersions = map[string][]string{
+	"set-namespace":      {"v0.4.1"},
+	"set-labels":         {"v0.2.0"},
+	"set-annotations":    {"v0.1.4"},
+	"render-helm-chart":  {"v0.2.0"},
+	"apply-replacements": {"v0.1.1"},
+	"starlark":           {"v0.2.1", "v0.3.0", "v0.4.3"},
+}
+
+// LookupCatalogFunction returns the builtin implementation of the catalog
+// function with the given image, if there is one compatible with the image
+// tag. Images without a tag or pinned by digest never match, as the function
+// version they run is unknown or must not be substituted.
+func LookupCatalogFunction(image string) (RunFunc, bool) {
+	name, tag, found := catalogFunctionVersion(image)
+	if !found || !slices.Contains(compatibleVersions[name], tag) {
+		return nil, false
+	}
+	if _, found := catalogFunctions(nil)[name]; !found {
+		return nil, false
+	}
+	return func(r io.Reader, w, stderr io.Writer) error {
+		p := catalogFunctions(stderr)[name]
+		rw := &kio.ByteReadWriter{
+			Reader:                r,
+			Writer:                w,
+			KeepReaderAnnotations: true,
+		}
+		return framework.Execute(p, rw)
+	}, true
+}
+
+// catalogFunctionVersion returns the name and the tag of the catalog function
+// with the given image. It returns false if the image is not a catalog
+// function image, has no tag or has a digest.
+func catalogFunctionVersion(image string) (string, string, bool) {
+	if !strings.HasPrefix(image, catalogImagePrefix) || strings.Contains(image, "@") {
+		return "", "", false
+	}
+	name, tag, found := strings.Cut(strings.TrimPrefix(image, catalogImagePrefix), ":")
+	if !found {
+		return "", "", false
+	}
+	return name, tag, true
+}
+
+// targetResources returns the resources a catalog function operates on,
+// i.e. all resources except the Kptfile and local config resources.
+func targetResources(items []*yaml.RNode) []*yaml.RNode {
+	var targets []*yaml.RNode
+	for _, item := range items {
+		if item.GetKind() == kptfilev1.KptFileKind {
+			continue
+		}
+		if item.GetAnnotations()[filters.LocalConfigAnnotation] == "true" {
+			continue
+		}
+		targets = append(targets, item)
+	}
+	return targets
+}
+
+// errorResult returns the results reporting err as a function error.
+func errorResult(err error) framework.Results {
+	return framework.Results{
+		&framework.Result{
+			Message:  err.Error(),
+			Severity: framework.Error,
+		},
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCatalogFunction(t *testing.T) {
+	for image, want := range map[string]bool{
+		"gcr.io/kpt-fn/set-namespace:v0.4.1":            true,
+		"gcr.io/kpt-fn/starlark:v0.2.1":                 true,
+		"gcr.io/kpt-fn/starlark:v0.4.3":                 true,
+		"gcr.io/kpt-fn/set-namespace:v0.1.3":            false,
+		"gcr.io/kpt-fn/apply-replacements:v0.1":         false,
+		"gcr.io/kpt-fn/set-labels":                      false,
+		"gcr.io/kpt-fn/set-annotations@sha256:a":        false,
+		"gcr.io/kpt-fn/set-annotations:v0.1.4@sha256:a": false,
+		"gcr.io/kpt-fn/apply-setters:v0.2":              false,
+		"example.com/set-namespace:v0.4.1":              false,
+	} {
+		_, found := LookupCatalogFunction(image)
+		assert.Equal(t, want, found, image)
+	}
+}
+
+func TestCatalogFunctions(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		dir   string
+	}{
+		{
+			name:  "set-namespace should set the namespace of namespace scoped resources",
+			image: "gcr.io/kpt-fn/set-namespace:v0.4.1",
+			dir:   "set-namespace",
+		},
+		{
+			name:  "set-namespace should use the package name without function config",
+			image: "gcr.io/kpt-fn/set-namespace:v0.4.1",
+			dir:   "set-namespace-pkg-context",
+		},
+		{
+			name:  "set-labels should set labels on resources, templates and selectors",
+			image: "gcr.io/kpt-fn/set-labels:v0.2.0",
+			dir:   "set-labels",
+		},
+		{
+			name:  "set-annotations should set annotations on resources and templates",
+			image: "gcr.io/kpt-fn/set-annotations:v0.1.4",
+			dir:   "set-annotations",
+		},
+		{
+			name:  "apply-replacements should copy fields from local config sources",
+			image: "gcr.io/kpt-fn/apply-replacements:v0.1.1",
+			dir:   "apply-replacements",
+		},
+		{
+			name:  "starlark should run the program with the function config params",
+			image: "gcr.io/kpt-fn/starlark:v0.4.3",
+			dir:   "starlark",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			run, found := LookupCatalogFunction(test.image)
+			if !assert.True(t, found) {
+				return
+			}
+			out := &bytes.Buffer{}
+
+			in, err := os.ReadFile(filepath.Join("testdata", test.dir, "in.yaml"))
+			assert.NoError(t, err)
+
+			assert.NoError(t, run(bytes.NewReader(in), out, io.Discard))
+			exp, err := os.ReadFile(filepath.Join("testdata", test.dir, "out.yaml"))
+			assert.NoError(t, err)
+			if diff := cmp.Diff(string(exp), out.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCatalogFunctionErrors(t *testing.T) {
+	run, _ := LookupCatalogFunction("gcr.io/kpt-fn/set-labels:v0.2.0")
+	in := `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+functionConfig:
+  apiVersion: v1
+  kind: Secret
+  metadata:
+    name: labels
+`
+	out := &bytes.Buffer{}
+	err := run(bytes.NewBufferString(in), out, io.Discard)
+	assert.Error(t, err)
+	assert.Contains(t, out.String(), `unknown function config kind "Secret", expected ConfigMap or SetLabels`)
+}
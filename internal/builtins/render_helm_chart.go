@@ -0,0 +1,242 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// helmBin is the helm binary used to render charts.
+const helmBin = "helm"
+
+// RenderHelmChart is a builtin implementation of the render-helm-chart
+// catalog function. It renders the charts listed in the RenderHelmChart
+// function config with `helm template` and adds the rendered resources to
+// the package. Rendered resources replace the resources with the same kind,
+// namespace and name from a previous render, so rendering is idempotent.
+type RenderHelmChart struct{}
+
+type renderHelmChartConfig struct {
+	HelmCharts []helmChart `yaml:"helmCharts"`
+}
+
+type helmChart struct {
+	ChartArgs       helmChartArgs       `yaml:"chartArgs"`
+	TemplateOptions helmTemplateOptions `yaml:"templateOptions"`
+}
+
+type helmChartArgs struct {
+	// Name is the name of the chart.
+	Name string `yaml:"name"`
+	// Version is the version of the chart. Defaults to the latest version.
+	Version string `yaml:"version"`
+	// Repo is the URL of the chart repository, either a helm repository or
+	// an oci:// registry.
+	Repo string `yaml:"repo"`
+}
+
+type helmTemplateOptions struct {
+	ReleaseName string     `yaml:"releaseName"`
+	Namespace   string     `yaml:"namespace"`
+	IncludeCRDs bool       `yaml:"includeCRDs"`
+	SkipTests   bool       `yaml:"skipTests"`
+	KubeVersion string     `yaml:"kubeVersion"`
+	APIVersions []string   `yaml:"apiVersions"`
+	Values      helmValues `yaml:"values"`
+}
+
+type helmValues struct {
+	// ValuesInline are values passed to the chart.
+	ValuesInline map[string]interface{} `yaml:"valuesInline"`
+	// ValuesFiles are values files passed to helm, e.g. URLs.
+	ValuesFiles []string `yaml:"valuesFiles"`
+}
+
+// Process implements framework.ResourceListProcessor interface.
+func (rhc *RenderHelmChart) Process(resourceList *framework.ResourceList) error {
+	charts, err := helmChartsFromConfig(resourceList.FunctionConfig)
+	if err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+
+	for _, chart := range charts {
+		rendered, err := chart.render()
+		if err != nil {
+			resourceList.Results = errorResult(err)
+			return resourceList.Results
+		}
+		resourceList.Items = replaceResources(resourceList.Items, rendered)
+		resourceList.Results = append(resourceList.Results, &framework.Result{
+			Message:  fmt.Sprintf("chart %q rendered as release %q: %d resources", chart.ChartArgs.Name, chart.TemplateOptions.ReleaseName, len(rendered)),
+			Severity: framework.Info,
+		})
+	}
+	return nil
+}
+
+func helmChartsFromConfig(fnConfig *yaml.RNode) ([]helmChart, error) {
+	if fnConfig == nil || fnConfig.IsNilOrEmpty() {
+		return nil, fmt.Errorf("function config of kind RenderHelmChart must be specified")
+	}
+	if fnConfig.GetKind() != "RenderHelmChart" {
+		return nil, fmt.Errorf("unknown function config kind %q, expected RenderHelmChart", fnConfig.GetKind())
+	}
+	s, err := fnConfig.String()
+	if err != nil {
+		return nil, err
+	}
+	var cfg renderHelmChartConfig
+	if err := yaml.Unmarshal([]byte(s), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid RenderHelmChart function config: %w", err)
+	}
+	if len(cfg.HelmCharts) == 0 {
+		return nil, fmt.Errorf("`helmCharts` must be specified in the function config")
+	}
+	for i := range cfg.HelmCharts {
+		c := &cfg.HelmCharts[i]
+		if c.ChartArgs.Name == "" {
+			return nil, fmt.Errorf("`chartArgs.name` must be specified for all charts")
+		}
+		if c.TemplateOptions.ReleaseName == "" {
+			c.TemplateOptions.ReleaseName = c.ChartArgs.Name
+		}
+	}
+	return cfg.HelmCharts, nil
+}
+
+// args returns the arguments of `helm template` for the chart. Inline values
+// are read from valuesFile.
+func (c helmChart) args(valuesFile string) []string {
+	opts := c.TemplateOptions
+	args := []string{"template", opts.ReleaseName}
+	if strings.HasPrefix(c.ChartArgs.Repo, "oci://") {
+		args = append(args, strings.TrimSuffix(c.ChartArgs.Repo, "/")+"/"+c.ChartArgs.Name)
+	} else {
+		args = append(args, c.ChartArgs.Name)
+		if c.ChartArgs.Repo != "" {
+			args = append(args, "--repo", c.ChartArgs.Repo)
+		}
+	}
+	if c.ChartArgs.Version != "" {
+		args = append(args, "--version", c.ChartArgs.Version)
+	}
+	if opts.Namespace != "" {
+		args = append(args, "--namespace", opts.Namespace)
+	}
+	if opts.IncludeCRDs {
+		args = append(args, "--include-crds")
+	}
+	if opts.SkipTests {
+		args = append(args, "--skip-tests")
+	}
+	if opts.KubeVersion != "" {
+		args = append(args, "--kube-version", opts.KubeVersion)
+	}
+	for _, v := range opts.APIVersions {
+		args = append(args, "--api-versions", v)
+	}
+	for _, f := range opts.Values.ValuesFiles {
+		args = append(args, "--values", f)
+	}
+	if valuesFile != "" {
+		args = append(args, "--values", valuesFile)
+	}
+	return args
+}
+
+// render runs `helm template` for the chart and returns the rendered
+// resources.
+func (c helmChart) render() ([]*yaml.RNode, error) {
+	if _, err := exec.LookPath(helmBin); err != nil {
+		return nil, fmt.Errorf("render-helm-chart requires the %s binary to be installed: %w", helmBin, err)
+	}
+
+	var valuesFile string
+	if len(c.TemplateOptions.Values.ValuesInline) > 0 {
+		dir, err := os.MkdirTemp("", "kpt-helm-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		b, err := yaml.Marshal(c.TemplateOptions.Values.ValuesInline)
+		if err != nil {
+			return nil, err
+		}
+		valuesFile = filepath.Join(dir, "values.yaml")
+		if err := os.WriteFile(valuesFile, b, 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(helmBin, c.args(valuesFile)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to render chart %q: %w: %s", c.ChartArgs.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	nodes, err := kio.FromBytes(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resources rendered from chart %q: %w", c.ChartArgs.Name, err)
+	}
+	return nodes, nil
+}
+
+// replaceResources adds the rendered resources to items. A rendered resource
+// replaces the item with the same kind, namespace and name, and keeps the
+// file of the item it replaces.
+func replaceResources(items, rendered []*yaml.RNode) []*yaml.RNode {
+	for _, r := range rendered {
+		replaced := false
+		for i, item := range items {
+			if item.GetKind() != r.GetKind() || item.GetNamespace() != r.GetNamespace() || item.GetName() != r.GetName() {
+				continue
+			}
+			if err := copyPathAnnotations(item, r); err != nil {
+				continue
+			}
+			items[i] = r
+			replaced = true
+			break
+		}
+		if !replaced {
+			items = append(items, r)
+		}
+	}
+	return items
+}
+
+func copyPathAnnotations(from, to *yaml.RNode) error {
+	for _, a := range []string{kioutil.PathAnnotation, kioutil.IndexAnnotation} {
+		if v, found := from.GetAnnotations()[a]; found {
+			if err := to.PipeE(yaml.SetAnnotation(a, v)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
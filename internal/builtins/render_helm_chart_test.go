@@ -0,0 +1,168 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHelm renders a ConfigMap named after the release, holding the
+// values file helm was called with.
+const fakeHelm = `#!/bin/sh
+release=$2
+values=""
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--values" ]; then values=$2; fi
+  shift
+done
+cat <<EOF
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: $release
+data:
+  values: |
+$([ -n "$values" ] && sed 's/^/    /' "$values")
+EOF
+`
+
+func TestRenderHelmChart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake helm binary is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, helmBin), []byte(fakeHelm), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	run, found := LookupCatalogFunction("gcr.io/kpt-fn/render-helm-chart:v0.2.0")
+	if !assert.True(t, found) {
+		return
+	}
+	in := `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: hello
+      annotations:
+        internal.config.kubernetes.io/index: '0'
+        internal.config.kubernetes.io/path: 'hello.yaml'
+    data:
+      values: stale
+functionConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: RenderHelmChart
+  metadata:
+    name: render
+  helmCharts:
+    - chartArgs:
+        name: helloworld
+        version: 0.1.0
+        repo: https://charts.example.com
+      templateOptions:
+        releaseName: hello
+        values:
+          valuesInline:
+            replicas: 2
+    - chartArgs:
+        name: other
+        repo: oci://registry.example.com/charts
+`
+	out := &bytes.Buffer{}
+	assert.NoError(t, run(bytes.NewBufferString(in), out, io.Discard))
+	// The hello ConfigMap is replaced in place, and the other release is
+	// added.
+	assert.Contains(t, out.String(), `- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: hello
+    annotations:
+      internal.config.kubernetes.io/path: 'hello.yaml'
+      internal.config.kubernetes.io/index: '0'
+  data:
+    values: |
+      replicas: 2
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: other
+`)
+	assert.NotContains(t, out.String(), "stale")
+	assert.Contains(t, out.String(), `chart "helloworld" rendered as release "hello": 1 resources`)
+}
+
+func TestHelmChartArgs(t *testing.T) {
+	tests := map[string]struct {
+		chart helmChart
+		want  []string
+	}{
+		"helm repository": {
+			chart: helmChart{
+				ChartArgs: helmChartArgs{Name: "helloworld", Version: "0.1.0", Repo: "https://charts.example.com"},
+				TemplateOptions: helmTemplateOptions{
+					ReleaseName: "hello",
+					Namespace:   "prod",
+					IncludeCRDs: true,
+					SkipTests:   true,
+					APIVersions: []string{"monitoring.coreos.com/v1"},
+				},
+			},
+			want: []string{"template", "hello", "helloworld", "--repo", "https://charts.example.com", "--version", "0.1.0",
+				"--namespace", "prod", "--include-crds", "--skip-tests", "--api-versions", "monitoring.coreos.com/v1", "--values", "values.yaml"},
+		},
+		"oci registry": {
+			chart: helmChart{
+				ChartArgs:       helmChartArgs{Name: "helloworld", Repo: "oci://registry.example.com/charts/"},
+				TemplateOptions: helmTemplateOptions{ReleaseName: "hello"},
+			},
+			want: []string{"template", "hello", "oci://registry.example.com/charts/helloworld", "--values", "values.yaml"},
+		},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.chart.args("values.yaml"))
+		})
+	}
+}
+
+func TestRenderHelmChartErrors(t *testing.T) {
+	run, _ := LookupCatalogFunction("gcr.io/kpt-fn/render-helm-chart:v0.2.0")
+	in := `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+functionConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: RenderHelmChart
+  metadata:
+    name: render
+  helmCharts:
+    - templateOptions:
+        releaseName: hello
+`
+	out := &bytes.Buffer{}
+	assert.Error(t, run(bytes.NewBufferString(in), out, io.Discard))
+	assert.Contains(t, out.String(), "`chartArgs.name` must be specified for all charts")
+}
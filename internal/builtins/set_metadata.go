@@ -0,0 +1,143 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/filters/annotations"
+	"sigs.k8s.io/kustomize/api/filters/labels"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// podTemplateKinds are the kinds of the workload resources with a pod
+// template in spec.template.
+var podTemplateKinds = []resid.Gvk{
+	{Version: "v1", Kind: "ReplicationController"},
+	{Kind: "Deployment"},
+	{Kind: "ReplicaSet"},
+	{Kind: "DaemonSet"},
+	{Group: "apps", Kind: "StatefulSet"},
+	{Group: "batch", Kind: "Job"},
+}
+
+// metadataFieldSpecs returns the field specs of the labels or annotations
+// field of resources and their pod templates.
+func metadataFieldSpecs(field string) types.FsSlice {
+	fs := types.FsSlice{
+		{Path: "metadata/" + field, CreateIfNotPresent: true},
+		{Gvk: resid.Gvk{Group: "batch", Kind: "CronJob"}, Path: "spec/jobTemplate/metadata/" + field, CreateIfNotPresent: true},
+		{Gvk: resid.Gvk{Group: "batch", Kind: "CronJob"}, Path: "spec/jobTemplate/spec/template/metadata/" + field, CreateIfNotPresent: true},
+	}
+	for _, gvk := range podTemplateKinds {
+		fs = append(fs, types.FieldSpec{Gvk: gvk, Path: "spec/template/metadata/" + field, CreateIfNotPresent: true})
+	}
+	return fs
+}
+
+// selectorFieldSpecs are the label selectors that select the pods of
+// workload resources, which must match the labels of the pod templates.
+var selectorFieldSpecs = types.FsSlice{
+	{Gvk: resid.Gvk{Version: "v1", Kind: "Service"}, Path: "spec/selector", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Version: "v1", Kind: "ReplicationController"}, Path: "spec/selector", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Kind: "Deployment"}, Path: "spec/selector/matchLabels", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Kind: "ReplicaSet"}, Path: "spec/selector/matchLabels", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Kind: "DaemonSet"}, Path: "spec/selector/matchLabels", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Group: "apps", Kind: "StatefulSet"}, Path: "spec/selector/matchLabels", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Group: "policy", Kind: "PodDisruptionBudget"}, Path: "spec/selector/matchLabels"},
+	{Gvk: resid.Gvk{Group: "networking.k8s.io", Kind: "NetworkPolicy"}, Path: "spec/podSelector/matchLabels"},
+}
+
+// SetLabels is a builtin implementation of the set-labels catalog function.
+// It adds the labels of the function config, which is either a ConfigMap or
+// a SetLabels resource, to all resources, their pod templates and the
+// selectors for them.
+type SetLabels struct{}
+
+// Process implements framework.ResourceListProcessor interface.
+func (sl *SetLabels) Process(resourceList *framework.ResourceList) error {
+	l, err := metadataFromConfig(resourceList.FunctionConfig, "SetLabels", "labels")
+	if err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	fs := append(metadataFieldSpecs("labels"), selectorFieldSpecs...)
+	if _, err := (labels.Filter{Labels: l, FsSlice: fs}).Filter(targetResources(resourceList.Items)); err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	resourceList.Results = append(resourceList.Results, &framework.Result{
+		Message:  fmt.Sprintf("%d label(s) set on resources", len(l)),
+		Severity: framework.Info,
+	})
+	return nil
+}
+
+// SetAnnotations is a builtin implementation of the set-annotations catalog
+// function. It adds the annotations of the function config, which is either
+// a ConfigMap or a SetAnnotations resource, to all resources and their pod
+// templates.
+type SetAnnotations struct{}
+
+// Process implements framework.ResourceListProcessor interface.
+func (sa *SetAnnotations) Process(resourceList *framework.ResourceList) error {
+	a, err := metadataFromConfig(resourceList.FunctionConfig, "SetAnnotations", "annotations")
+	if err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	if _, err := (annotations.Filter{Annotations: a, FsSlice: metadataFieldSpecs("annotations")}).Filter(targetResources(resourceList.Items)); err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	resourceList.Results = append(resourceList.Results, &framework.Result{
+		Message:  fmt.Sprintf("%d annotation(s) set on resources", len(a)),
+		Severity: framework.Info,
+	})
+	return nil
+}
+
+// metadataFromConfig returns the key value pairs of the function config:
+// the data of a ConfigMap, or the given field of a resource of the given kind.
+func metadataFromConfig(fnConfig *yaml.RNode, kind, field string) (map[string]string, error) {
+	if fnConfig == nil || fnConfig.IsNilOrEmpty() {
+		return nil, fmt.Errorf("function config must be specified")
+	}
+
+	var m map[string]string
+	switch fnConfig.GetKind() {
+	case "ConfigMap":
+		m = fnConfig.GetDataMap()
+	case kind:
+		n, err := fnConfig.Pipe(yaml.Lookup(field))
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			if err := n.YNode().Decode(&m); err != nil {
+				return nil, fmt.Errorf("invalid `%s` in function config: %w", field, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown function config kind %q, expected ConfigMap or %s", fnConfig.GetKind(), kind)
+	}
+	if len(m) == 0 {
+		return nil, fmt.Errorf("`%s` must be specified in the function config", field)
+	}
+	return m, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/filters/namespace"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// namespaceFieldSpecs are the fields holding namespaces in addition to
+// metadata.namespace, which is set on all namespace scoped resources.
+var namespaceFieldSpecs = types.FsSlice{
+	{Gvk: resid.Gvk{Kind: "Namespace"}, Path: "metadata/name", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Group: "apiregistration.k8s.io", Kind: "APIService"}, Path: "spec/service/namespace", CreateIfNotPresent: true},
+	{Gvk: resid.Gvk{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}, Path: "spec/conversion/webhook/clientConfig/service/namespace"},
+}
+
+// SetNamespace is a builtin implementation of the set-namespace catalog
+// function. It sets the namespace of all namespace scoped resources to the
+// `namespace` of the function config, which is either a ConfigMap or a
+// SetNamespace resource. Without function config, the name of the package
+// from the package context is used.
+type SetNamespace struct{}
+
+// Process implements framework.ResourceListProcessor interface.
+func (sn *SetNamespace) Process(resourceList *framework.ResourceList) error {
+	ns, err := namespaceFromConfig(resourceList)
+	if err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+
+	if _, err := (namespace.Filter{
+		Namespace:              ns,
+		FsSlice:                namespaceFieldSpecs,
+		SetRoleBindingSubjects: namespace.DefaultSubjectsOnly,
+	}).Filter(targetResources(resourceList.Items)); err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	resourceList.Results = append(resourceList.Results, &framework.Result{
+		Message:  fmt.Sprintf("namespace %q set on resources", ns),
+		Severity: framework.Info,
+	})
+	return nil
+}
+
+func namespaceFromConfig(resourceList *framework.ResourceList) (string, error) {
+	fnConfig := resourceList.FunctionConfig
+	if fnConfig == nil || fnConfig.IsNilOrEmpty() {
+		for _, item := range resourceList.Items {
+			if resid.GvkFromNode(item).Equals(configMapGVK) && item.GetName() == PkgContextName {
+				if ns := item.GetDataMap()["name"]; ns != "" {
+					return ns, nil
+				}
+			}
+		}
+		return "", fmt.Errorf("`namespace` must be specified in the function config or the package context")
+	}
+
+	var ns string
+	switch fnConfig.GetKind() {
+	case "ConfigMap":
+		ns = fnConfig.GetDataMap()["namespace"]
+	case "SetNamespace":
+		n, err := fnConfig.Pipe(yaml.Lookup("namespace"))
+		if err != nil {
+			return "", err
+		}
+		if n != nil {
+			ns = yaml.GetValue(n)
+		}
+	default:
+		return "", fmt.Errorf("unknown function config kind %q, expected ConfigMap or SetNamespace", fnConfig.GetKind())
+	}
+	if ns == "" {
+		return "", fmt.Errorf("`namespace` must be specified in the function config")
+	}
+	return ns, nil
+}
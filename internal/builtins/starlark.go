@@ -0,0 +1,271 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// StarlarkTimeoutEnv overrides how long a starlark program may run, as
+	// a duration like "1m".
+	StarlarkTimeoutEnv = "KPT_FN_STARLARK_TIMEOUT"
+
+	defaultStarlarkTimeout = 30 * time.Second
+)
+
+// StarlarkRun is a builtin implementation of the starlark catalog function.
+// It runs the starlark program in the `source` of the StarlarkRun function
+// config, which reads and modifies the resources through
+// `ctx.resource_list`. The function config, including its `params`, is
+// available as `ctx.resource_list["functionConfig"]`.
+//
+// The program is sandboxed: it cannot load modules and has no access to the
+// environment, the filesystem or the network. It is cancelled if it runs for
+// longer than 30 seconds, or the duration in StarlarkTimeoutEnv.
+type StarlarkRun struct {
+	// Stderr receives the output of print statements.
+	Stderr io.Writer
+}
+
+func init() {
+	// like the function image, allow if and for statements at the top
+	// level of the program.
+	resolve.AllowGlobalReassign = true
+}
+
+// Process implements framework.ResourceListProcessor interface.
+func (sr *StarlarkRun) Process(resourceList *framework.ResourceList) error {
+	items, err := runStarlark(resourceList, sr.Stderr)
+	if err != nil {
+		resourceList.Results = errorResult(err)
+		return resourceList.Results
+	}
+	resourceList.Items = items
+	return nil
+}
+
+func runStarlark(resourceList *framework.ResourceList, stderr io.Writer) ([]*yaml.RNode, error) {
+	fnConfig := resourceList.FunctionConfig
+	if fnConfig == nil || fnConfig.IsNilOrEmpty() {
+		return nil, fmt.Errorf("function config of kind StarlarkRun must be specified")
+	}
+	if fnConfig.GetKind() != "StarlarkRun" {
+		return nil, fmt.Errorf("unknown function config kind %q, expected StarlarkRun", fnConfig.GetKind())
+	}
+	source, err := fnConfig.Pipe(yaml.Lookup("source"))
+	if err != nil {
+		return nil, err
+	}
+	if source.IsNilOrEmpty() || source.YNode().Value == "" {
+		return nil, fmt.Errorf("`source` must be specified in the function config")
+	}
+
+	var items []interface{}
+	for _, item := range resourceList.Items {
+		m, err := item.Map()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, m)
+	}
+	config, err := fnConfig.Map()
+	if err != nil {
+		return nil, err
+	}
+	rl, err := toStarlark(map[string]interface{}{
+		"apiVersion":     kio.ResourceListAPIVersion,
+		"kind":           kio.ResourceListKind,
+		"items":          items,
+		"functionConfig": config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	predeclared := starlark.StringDict{
+		"ctx": starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"resource_list": rl,
+		}),
+	}
+
+	timeout, err := starlarkTimeout()
+	if err != nil {
+		return nil, err
+	}
+	// a thread without a Load function cannot load modules.
+	thread := &starlark.Thread{
+		Name: fnConfig.GetName(),
+		Print: func(_ *starlark.Thread, msg string) {
+			if stderr != nil {
+				fmt.Fprintln(stderr, msg)
+			}
+		},
+	}
+	timer := time.AfterFunc(timeout, func() {
+		thread.Cancel(fmt.Sprintf("program exceeded the time limit of %s", timeout))
+	})
+	defer timer.Stop()
+	// the error is reported as is, like by the starlark function image.
+	if _, err := starlark.ExecFile(thread, fnConfig.GetName(), source.YNode().Value, predeclared); err != nil {
+		return nil, err
+	}
+
+	out, err := fromStarlark(rl)
+	if err != nil {
+		return nil, err
+	}
+	outItems, ok := out.(map[string]interface{})["items"].([]interface{})
+	if !ok && out.(map[string]interface{})["items"] != nil {
+		return nil, fmt.Errorf("`items` of the resource list must be a list")
+	}
+	var nodes []*yaml.RNode
+	for _, item := range outItems {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("items of the resource list must be dicts, got %T", item)
+		}
+		node, err := yaml.FromMap(m)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	// like with the starlark function image, the fields of the resources are
+	// sorted alphabetically.
+	return nodes, nil
+}
+
+// starlarkTimeout returns how long a starlark program may run.
+func starlarkTimeout() (time.Duration, error) {
+	v := os.Getenv(StarlarkTimeoutEnv)
+	if v == "" {
+		return defaultStarlarkTimeout, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration like 1m", StarlarkTimeoutEnv, v)
+	}
+	return d, nil
+}
+
+// toStarlark converts a value decoded from YAML into a starlark value.
+func toStarlark(v interface{}) (starlark.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case int:
+		return starlark.MakeInt(v), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case uint64:
+		return starlark.MakeUint64(v), nil
+	case float64:
+		return starlark.Float(v), nil
+	case string:
+		return starlark.String(v), nil
+	case []interface{}:
+		var elems []starlark.Value
+		for _, e := range v {
+			sv, err := toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, sv)
+		}
+		return starlark.NewList(elems), nil
+	case map[string]interface{}:
+		var keys []string
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		d := starlark.NewDict(len(v))
+		for _, k := range keys {
+			sv, err := toStarlark(v[k])
+			if err != nil {
+				return nil, err
+			}
+			if err := d.SetKey(starlark.String(k), sv); err != nil {
+				return nil, err
+			}
+		}
+		return d, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %v of type %T", v, v)
+	}
+}
+
+// fromStarlark converts a starlark value into a value that can be encoded
+// as YAML.
+func fromStarlark(v starlark.Value) (interface{}, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i, nil
+		}
+		return nil, fmt.Errorf("integer %s is out of range", v)
+	case starlark.Float:
+		if math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+			return nil, fmt.Errorf("float %s cannot be encoded", v)
+		}
+		return float64(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Indexable: // lists and tuples
+		var elems []interface{}
+		for i := 0; i < v.Len(); i++ {
+			e, err := fromStarlark(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, e)
+		}
+		return elems, nil
+	case *starlark.Dict:
+		m := map[string]interface{}{}
+		for _, item := range v.Items() {
+			k, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings, got %s", item[0].Type())
+			}
+			e, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, err
+			}
+			m[string(k)] = e
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %s of type %s", v, v.Type())
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builtins
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarlarkRun(t *testing.T) {
+	testCases := map[string]struct {
+		source         string
+		expected       string
+		expectedStderr string
+		expectErr      string
+	}{
+		"adds resources": {
+			source: `ctx.resource_list["items"].append({
+  "apiVersion": "v1",
+  "kind": "ConfigMap",
+  "metadata": {"name": "new"},
+  "data": {"count": str(len(ctx.resource_list["items"]))},
+})
+`,
+			expected: `items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: app
+- apiVersion: v1
+  data:
+    count: "1"
+  kind: ConfigMap
+  metadata:
+    name: new
+functionConfig:
+`,
+		},
+		"supports top-level statements and prints to stderr": {
+			source: `for item in ctx.resource_list["items"]:
+  print("found " + item["metadata"]["name"])
+`,
+			expected:       "name: app\n",
+			expectedStderr: "found app\n",
+		},
+		"cannot load modules": {
+			source:    `load("@stdlib//json", "json")`,
+			expectErr: "load not implemented",
+		},
+		"cannot read the environment": {
+			source:    `print(ctx.environment)`,
+			expectErr: "struct has no .environment attribute",
+		},
+		"is time limited": {
+			source: `def loop():
+  for i in range(1000000000):
+    pass
+
+loop()
+`,
+			expectErr: "program exceeded the time limit of 10ms",
+		},
+		"reports syntax errors like the function image": {
+			source:    "x = \n",
+			expectErr: "message: 'test:2:1: got newline, want primary expression'",
+		},
+		"rejects invalid items": {
+			source:    `ctx.resource_list["items"].append("not a resource")`,
+			expectErr: "items of the resource list must be dicts, got string",
+		},
+	}
+
+	t.Setenv(StarlarkTimeoutEnv, "10ms")
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			in := `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: app
+functionConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: StarlarkRun
+  metadata:
+    name: test
+  source: |
+` + indent(tc.source, "    ")
+			run, _ := LookupCatalogFunction("gcr.io/kpt-fn/starlark:v0.4.3")
+			out, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			err := run(strings.NewReader(in), out, stderr)
+			if tc.expectErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, out.String(), tc.expectErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Contains(t, out.String(), tc.expected)
+			assert.Equal(t, tc.expectedStderr, stderr.String())
+		})
+	}
+}
+
+func TestStarlarkTimeout(t *testing.T) {
+	timeout, err := starlarkTimeout()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultStarlarkTimeout, timeout)
+
+	t.Setenv(StarlarkTimeoutEnv, "2m")
+	timeout, err = starlarkTimeout()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, timeout)
+
+	t.Setenv(StarlarkTimeoutEnv, "forever")
+	_, err = starlarkTimeout()
+	assert.EqualError(t, err, `invalid KPT_FN_STARLARK_TIMEOUT "forever": must be a positive duration like 1m`)
+}
+
+func indent(s, prefix string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line != "" {
+			b.WriteString(prefix + line)
+		}
+	}
+	return b.String()
+}
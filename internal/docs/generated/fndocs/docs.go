@@ -21,15 +21,24 @@ Flags:
     Container image of the function e.g. ` + "`" + `gcr.io/kpt-fn/set-namespace:v0.1` + "`" + `.
     For convenience, if full image path is not specified, ` + "`" + `gcr.io/kpt-fn/` + "`" + ` is added as default prefix.
     e.g. instead of passing ` + "`" + `gcr.io/kpt-fn/set-namespace:v0.1` + "`" + ` you can pass ` + "`" + `set-namespace:v0.1` + "`" + `.
+  
+  --output, -o:
+    Write the documentation to stdout in a machine-readable format, either json
+    or yaml. The result holds the resolved image and the output of the function
+    for ` + "`" + `--help` + "`" + `.
 
 Environment Variables:
 
   KPT_FN_RUNTIME:
     The runtime to run kpt functions. It must be one of "docker", "podman" and "nerdctl".
+    If unset, the first of docker, podman and nerdctl found in PATH is used.
 `
 var DocExamples = `
   # display the documentation for image set-namespace:v0.1.1
   kpt fn doc -i set-namespace:v0.1.1
+
+  # display the documentation for image set-namespace:v0.1.1 as json
+  kpt fn doc -i set-namespace:v0.1.1 -o json
 `
 
 var EvalShort = `Execute function on resources`
@@ -130,26 +139,73 @@ Flags:
     3. OUT_DIR_PATH: output resources are written to provided directory.
        The provided directory must not already exist.
   
+  --prefer-builtin:
+    Execute the catalog functions set-namespace, set-labels, set-annotations,
+    apply-replacements, starlark and render-helm-chart by kpt itself instead of
+    in a container, if the image tag is a version the builtin implementation is
+    compatible with: set-namespace:v0.4.1, set-labels:v0.2.0,
+    set-annotations:v0.1.4, apply-replacements:v0.1.1, render-helm-chart:v0.2.0
+    and starlark:v0.2.1, v0.3.0 or v0.4.3. Images of other versions, and images
+    pinned by digest, always run in a container. If unspecified, the value is read
+    from KPT_FN_PREFER_BUILTIN.
+  
   --type, t;
     Specify the function type. Accept value ` + "`" + `mutator` + "`" + ` (default), ` + "`" + `validator` + "`" + `. 
     If used with ` + "`" + `--save` + "`" + `, this flag will save the evaluated function to the corresponding
      Kptfile section: ` + "`" + `.pipeline.mutators` + "`" + ` if type is ` + "`" + `mutator` + "`" + `; ` + "`" + `.pipeline.validators` + "`" + ` if type
       is ` + "`" + `validator` + "`" + `.
   
+  --read-only:
+    Run the container function with a read-only root filesystem. ` + "`" + `/tmp` + "`" + ` is
+    backed by a tmpfs so the function can still write temporary files.
+    By default it is disabled.
+  
   --results-dir:
     Path to a directory to write structured results. Directory will be created if
     it doesn't exist. Structured results emitted by the functions are aggregated and saved
     to ` + "`" + `results.yaml` + "`" + ` file in the specified directory.
     If not specified, no result files are written to the local filesystem.
+  
+  --results-format:
+    If specified, the command result holding the function results is
+    written to stdout in a machine-readable format, whether the function
+    succeeds or fails. Allowed values: json|yaml. Cannot be used with
+    ` + "`" + `--output stdout` + "`" + `, ` + "`" + `--output unwrap` + "`" + ` or resources read from stdin.
+    
+  --runtime:
+    The container runtime running container functions. It can be set to one of
+    docker, podman, nerdctl. If unspecified, the runtime is read from
+    KPT_FN_RUNTIME, and otherwise the first of docker, podman and nerdctl found
+    in PATH is used.
     
   --save, s:
     Save the function image and fn-config to Kptfile. Require ` + "`" + ` + "` + "`" + `" + ` + "`" + `--image` + "`" + ` + "` + "`" + `" + ` + "`" + `.
+  
+  --user:
+    Run the container function with this ` + "`" + `uid` + "`" + ` and optional ` + "`" + `gid` + "`" + `, in the
+    format ` + "`" + `UID[:GID]` + "`" + `, e.g. to match the owner of the files in a CI sandbox.
+    Cannot be used with ` + "`" + `--as-current-user` + "`" + `.
     
 
 Environment Variables:
 
   KPT_FN_RUNTIME:
     The runtime to run kpt functions. It must be one of "docker", "podman" and "nerdctl".
+    Overridden by --runtime. If unset, the first of docker, podman and nerdctl
+    found in PATH is used.
+  
+  KPT_FN_PREFER_BUILTIN:
+    If "true", catalog functions with a compatible builtin implementation are
+    executed by kpt itself instead of in a container, see --prefer-builtin.
+    Overridden by --prefer-builtin. Defaults to "true" on architectures for which no function images are
+    published (e.g. riscv64), and "false" otherwise. The builtin
+    render-helm-chart requires the helm binary to be installed.
+    The program of the builtin starlark function cannot load modules or access
+    the environment, filesystem or network, and is cancelled after 30 seconds.
+  
+  KPT_FN_STARLARK_TIMEOUT:
+    How long the program of the builtin starlark function may run, as a
+    duration like "1m". Defaults to "30s".
 `
 var EvalExamples = `
   # execute container my-fn on the resources in DIR directory and
@@ -222,7 +278,7 @@ var EvalExamples = `
 
   # execute container my-fn with podman on the resources in DIR directory and
   # write output back to DIR
-  $ KPT_FN_RUNTIME=podman kpt fn eval DIR -i gcr.io/example.com/my-fn
+  $ kpt fn eval DIR -i gcr.io/example.com/my-fn --runtime podman
 `
 
 var ExportShort = `Auto-generating function pipelines for different workflow orchestrators`
@@ -269,10 +325,23 @@ Args:
 
 Flags:
 
+  --allow-cluster-access:
+    Allow functions declaring ` + "`" + `clusterAccess: true` + "`" + ` in the Kptfile pipeline to
+    read from the cluster of the current kubeconfig context, e.g. to look up
+    existing Services or CRD schemas. Such functions are given a kubeconfig
+    holding only the current context, mounted read-only at
+    ` + "`" + `/var/run/kpt/kubeconfig` + "`" + ` with ` + "`" + `KUBECONFIG` + "`" + ` pointing to it, and use the host
+    network so clusters listening on localhost, e.g. kind clusters, are
+    reachable. Credentials that require a plugin on the host, e.g. an ` + "`" + `exec` + "`" + `
+    credential plugin, are not usable in the container. Rendering fails if a
+    function declares cluster access and this flag is not set. Default: ` + "`" + `false` + "`" + `.
+  
   --allow-exec:
     Allow executable binaries to run as function. Note that executable binaries
     can perform privileged operations on your system, so ensure that binaries
-    referred in the pipeline are trusted and safe to execute.
+    referred in the pipeline are trusted and safe to execute. Relative paths of
+    binaries are resolved against the current directory, unless
+    ` + "`" + `--resolve-exec-to-pkg` + "`" + ` is set.
   
   --allow-network:
     Allow functions to access network during pipeline execution. Default: ` + "`" + `false` + "`" + `. Note that this is applicable to container based functions only.
@@ -291,16 +360,77 @@ Flags:
     3. OUT_DIR_PATH: output resources are written to provided directory.
        The provided directory must not already exist.
   
+  --parallel:
+    The maximum number of package pipelines to run concurrently. Sibling
+    subpackages are independent, so if greater than 1, they are rendered in
+    parallel. The output and the function results are reported in the same
+    order as in sequential rendering. Default: ` + "`" + `1` + "`" + `.
+  
+  --prefer-builtin:
+    Execute the catalog functions set-namespace, set-labels, set-annotations,
+    apply-replacements, starlark and render-helm-chart by kpt itself instead of
+    in a container, if the image tag is a version the builtin implementation is
+    compatible with: set-namespace:v0.4.1, set-labels:v0.2.0,
+    set-annotations:v0.1.4, apply-replacements:v0.1.1, render-helm-chart:v0.2.0
+    and starlark:v0.2.1, v0.3.0 or v0.4.3. Images of other versions, and images
+    pinned by digest, always run in a container. If unspecified, the value is read
+    from KPT_FN_PREFER_BUILTIN.
+  
+  --report:
+    Write a report of the functions run to ` + "`" + `render-report.yaml` + "`" + ` in the results
+    directory, to find out which functions make the rendering slow. For each
+    function, the report holds the package, the wall-clock time and whether it
+    passed. For container functions, it also holds whether the image was already
+    present (` + "`" + `imageCache: hit|miss` + "`" + `) and the time spent pulling the image. The
+    report is updated after each function, so it can be inspected while the
+    rendering runs. Requires ` + "`" + `--results-dir` + "`" + `.
+  
+  --runtime:
+    The container runtime running container functions. It can be set to one of
+    docker, podman, nerdctl. If unspecified, the runtime is read from
+    KPT_FN_RUNTIME, and otherwise the first of docker, podman and nerdctl found
+    in PATH is used.
+  
+  --resolve-exec-to-pkg:
+    Resolve relative paths of binaries run as functions, e.g. ` + "`" + `./bin/fn` + "`" + `,
+    against the directory of the package declaring the function if they exist
+    there, and against the current directory otherwise. This lets a package ship
+    its own function binaries and be rendered from any directory. Default: ` + "`" + `false` + "`" + `.
+  
   --results-dir:
     Path to a directory to write structured results. Directory will be created if
     it doesn't exist. Structured results emitted by the functions are aggregated and saved
     to ` + "`" + `results.yaml` + "`" + ` file in the specified directory.
     If not specified, no result files are written to the local filesystem.
+  
+  --results-format:
+    If specified, the function results are written to stdout in a
+    machine-readable format, whether rendering succeeds or fails.
+    Allowed values: json|yaml|sarif
+    1. json, yaml: the command result holding the function results.
+    2. sarif: a SARIF log with a run per function, which CI systems can use
+       to annotate changes with validation findings.
+    Cannot be used with ` + "`" + `--output stdout` + "`" + ` or ` + "`" + `--output unwrap` + "`" + `.
 
 Environment Variables:
 
   KPT_FN_RUNTIME:
     The runtime to run kpt functions. It must be one of "docker", "podman" and "nerdctl".
+    Overridden by --runtime. If unset, the first of docker, podman and nerdctl
+    found in PATH is used.
+  
+  KPT_FN_PREFER_BUILTIN:
+    If "true", catalog functions with a compatible builtin implementation are
+    executed by kpt itself instead of in a container, see --prefer-builtin.
+    Overridden by --prefer-builtin. Defaults to "true" on architectures for which no function images are
+    published (e.g. riscv64), and "false" otherwise. The builtin
+    render-helm-chart requires the helm binary to be installed.
+    The program of the builtin starlark function cannot load modules or access
+    the environment, filesystem or network, and is cancelled after 30 seconds.
+  
+  KPT_FN_STARLARK_TIMEOUT:
+    How long the program of the builtin starlark function may run, as a
+    duration like "1m". Defaults to "30s".
 `
 var RenderExamples = `
   # Render the package in current directory
@@ -309,6 +439,9 @@ var RenderExamples = `
   # Render the package in current directory and save results in my-results-dir
   $ kpt fn render --results-dir my-results-dir
 
+  # Render the package and report the time taken by each function
+  $ kpt fn render --results-dir my-results-dir --report
+
   # Render my-package-dir
   $ kpt fn render my-package-dir
 
@@ -326,18 +459,32 @@ var RenderExamples = `
   | kpt fn eval - -i gcr.io/kpt-fn/set-annotations:v0.1.3 -o path/to/dir  -- foo=bar
 
   # Render my-package-dir with podman as runtime for functions
-  $ KPT_FN_RUNTIME=podman kpt fn render my-package-dir
+  $ kpt fn render my-package-dir --runtime podman
 
   # Render my-package-dir with network access enabled for functions
   $ kpt fn render --allow-network
+
+  # Render my-package-dir, allowing functions that declare cluster access to
+  # read from the cluster of the current kubeconfig context
+  $ kpt fn render my-package-dir --allow-cluster-access
+
+  # Render my-package-dir, running the pipelines of up to 8 subpackages
+  # concurrently
+  $ kpt fn render my-package-dir --parallel=8
 `
 
 var SinkShort = `Write resources to a local directory`
 var SinkLong = `
-  kpt fn sink DIR [flags]
+  kpt fn sink DIR | oci://IMAGE [flags]
   
   DIR:
     Path to a local directory to write resources to. The directory must not already exist.
+  
+  IMAGE:
+    Name of an image in an OCI registry to push the resources to, as a package
+    readable by ` + "`" + `kpt fn source` + "`" + ` and ` + "`" + `kpt pkg get` + "`" + `. The resources are stored in a
+    single image layer. Credentials for the registry are read from the docker
+    config.
 `
 var SinkExamples = `
   # read resources from DIR directory, execute my-fn on them and write the
@@ -345,17 +492,28 @@ var SinkExamples = `
   $ kpt fn source DIR |
     kpt fn eval - --image gcr.io/example.com/my-fn |
     kpt fn sink NEW_DIR
+
+  # read resources from DIR directory, execute my-fn on them and push the
+  # output as an image to an OCI registry.
+  $ kpt fn source DIR |
+    kpt fn eval - --image gcr.io/example.com/my-fn |
+    kpt fn sink oci://us-docker.pkg.dev/my-project/hydrated/app:v1
 `
 
 var SourceShort = `Source resources from a local directory`
 var SourceLong = `
-  kpt fn source [DIR] [flags]
+  kpt fn source [DIR | oci://IMAGE] [flags]
 
 Args:
 
   DIR:
     Path to the local directory containing resources. Defaults to the current
     working directory.
+  
+  IMAGE:
+    Name of an image in an OCI registry whose layers contain the files of the
+    package, referenced by tag or digest. Credentials for the registry are read
+    from the docker config.
 
 Flags:
 
@@ -381,4 +539,10 @@ var SourceExamples = `
   $ kpt fn source DIR |
     kpt fn eval - --image gcr.io/example.com/my-fn - |
     kpt fn sink DIR
+
+  # read resources from a package image in an OCI registry, execute my-fn on
+  # them and push the output as a new image.
+  $ kpt fn source oci://us-docker.pkg.dev/my-project/blueprints/app:v1 |
+    kpt fn eval - --image gcr.io/example.com/my-fn |
+    kpt fn sink oci://us-docker.pkg.dev/my-project/hydrated/app:v1
 `
@@ -21,6 +21,14 @@ Args:
 
 Flags:
 
+  --contexts:
+    Comma separated list of kubeconfig contexts to apply the package to. The
+    package is applied to the cluster of each context in turn, and each cluster
+    gets its own copy of the inventory object. A failure in one cluster doesn't
+    stop the others. A summary of the outcome in each cluster is printed at the
+    end, and the command fails if any of them failed. Cannot be used with
+    --context.
+  
   --dry-run:
     It true, kpt will validate the resources in the package and print which
     resources will be applied and which resources will be pruned, but no resources
@@ -68,6 +76,8 @@ Flags:
   --prune-propagation-policy:
     The propagation policy that should be used when pruning resources. The
     default value here is 'Background'. The other options are 'Foreground' and 'Orphan'.
+    Resources with the ` + "`" + `cli-utils.sigs.k8s.io/on-remove: keep` + "`" + ` annotation are
+    not pruned, but removed from the inventory.
   
   --prune-timeout:
     The threshold for how long to wait for all pruned resources to be
@@ -81,7 +91,10 @@ Flags:
     interrupted.
   
   --server-side:
-    Perform the apply operation server-side rather than client-side.
+    Perform the apply operation server-side rather than client-side. Server-side
+    apply tracks field ownership on the server instead of in the
+    last-applied-configuration annotation, so it isn't limited by the annotation
+    size and works alongside other controllers managing fields of the resources.
     Default value is false (client-side).
   
   --show-status-events:
@@ -100,6 +113,14 @@ var ApplyExamples = `
 
   # apply resources and specify how often to poll the cluster for resource status
   $ kpt live apply --reconcile-timeout=15m --poll-period=5s my-dir
+
+  # apply resources server-side as the platform-team field manager, and take
+  # ownership of fields managed by other field managers
+  $ kpt live apply --server-side --force-conflicts --field-manager=platform-team my-dir
+
+  # apply resources in the my-dir directory to the clusters of the edge-1 and
+  # edge-2 kubeconfig contexts
+  $ kpt live apply --contexts=edge-1,edge-2 my-dir
 `
 
 var DestroyShort = `Remove all previously applied resources in a package from the cluster`
@@ -116,6 +137,17 @@ Args:
 
 Flags:
 
+  --delete-propagation-policy:
+    The propagation policy that should be used when deleting resources. The
+    default value here is 'Background'. The other options are 'Foreground' and 'Orphan'.
+  
+  --delete-timeout:
+    The threshold for how long to wait for the resources of a wave to be removed
+    from the cluster, e.g. until their finalizers completed, before giving up.
+    If this flag is not set, kpt live destroy will wait until interrupted. In
+    most cases, it would also make sense to set the --delete-propagation-policy
+    to Foreground when this flag is set.
+  
   --dry-run:
     It true, kpt will print the resources that will be removed from the cluster,
     but no resources will be deleted.
@@ -220,7 +252,8 @@ Args:
 Flags:
 
   --dry-run:
-    Go through the steps of migration, but don't make any changes.
+    Go through the steps of migration, but don't make any changes. Moving an
+    inventory is still checked for conflicts with the cluster.
   
   --force:
     Forces the inventory values in the ResourceGroup manfiest to be updated,
@@ -229,7 +262,7 @@ Flags:
   --name:
     The name for the ResourceGroup resource that contains the inventory
     for the package. Defaults to the same name as the existing inventory
-    object.
+    object, or a generated name when migrating from a ConfigMap.
   
   --namespace:
     The namespace for the ResourceGroup resource that contains the inventory
@@ -239,6 +272,11 @@ Flags:
 var MigrateExamples = `
   # Migrate the package in the current directory.
   $ kpt live migrate
+
+  # Move the inventory of the package in the current directory to the
+  # ResourceGroup my-app in the namespace apps, showing the steps first.
+  $ kpt live migrate --name my-app --namespace apps --dry-run
+  $ kpt live migrate --name my-app --namespace apps
 `
 
 var StatusShort = `Display shows the status for the resources in the cluster`
@@ -309,6 +347,14 @@ Flags:
   --statuses:
     Filter for printing packages with specified statuses.
     For multiple statuses, use comma to separate them.
+  
+  --watch:
+    Stream the status transitions of the resources as JSON lines, one object per
+    line with the type, timestamp, inventoryName, group, kind, namespace, name,
+    status, previousStatus, message and the condition explaining a status other
+    than Current. A line is only written when the status or message of a
+    resource changes. Keeps watching until interrupted or the --timeout is
+    reached, unless --poll-until is set explicitly. Can't be used with --output.
 `
 var StatusExamples = `
   # Monitor status for the resources belonging to the package in the current
@@ -319,6 +365,10 @@ var StatusExamples = `
   # directory. Output in table format:
   $ kpt live status my-app --poll-until=forever --output=table
 
+  # Stream the status transitions of the resources in the my-app package as
+  # JSON lines until all of them are Current.
+  $ kpt live status my-app --watch --poll-until=current
+
   # Monitor status for the all resources on the cluster
   # with certain inventory names and under certain namespaces.
   $ kpt live status --inv-type remote --inv-names inv1,inv2 --namespaces ns1,ns2
@@ -15,4 +15,10 @@ kpt functionality is divided into following command groups:
 | [fn]    | generate, transform, validate packages using containerized functions. |
 | [live]  | deploy local configuration packages to a cluster.                     |
 | [alpha] | commands currently in alpha and might change without notice.          |
+
+The ` + "`" + `doctor` + "`" + ` command diagnoses the environment kpt runs in, see [doctor].
+
+kpt can be extended with plugins: any executable named ` + "`" + `kpt-<name>` + "`" + ` on the
+` + "`" + `PATH` + "`" + ` is invoked as ` + "`" + `kpt <name>` + "`" + `, unless ` + "`" + `<name>` + "`" + ` is a builtin command. See
+[plugins] for details.
 `
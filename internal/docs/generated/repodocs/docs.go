@@ -42,6 +42,14 @@ Flags:
     Branch within the repository where finalized packages are
     commited. The default is to use the 'main' branch.
   
+  --content:
+    Content stored in the repository. Must be one of Package or
+    Function. The default is Package.
+  
+  --create-branch:
+    Create the branch for finalized packages if it doesn't already
+    exist in the repository.
+  
   --deployment:
     Tags the repository as a deployment repository. Packages in
     a deployment repository are considered ready for deployment.
@@ -62,6 +70,15 @@ Flags:
   
   --repo-basic-password:
     Password for authenticating to a repository with basic auth.
+  
+  --repo-credential-helper:
+    Obtain the username and password for authenticating to a
+    repository with basic auth from the git credential helper
+    configured for the repository URL.
+
+When any of the authentication flags are provided, the credentials are
+stored in a Secret named ` + "`" + `<name>-auth` + "`" + ` in the namespace of the repository,
+and the repository references it.
 `
 var RegExamples = `
   # register a new git repository with the name generated from the URI.
@@ -69,6 +86,9 @@ var RegExamples = `
 
   # register a new deployment repository with name foo.
   $ kpt alpha repo register https://github.com/platkrm/blueprints-deployment.git --name=foo --deployment --namespace=bar
+
+  # register a private git repository using the credentials stored by the git credential helper.
+  $ kpt alpha repo register https://github.com/platkrm/private-blueprints.git --repo-credential-helper --namespace=default
 `
 
 var UnregShort = `Unregister a repository.`
@@ -14,16 +14,38 @@ Args:
 
   PACKAGE_REV_NAME...:
     The name of one or more package revisions. If more than
-    one is provided, they must be space-separated.
+    one is provided, they must be space-separated. Can't be combined with
+    --selector, --repository or --name.
+
+Flags:
+
+  --message, -m
+    Message describing the approval. It is recorded in the
+    ` + "`" + `porch.kpt.dev/approve-message` + "`" + ` annotation of the package revision.
+  
+  --name
+    Operate on all proposed revisions of the package with this name.
+  
+  --repository
+    Operate on all proposed package revisions in this repository.
+  
+  --selector, -l
+    Operate on all proposed package revisions matching this label selector,
+    e.g. ` + "`" + `app=foo,tier!=db` + "`" + `. When package revisions are selected, a failure
+    doesn't stop the operation: each failure is reported, followed by a
+    summary, and the command fails if any package revision failed.
 `
 var ApproveExamples = `
   # approve package revision blueprint-91817620282c133138177d16c981cf35f0083cad
   $ kpt alpha rpkg approve blueprint-91817620282c133138177d16c981cf35f0083cad --namespace=default
+
+  # approve all proposed package revisions labeled app=foo.
+  $ kpt alpha rpkg approve --selector=app=foo --namespace=default
 `
 
 var CloneShort = `Create a clone of an existing package revision.`
 var CloneLong = `
-  kpt alpha rpkg clone SOURCE_PACKAGE_REV TARGET_PACKAGE_NAME [flags]
+  kpt alpha rpkg clone [SOURCE_PACKAGE_REV] TARGET_PACKAGE_NAME [flags]
 
 Args:
 
@@ -39,6 +61,8 @@ Args:
         repository.
         blueprint-e982b2196b35a4f5e81e92f49a430fe463aa9f1a
   
+    If omitted, the source package revision is selected interactively.
+  
   TARGET_PACKAGE_NAME:
     The name of the new package.
   
@@ -66,6 +90,11 @@ Flags:
     Update strategy that should be used when updating the new
     package revision. Must be one of: resource-merge, fast-forward,  or 
     force-delete-replace. The default value is resource-merge.
+  
+  --search
+    Select the source package revision interactively, only offering
+    published package revisions whose repository, package name or
+    revision contain the given text.
 `
 var CloneExamples = `
   # clone the blueprint-e982b2196b35a4f5e81e92f49a430fe463aa9f1a package and create a new package revision called
@@ -75,6 +104,10 @@ var CloneExamples = `
   # clone the git repository at https://github.com/repo/blueprint.git at reference base/v0 and in directory base. The new
   # package revision will be created in repository blueprint and namespace default.
   $ kpt alpha rpkg clone https://github.com/repo/blueprint.git bar --repository=blueprint --ref=base/v0 --namespace=default --directory=base
+
+  # select one of the published revisions of packages matching "basens" and clone it to create a
+  # new package revision called foo in the deployments repository.
+  $ kpt alpha rpkg clone foo --repository=deployments --search=basens
 `
 
 var CopyShort = `Create a new package revision from an existing one.`
@@ -144,6 +177,57 @@ var GetExamples = `
   $ kpt alpha rpkg get --revision=v0
 `
 
+var ImpactShort = `Report how a blueprint revision would affect its downstream packages.`
+var ImpactLong = `
+  kpt alpha rpkg impact PACKAGE_REV_NAME [flags]
+
+Args:
+
+  PACKAGE_REV_NAME:
+    The name of the blueprint package revision, usually a proposed revision.
+
+Flags:
+
+  --output, -o
+    Write the results to stdout in a machine-readable format, ` + "`" + `json` + "`" + ` or
+    ` + "`" + `yaml` + "`" + `, instead of the human-oriented report.
+
+A downstream package revision is a package revision that was cloned from, or
+last updated to, a revision of the blueprint package. The latest published
+revision and the unpublished revisions of each downstream package are
+checked.
+
+For each downstream package revision, the update from its current upstream
+revision to the blueprint revision is merged in a temporary directory with
+the ` + "`" + `resource-merge` + "`" + ` strategy, and the result is one of:
+
+  up-to-date
+    The package revision is already based on the blueprint revision.
+  
+  clean
+    The update merges without losing any change.
+  
+  conflict
+    The update fails, or a file or resource field was changed both in the
+    package and upstream to different values, so one of the changes would be
+    lost. Each conflict is reported.
+  
+  error
+    The update couldn't be simulated, e.g. because the upstream revision the
+    package is based on no longer exists.
+
+The command fails if any downstream package revision would conflict or
+couldn't be checked, so it can gate the approval of a blueprint revision.
+`
+var ImpactExamples = `
+  # report the impact of blueprint-91817620282c133138177d16c981cf35f0083cad on
+  # its downstream packages.
+  $ kpt alpha rpkg impact blueprint-91817620282c133138177d16c981cf35f0083cad --namespace=default
+
+  # report the impact as JSON.
+  $ kpt alpha rpkg impact blueprint-91817620282c133138177d16c981cf35f0083cad --namespace=default -o json
+`
+
 var InitShort = `Initializes a new package in a repository.`
 var InitLong = `
   kpt alpha rpkg init PACKAGE_NAME [flags]
@@ -175,6 +259,54 @@ var InitExamples = `
   $ kpt alpha rpkg init foo --namespace=default --repository=blueprint --workspace=v1
 `
 
+var PromoteShort = `Promote a published package revision to another repository.`
+var PromoteLong = `
+  kpt alpha rpkg promote PACKAGE_REV_NAME --to REPOSITORY [flags]
+
+Args:
+
+  PACKAGE_REV_NAME:
+    The name of the published package revision to promote.
+
+Flags:
+
+  --to
+    Repository to promote the package revision to, usually a deployment
+    repository. Required.
+  
+  --name
+    Name of the package in the target repository. Defaults to the name of
+    the promoted package. The package must not exist in the target
+    repository yet; use ` + "`" + `copy` + "`" + ` and ` + "`" + `update` + "`" + ` to move an existing package to a
+    new upstream revision.
+  
+  --workspace
+    Workspace of the new package revision. The default value is v1.
+
+The new package revision has the following annotations recording its
+provenance:
+
+  porch.kpt.dev/promoted-from
+    The name of the promoted package revision.
+  
+  porch.kpt.dev/promoted-from-repository
+    The repository of the promoted package revision.
+  
+  porch.kpt.dev/promoted-from-package
+    The package of the promoted package revision.
+  
+  porch.kpt.dev/promoted-from-revision
+    The revision of the promoted package revision, e.g. v1.
+`
+var PromoteExamples = `
+  # promote the published package revision catalog-e982b2196b35a4f5e81e92f49a430fe463aa9f1a
+  # to the deployments repository.
+  $ kpt alpha rpkg promote catalog-e982b2196b35a4f5e81e92f49a430fe463aa9f1a --to=deployments --namespace=default
+
+  # promote a package revision as the basens-prod package in the deployments repository.
+  $ kpt alpha rpkg promote catalog-e982b2196b35a4f5e81e92f49a430fe463aa9f1a --to=deployments --name=basens-prod
+`
+
 var ProposeShort = `Propose that a package revision should be published.`
 var ProposeLong = `
   kpt alpha rpkg propose [PACKAGE_REV_NAME...] [flags]
@@ -183,11 +315,36 @@ Args:
 
   PACKAGE_REV_NAME...:
     The name of one or more package revisions. If more than
-    one is provided, they must be space-separated.
+    one is provided, they must be space-separated. Can't be combined with
+    --selector, --repository or --name.
+
+Flags:
+
+  --message, -m
+    Message describing the proposal. It is recorded in the
+    ` + "`" + `porch.kpt.dev/propose-message` + "`" + ` annotation of the package revision.
+  
+  --name
+    Operate on all draft revisions of the package with this name.
+  
+  --repository
+    Operate on all draft package revisions in this repository.
+  
+  --selector, -l
+    Operate on all draft package revisions matching this label selector,
+    e.g. ` + "`" + `app=foo,tier!=db` + "`" + `. When package revisions are selected, a failure
+    doesn't stop the operation: each failure is reported, followed by a
+    summary, and the command fails if any package revision failed.
 `
 var ProposeExamples = `
   # propose that package revision blueprint-91817620282c133138177d16c981cf35f0083cad should be finalized.
   $ kpt alpha rpkg propose blueprint-91817620282c133138177d16c981cf35f0083cad --namespace=default
+
+  # propose a package revision, recording why it is ready for review.
+  $ kpt alpha rpkg propose blueprint-91817620282c133138177d16c981cf35f0083cad --namespace=default --message="add resource quota"
+
+  # propose all draft package revisions in the deployments repository.
+  $ kpt alpha rpkg propose --repository=deployments --namespace=default
 `
 
 var ProposeDeleteShort = `Propose deletion of a published package revision.`
@@ -250,11 +407,35 @@ Args:
 
   PACKAGE_REV_NAME...:
     The name of one or more package revisions. If more than
-    one is provided, they must be space-separated.
+    one is provided, they must be space-separated. Can't be combined with
+    --selector, --repository or --name.
+
+Flags:
+
+  --message, -m
+    Message describing why the proposal was rejected. It is recorded in the
+    ` + "`" + `porch.kpt.dev/reject-message` + "`" + ` annotation of the package revision.
+  
+  --name
+    Operate on all proposed or deletion proposed revisions of the package
+    with this name.
+  
+  --repository
+    Operate on all proposed or deletion proposed package revisions in this
+    repository.
+  
+  --selector, -l
+    Operate on all proposed or deletion proposed package revisions matching
+    this label selector, e.g. ` + "`" + `app=foo,tier!=db` + "`" + `. When package revisions are selected, a failure
+    doesn't stop the operation: each failure is reported, followed by a
+    summary, and the command fails if any package revision failed.
 `
 var RejectExamples = `
   # reject the proposal for package revision blueprint-8f9a0c7bf29eb2cbac9476319cd1ad2e897be4f9
   $ kpt alpha rpkg reject blueprint-8f9a0c7bf29eb2cbac9476319cd1ad2e897be4f9 --namespace=default
+
+  # reject the proposals for all revisions of package app in the deployments repository.
+  $ kpt alpha rpkg reject --repository=deployments --name=app --namespace=default
 `
 
 var UpdateShort = `Update a downstream package revision to a more recent revision of its upstream package.`
@@ -0,0 +1,124 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fnruntime
+
+import (
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/builtins"
+	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+)
+
+const (
+	// PreferBuiltinEnv controls whether catalog functions with a builtin
+	// implementation are executed by kpt itself instead of in a container,
+	// unless set with the --prefer-builtin flag. If unset, builtin
+	// implementations are preferred on architectures for which no function
+	// images are published.
+	PreferBuiltinEnv = "KPT_FN_PREFER_BUILTIN"
+)
+
+// fnImageArchs are the architectures for which function images of the kpt
+// function catalog are published.
+var fnImageArchs = []string{"amd64", "arm64"}
+
+// PreferBuiltin returns true if catalog functions should be executed using
+// their builtin implementation by default, if they have one.
+func PreferBuiltin() bool {
+	if v, err := strconv.ParseBool(os.Getenv(PreferBuiltinEnv)); err == nil {
+		return v
+	}
+	return !HasFnImages()
+}
+
+// LookupBuiltin returns the builtin implementation of the function with the
+// given (resolved) image, if the function has one. Like for container
+// functions, what the function writes to stderr is recorded in fnResult.
+func LookupBuiltin(image string, fnResult *fnresult.Result) (func(r io.Reader, w io.Writer) error, bool) {
+	run, found := builtins.LookupCatalogFunction(image)
+	if !found {
+		return nil, false
+	}
+	return func(r io.Reader, w io.Writer) error {
+		var errSink bytes.Buffer
+		if err := run(r, w, &errSink); err != nil {
+			// the function images write the results of failures to stderr
+			// and exit with 1, so builtin failures are reported the same.
+			return &ExecError{
+				OriginalErr:    err,
+				ExitCode:       1,
+				Stderr:         errSink.String() + builtinStderr(err),
+				TruncateOutput: printer.TruncateOutput,
+			}
+		}
+		if errSink.Len() > 0 && fnResult != nil {
+			fnResult.Stderr = strings.TrimSuffix(errSink.String(), "\n")
+		}
+		return nil
+	}, true
+}
+
+// builtinStderr returns what the function image writes to stderr when it
+// fails with err.
+func builtinStderr(err error) string {
+	var results framework.Results
+	if !goerrors.As(err, &results) {
+		return err.Error()
+	}
+	var lines []string
+	for _, result := range results {
+		var id []string
+		if ref := result.ResourceRef; ref != nil {
+			for _, v := range []string{ref.APIVersion, ref.Kind, ref.Namespace, ref.Name} {
+				if v != "" {
+					id = append(id, v)
+				}
+			}
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", result.Severity, strings.Join(id, "/"), result.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HasFnImages returns true if function images of the kpt function catalog
+// are published for the current architecture.
+func HasFnImages() bool {
+	for _, arch := range fnImageArchs {
+		if runtime.GOARCH == arch {
+			return true
+		}
+	}
+	return false
+}
+
+// noFnImagesError adds a hint on how to run functions without containers to
+// err if no function images are published for the current architecture.
+func noFnImagesError(err error) error {
+	if HasFnImages() {
+		return err
+	}
+	return fmt.Errorf("%w; function images are not published for %s/%s, "+
+		"use functions with a builtin implementation or run wasm functions with --allow-alpha-wasm",
+		err, runtime.GOOS, runtime.GOARCH)
+}
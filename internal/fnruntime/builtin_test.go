@@ -0,0 +1,70 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fnruntime
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupBuiltin(t *testing.T) {
+	_, found := LookupBuiltin("gcr.io/kpt-fn/set-namespace:v0.4.1", nil)
+	assert.True(t, found)
+	_, found = LookupBuiltin("gcr.io/kpt-fn/set-namespace:v0.1.3", nil)
+	assert.False(t, found)
+	_, found = LookupBuiltin("gcr.io/kpt-fn/apply-setters:v0.2", nil)
+	assert.False(t, found)
+}
+
+func TestPreferBuiltin(t *testing.T) {
+	t.Setenv(PreferBuiltinEnv, "true")
+	var opts RunnerOptions
+	opts.InitDefaults()
+	assert.True(t, opts.PreferBuiltin)
+
+	t.Setenv(PreferBuiltinEnv, "false")
+	assert.False(t, PreferBuiltin())
+	t.Setenv(PreferBuiltinEnv, "")
+	assert.Equal(t, !HasFnImages(), PreferBuiltin())
+}
+
+func TestLookupBuiltin_failure(t *testing.T) {
+	run, found := LookupBuiltin("gcr.io/kpt-fn/starlark:v0.4.3", nil)
+	if !assert.True(t, found) {
+		return
+	}
+	in := `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+functionConfig:
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: StarlarkRun
+  metadata:
+    name: gen
+  source: |
+    x =
+`
+	err := run(strings.NewReader(in), &bytes.Buffer{})
+	// failures are reported like by the function image.
+	var execErr *ExecError
+	if assert.True(t, errors.As(err, &execErr)) {
+		assert.Equal(t, 1, execErr.ExitCode)
+		assert.Equal(t, "[error] : gen:2:1: got newline, want primary expression", execErr.Stderr)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fnruntime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+// ClusterKubeconfigPath is the path of the kubeconfig in the container of
+// functions with cluster access.
+const ClusterKubeconfigPath = "/var/run/kpt/kubeconfig"
+
+// WithClusterAccess gives the container function access to the cluster of
+// the kubeconfig at kubeconfigPath. The kubeconfig is mounted read-only and
+// the container uses the host network, so clusters listening on localhost,
+// e.g. kind clusters, are reachable.
+func (f *ContainerFn) WithClusterAccess(kubeconfigPath string) {
+	f.Perm.AllowNetwork = true
+	f.StorageMounts = append(f.StorageMounts, runtimeutil.StorageMount{
+		MountType: "bind",
+		Src:       kubeconfigPath,
+		DstPath:   ClusterKubeconfigPath,
+	})
+	f.Env = append(f.Env, "KUBECONFIG="+ClusterKubeconfigPath)
+}
+
+// WriteClusterKubeconfig writes a kubeconfig for functions with cluster
+// access to a new temporary directory. The kubeconfig only holds the current
+// context of the user's kubeconfig, with its credentials embedded. It
+// returns the path of the kubeconfig and a function removing it.
+func WriteClusterKubeconfig() (string, func(), error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot load kubeconfig: %w", err)
+	}
+	if config.CurrentContext == "" {
+		return "", nil, fmt.Errorf("cluster access requires a current context in the kubeconfig")
+	}
+	if err := clientcmdapi.MinifyConfig(&config); err != nil {
+		return "", nil, fmt.Errorf("cannot scope kubeconfig to context %q: %w", config.CurrentContext, err)
+	}
+	if err := clientcmdapi.FlattenConfig(&config); err != nil {
+		return "", nil, fmt.Errorf("cannot embed kubeconfig credentials: %w", err)
+	}
+	b, err := clientcmd.Write(config)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "kpt-cluster-access-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	path := filepath.Join(dir, "kubeconfig")
+	// Functions run as nobody, so the kubeconfig must be readable by all. The
+	// temporary directory is only accessible to the user.
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return path, cleanup, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fnruntime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+current-context: kind
+contexts:
+- name: kind
+  context:
+    cluster: kind
+    user: kind
+- name: prod
+  context:
+    cluster: prod
+    user: prod
+clusters:
+- name: kind
+  cluster:
+    server: https://127.0.0.1:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com
+users:
+- name: kind
+  user:
+    token: kind-token
+- name: prod
+  user:
+    token: prod-token
+`
+
+func TestWithClusterAccess(t *testing.T) {
+	fn := &ContainerFn{Image: "gcr.io/kpt-fn/lookup"}
+	fn.WithClusterAccess("/tmp/kubeconfig")
+	cmd, cancel := fn.getCmd(dockerBin, fn.ImagePullPolicy)
+	defer cancel()
+
+	assert.Contains(t, cmd.Args, "host")
+	assert.Contains(t, cmd.Args, "type=bind,source=/tmp/kubeconfig,target="+ClusterKubeconfigPath+",readonly")
+	assert.Contains(t, cmd.Args, "KUBECONFIG="+ClusterKubeconfigPath)
+}
+
+func TestWriteClusterKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0600))
+	t.Setenv(clientcmd.RecommendedConfigPathEnvVar, path)
+
+	scoped, cleanup, err := WriteClusterKubeconfig()
+	require.NoError(t, err)
+
+	config, err := clientcmd.LoadFromFile(scoped)
+	require.NoError(t, err)
+	assert.Equal(t, "kind", config.CurrentContext)
+	assert.Len(t, config.Contexts, 1)
+	assert.Len(t, config.Clusters, 1)
+	if assert.Contains(t, config.AuthInfos, "kind") {
+		assert.Equal(t, "kind-token", config.AuthInfos["kind"].Token)
+	}
+
+	cleanup()
+	_, err = os.Stat(scoped)
+	assert.True(t, os.IsNotExist(err))
+}
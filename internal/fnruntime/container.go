@@ -30,6 +30,7 @@ import (
 
 	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	"github.com/spf13/pflag"
 	"golang.org/x/mod/semver"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
 )
@@ -61,6 +62,50 @@ const (
 
 type ContainerRuntime string
 
+var allContainerRuntimes = []ContainerRuntime{
+	Docker,
+	Podman,
+	Nerdctl,
+}
+
+// ContainerRuntime can be used in pflag
+var _ pflag.Value = ((*ContainerRuntime)(nil))
+
+// String implements pflag.Value and fmt.Stringer
+func (r *ContainerRuntime) String() string {
+	return string(*r)
+}
+
+// Set implements pflag.Value
+func (r *ContainerRuntime) Set(v string) error {
+	l := strings.ToLower(v)
+	for _, c := range allContainerRuntimes {
+		if string(c) == l {
+			*r = c
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(r.AllStrings(), ", "))
+}
+
+func (r *ContainerRuntime) AllStrings() []string {
+	var allStrings []string
+	for _, c := range allContainerRuntimes {
+		allStrings = append(allStrings, string(c))
+	}
+	return allStrings
+}
+
+// HelpAllowedValues builds help text for the allowed values
+func (r *ContainerRuntime) HelpAllowedValues() string {
+	return "(one of " + strings.Join(r.AllStrings(), ", ") + ")"
+}
+
+// Type implements pflag.Value
+func (r *ContainerRuntime) Type() string {
+	return "ContainerRuntime"
+}
+
 // ContainerFnPermission contains the permission of container
 // function such as network access.
 type ContainerFnPermission struct {
@@ -85,6 +130,10 @@ type ContainerFn struct {
 	// used to run the container in format userId:groupId.
 	// If it's empty, "nobody" will be used.
 	UIDGID string
+	// ReadOnlyRootfs mounts the root filesystem of the container read-only.
+	// /tmp is then backed by a tmpfs so functions can still write
+	// temporary files.
+	ReadOnlyRootfs bool
 	// StorageMounts are the storage or directories to mount
 	// into the container
 	StorageMounts []runtimeutil.StorageMount
@@ -93,6 +142,12 @@ type ContainerFn struct {
 	// FnResult is used to store the information about the result from
 	// the function.
 	FnResult *fnresult.Result
+	// ImageTiming records the image cache status and the pull time of the
+	// image if set. The image is then pulled before running the container.
+	ImageTiming *ImageTiming
+	// Runtime is the container runtime running the function. If it's empty,
+	// the runtime is read from the KPT_FN_RUNTIME env var or detected.
+	Runtime ContainerRuntime
 }
 
 func (r ContainerRuntime) GetBin() string {
@@ -110,17 +165,22 @@ func (r ContainerRuntime) GetBin() string {
 // It reads the input from the given reader and writes the output
 // to the provided writer.
 func (f *ContainerFn) Run(reader io.Reader, writer io.Writer) error {
-	// If the env var is empty, stringToContainerRuntime defaults it to docker.
-	runtime, err := StringToContainerRuntime(os.Getenv(ContainerRuntimeEnv))
-	if err != nil {
-		return err
+	runtime := f.Runtime
+	if runtime == "" {
+		var err error
+		runtime, err = GetContainerRuntime()
+		if err != nil {
+			return err
+		}
 	}
 
+	var err error
+
 	checkContainerRuntimeOnce.Do(func() {
 		err = ContainerRuntimeAvailable(runtime)
 	})
 	if err != nil {
-		return err
+		return noFnImagesError(err)
 	}
 
 	switch runtime {
@@ -134,8 +194,12 @@ func (f *ContainerFn) Run(reader io.Reader, writer io.Writer) error {
 }
 
 func (f *ContainerFn) runCLI(reader io.Reader, writer io.Writer, bin string, filterCLIOutputFn func(io.Reader) string) error {
+	pullPolicy := f.ImagePullPolicy
+	if f.ImageTiming != nil && f.pullImage(bin) {
+		pullPolicy = NeverPull
+	}
 	errSink := bytes.Buffer{}
-	cmd, cancel := f.getCmd(bin)
+	cmd, cancel := f.getCmd(bin, pullPolicy)
 	defer cancel()
 	cmd.Stdin = reader
 	cmd.Stdout = writer
@@ -160,9 +224,30 @@ func (f *ContainerFn) runCLI(reader io.Reader, writer io.Writer, bin string, fil
 	return nil
 }
 
+// pullImage records the image cache status in f.ImageTiming and pulls the
+// image if the pull policy requires it, recording the pull time. It returns
+// true if the image is present afterwards, so the container is run without
+// pulling again. Failed pulls are left to the container run to report.
+func (f *ContainerFn) pullImage(bin string) bool {
+	present := exec.Command(bin, "image", "inspect", f.Image).Run() == nil
+	f.ImageTiming.Cache = ImageCacheMiss
+	if present {
+		f.ImageTiming.Cache = ImageCacheHit
+	}
+	if f.ImagePullPolicy == NeverPull || (present && f.ImagePullPolicy != AlwaysPull) {
+		return present
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultLongTimeout)
+	defer cancel()
+	t0 := time.Now()
+	err := exec.CommandContext(ctx, bin, "pull", f.Image).Run()
+	f.ImageTiming.Pull = time.Since(t0)
+	return err == nil
+}
+
 // getCmd assembles a command for docker, podman or nerdctl. The input binName
 // is expected to be one of "docker", "podman" and "nerdctl".
-func (f *ContainerFn) getCmd(binName string) (*exec.Cmd, context.CancelFunc) {
+func (f *ContainerFn) getCmd(binName string, pullPolicy ImagePullPolicy) (*exec.Cmd, context.CancelFunc) {
 	network := networkNameNone
 	if f.Perm.AllowNetwork {
 		network = networkNameHost
@@ -178,8 +263,11 @@ func (f *ContainerFn) getCmd(binName string) (*exec.Cmd, context.CancelFunc) {
 		"--user", uidgid,
 		"--security-opt=no-new-privileges",
 	}
+	if f.ReadOnlyRootfs {
+		args = append(args, "--read-only", "--tmpfs", "/tmp")
+	}
 
-	switch f.ImagePullPolicy {
+	switch pullPolicy {
 	case NeverPull:
 		args = append(args, "--pull", "never")
 	case AlwaysPull:
@@ -385,8 +473,29 @@ func StringToContainerRuntime(v string) (ContainerRuntime, error) {
 	case "":
 		return Docker, nil
 	default:
-		return "", fmt.Errorf("unsupported runtime: %q the runtime must be either %s or %s", v, Docker, Podman)
+		return "", fmt.Errorf("unsupported runtime: %q the runtime must be one of %s, %s or %s", v, Docker, Podman, Nerdctl)
+	}
+}
+
+// GetContainerRuntime returns the container runtime set by the KPT_FN_RUNTIME
+// env var, or detects it if the env var is empty.
+func GetContainerRuntime() (ContainerRuntime, error) {
+	if v := os.Getenv(ContainerRuntimeEnv); v != "" {
+		return StringToContainerRuntime(v)
+	}
+	return DetectContainerRuntime(exec.LookPath), nil
+}
+
+// DetectContainerRuntime returns the first of docker, podman and nerdctl whose
+// binary is found by lookPath. It defaults to docker if none is found, so the
+// error running functions explains how to install it.
+func DetectContainerRuntime(lookPath func(file string) (string, error)) ContainerRuntime {
+	for _, runtime := range allContainerRuntimes {
+		if _, err := lookPath(runtime.GetBin()); err == nil {
+			return runtime
+		}
 	}
+	return Docker
 }
 
 func ContainerRuntimeAvailable(runtime ContainerRuntime) error {
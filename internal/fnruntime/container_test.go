@@ -75,6 +75,25 @@ func TestContainerFn(t *testing.T) {
 	}
 }
 
+func TestContainerFnGetCmd(t *testing.T) {
+	f := &ContainerFn{
+		Image:          "gcr.io/kpt-fn/set-labels:v0.1",
+		UIDGID:         "1000:1000",
+		ReadOnlyRootfs: true,
+	}
+	cmd, cancel := f.getCmd("docker", IfNotPresentPull)
+	defer cancel()
+	assert.Equal(t, []string{
+		"docker", "run", "--rm", "-i",
+		"--network", "none",
+		"--user", "1000:1000",
+		"--security-opt=no-new-privileges",
+		"--read-only", "--tmpfs", "/tmp",
+		"--pull", "missing",
+		"gcr.io/kpt-fn/set-labels:v0.1",
+	}, cmd.Args)
+}
+
 func TestIsSupportedDockerVersion(t *testing.T) {
 	tests := []struct {
 		name   string
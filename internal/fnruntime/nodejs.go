@@ -25,6 +25,8 @@ import (
 
 const (
 	WasmPathEnv = "KPT_FN_WASM_PATH"
+
+	nodejsBin = "node"
 )
 
 type WasmNodejsFn struct {
@@ -54,7 +56,7 @@ func NewNodejsFn(loader WasmLoader) (*WasmNodejsFn, error) {
 
 	f := &WasmNodejsFn{
 		NodeJsRunner: &ExecFn{
-			Path: "node",
+			Path: nodejsBin,
 			Args: []string{jsPath},
 			Env: map[string]string{
 				WasmPathEnv: wasmFile,
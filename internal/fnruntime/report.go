@@ -0,0 +1,135 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fnruntime
+
+import (
+	"bytes"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/internal/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// ReportFileName is the name of the render report in the results directory.
+const ReportFileName = "render-report.yaml"
+
+// Image cache status of container functions in the report.
+const (
+	ImageCacheHit  = "hit"
+	ImageCacheMiss = "miss"
+)
+
+// ImageTiming records the image pull of a container function.
+type ImageTiming struct {
+	// Cache is ImageCacheHit if the image was present before the function
+	// ran, ImageCacheMiss otherwise.
+	Cache string
+	// Pull is the time spent pulling the image.
+	Pull time.Duration
+}
+
+// StepReport is the report of a function run.
+type StepReport struct {
+	// Package is the path of the package running the function, relative to
+	// the root package.
+	Package string `yaml:"package"`
+	Image   string `yaml:"image,omitempty"`
+	Exec    string `yaml:"exec,omitempty"`
+	// Status is pass or fail.
+	Status string `yaml:"status"`
+	// Duration is the wall-clock time of the function run, including the
+	// image pull.
+	Duration string `yaml:"duration"`
+	// ImagePull is the time spent pulling the image of container functions.
+	ImagePull  string `yaml:"imagePull,omitempty"`
+	ImageCache string `yaml:"imageCache,omitempty"`
+}
+
+// Report records the function runs of a render. The report is written to
+// the results directory after each function run, so the progress of a slow
+// render can be followed while it runs.
+type Report struct {
+	fsys    filesys.FileSystem
+	path    string
+	rootPkg string
+
+	mu    sync.Mutex
+	steps []StepReport
+}
+
+// NewReport returns a report written to resultsDir for the render of the
+// package at rootPkg.
+func NewReport(fsys filesys.FileSystem, resultsDir, rootPkg string) *Report {
+	return &Report{
+		fsys:    fsys,
+		path:    filepath.Join(resultsDir, ReportFileName),
+		rootPkg: rootPkg,
+	}
+}
+
+// Path returns the path of the report file.
+func (r *Report) Path() string {
+	return r.path
+}
+
+// add records a function run and writes the report.
+func (r *Report) add(pkgPath types.UniquePath, fr *FunctionRunner, d time.Duration, runErr error) error {
+	step := StepReport{
+		Package:  ".",
+		Image:    fr.fnResult.Image,
+		Exec:     fr.fnResult.ExecPath,
+		Status:   "pass",
+		Duration: d.Truncate(time.Millisecond).String(),
+	}
+	if runErr != nil {
+		step.Status = "fail"
+	}
+	if rel, err := filepath.Rel(r.rootPkg, string(pkgPath)); err == nil && !pkgPath.Empty() {
+		step.Package = filepath.ToSlash(rel)
+	}
+	if fr.imageTiming != nil && fr.imageTiming.Cache != "" {
+		step.ImageCache = fr.imageTiming.Cache
+		if fr.imageTiming.Pull > 0 {
+			step.ImagePull = fr.imageTiming.Pull.Truncate(time.Millisecond).String()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+	return r.write()
+}
+
+// Write writes the report to the results directory.
+func (r *Report) Write() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.write()
+}
+
+func (r *Report) write() error {
+	out := &bytes.Buffer{}
+	// use kyaml encoder to ensure consistent indentation
+	e := yaml.NewEncoderWithOptions(out, &yaml.EncoderOptions{SeqIndent: yaml.WideSequenceStyle})
+	if err := e.Encode(struct {
+		Steps []StepReport `yaml:"steps"`
+	}{Steps: r.steps}); err != nil {
+		return err
+	}
+	return r.fsys.WriteFile(r.path, out.Bytes())
+}
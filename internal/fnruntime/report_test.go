@@ -0,0 +1,72 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fnruntime
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/kpt/internal/types"
+	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+func TestReport(t *testing.T) {
+	fsys := filesys.MakeFsInMemory()
+	report := NewReport(fsys, "/results", "/pkg")
+	ctx := fake.CtxWithDefaultPrinter()
+
+	run := func(pkgPath string, result *fnresult.Result, timing *ImageTiming, fail bool) {
+		fltr := &runtimeutil.FunctionFilter{
+			Run: func(r io.Reader, w io.Writer) error {
+				if fail {
+					return fmt.Errorf("failed")
+				}
+				_, err := io.Copy(w, r)
+				return err
+			},
+		}
+		fr, err := NewFunctionRunner(ctx, fltr, types.UniquePath(pkgPath), result, fnresult.NewResultList(), RunnerOptions{Report: report})
+		assert.NoError(t, err)
+		fr.imageTiming = timing
+		_, _ = fr.Filter(nil)
+	}
+	run("/pkg", &fnresult.Result{Image: "set-labels:v1"}, &ImageTiming{Cache: ImageCacheMiss, Pull: 1500 * time.Millisecond}, false)
+	run(filepath.Join("/pkg", "db"), &fnresult.Result{ExecPath: "./fn"}, nil, true)
+
+	b, err := fsys.ReadFile("/results/" + ReportFileName)
+	assert.NoError(t, err)
+	// durations depend on the machine running the test.
+	got := regexp.MustCompile(`duration: .*`).ReplaceAllString(string(b), "duration: <d>")
+	assert.Equal(t, `steps:
+  - package: .
+    image: set-labels:v1
+    status: pass
+    duration: <d>
+    imagePull: 1.5s
+    imageCache: miss
+  - package: db
+    exec: ./fn
+    status: fail
+    duration: <d>
+`, got)
+}
@@ -61,6 +61,13 @@ type RunnerOptions struct {
 	// privileged operation, so explicit permission is required.
 	AllowExec bool
 
+	// when set to true, relative paths of function binary executables are
+	// resolved against the directory of the package declaring the function,
+	// so pipelines referring to executables in the package can be rendered
+	// from any working directory. By default they are resolved against the
+	// working directory.
+	ResolveExecToPkg bool
+
 	// AllowNetwork specifies if container based functions are allowed
 	// to access network during pipeline execution. Accessing network is
 	// considered a privileged operation (and makes render operation non-hermetic),
@@ -72,8 +79,26 @@ type RunnerOptions struct {
 	// enabled explicitly.
 	AllowWasm bool
 
+	// PreferBuiltin determines if catalog functions with a builtin
+	// implementation compatible with their image tag are executed by kpt
+	// itself instead of in a container.
+	PreferBuiltin bool
+
+	// Runtime is the container runtime running container based functions.
+	// If it's empty, it is read from the KPT_FN_RUNTIME env var or detected.
+	Runtime ContainerRuntime
+
+	// ClusterKubeconfig is the path of the kubeconfig given to container
+	// based functions that declare cluster access. Cluster access is not
+	// allowed if it is empty.
+	ClusterKubeconfig string
+
 	// ResolveToImage will resolve a partial image to a fully-qualified one
 	ResolveToImage ImageResolveFunc
+
+	// Report records the wall-clock time of each function run, and the
+	// image pull time and image cache status of container functions, if set.
+	Report *Report
 }
 
 // ImageResolveFunc is the type for a function that can resolve a partial image to a (more) fully-qualified name
@@ -82,6 +107,7 @@ type ImageResolveFunc func(ctx context.Context, image string) (string, error)
 func (o *RunnerOptions) InitDefaults() {
 	o.ImagePullPolicy = IfNotPresentPull
 	o.ResolveToImage = ResolveToImageForCLI
+	o.PreferBuiltin = PreferBuiltin()
 }
 
 // NewRunner returns a FunctionRunner given a specification of a function
@@ -131,6 +157,7 @@ func NewRunner(
 			fltr.Run = runner.Run
 		}
 	}
+	var imageTiming *ImageTiming
 	if fltr.Run == nil {
 		if f.Image == FuncGenPkgContext {
 			pkgCtxGenerator := &builtins.PackageContextGenerator{}
@@ -138,8 +165,12 @@ func NewRunner(
 		} else {
 			switch {
 			case f.Image != "":
-				// If allowWasm is true, we will use wasm runtime for image field.
-				if opts.AllowWasm {
+				if builtin, found := LookupBuiltin(f.Image, fnResult); opts.PreferBuiltin && found {
+					// Builtin implementations are used where function images
+					// are not available, e.g. on riscv64.
+					fltr.Run = builtin
+				} else if opts.AllowWasm {
+					// If allowWasm is true, we will use wasm runtime for image field.
 					wFn, err := NewWasmFn(NewOciLoader(filepath.Join(os.TempDir(), "kpt-fn-wasm"), f.Image))
 					if err != nil {
 						return nil, err
@@ -149,6 +180,7 @@ func NewRunner(
 					cfn := &ContainerFn{
 						Image:           f.Image,
 						ImagePullPolicy: opts.ImagePullPolicy,
+						Runtime:         opts.Runtime,
 						Perm: ContainerFnPermission{
 							AllowNetwork: opts.AllowNetwork,
 							// mounts are disabled for render operations (currently)
@@ -158,6 +190,13 @@ func NewRunner(
 						Ctx:      ctx,
 						FnResult: fnResult,
 					}
+					if f.ClusterAccess && opts.ClusterKubeconfig != "" {
+						cfn.WithClusterAccess(opts.ClusterKubeconfig)
+					}
+					if opts.Report != nil {
+						imageTiming = &ImageTiming{}
+						cfn.ImageTiming = imageTiming
+					}
 					fltr.Run = cfn.Run
 				}
 			case f.Exec != "":
@@ -179,6 +218,9 @@ func NewRunner(
 					if len(s) > 0 {
 						execPath = s[0]
 					}
+					if opts.ResolveExecToPkg {
+						execPath = resolveExecPath(pkgPath, execPath)
+					}
 					if len(s) > 1 {
 						execArgs = s[1:]
 					}
@@ -194,7 +236,28 @@ func NewRunner(
 			}
 		}
 	}
-	return NewFunctionRunner(ctx, fltr, pkgPath, fnResult, fnResults, opts)
+	fr, err := NewFunctionRunner(ctx, fltr, pkgPath, fnResult, fnResults, opts)
+	if err != nil {
+		return nil, err
+	}
+	fr.imageTiming = imageTiming
+	return fr, nil
+}
+
+// resolveExecPath returns the path of an executable declared in the pipeline of
+// the package at pkgPath. Relative paths, e.g. ./bin/fn, are resolved against
+// the package directory if the executable exists there, and against the
+// working directory otherwise. Names without a path separator are looked up
+// in PATH.
+func resolveExecPath(pkgPath types.UniquePath, execPath string) string {
+	if pkgPath.Empty() || filepath.IsAbs(execPath) || !strings.ContainsRune(filepath.ToSlash(execPath), '/') {
+		return execPath
+	}
+	p := filepath.Join(string(pkgPath), execPath)
+	if _, err := os.Stat(p); err != nil {
+		return execPath
+	}
+	return p
 }
 
 // NewFunctionRunner returns a FunctionRunner given a specification of a function
@@ -235,6 +298,9 @@ type FunctionRunner struct {
 	fnResult         *fnresult.Result
 	fnResults        *fnresult.ResultList
 	opts             RunnerOptions
+	// imageTiming is the image pull of container functions, recorded if
+	// opts.Report is set.
+	imageTiming *ImageTiming
 }
 
 func (fr *FunctionRunner) Filter(input []*yaml.RNode) (output []*yaml.RNode, err error) {
@@ -252,6 +318,11 @@ func (fr *FunctionRunner) Filter(input []*yaml.RNode) (output []*yaml.RNode, err
 	}
 	t0 := time.Now()
 	output, err = fr.do(input)
+	if fr.opts.Report != nil {
+		if reportErr := fr.opts.Report.add(fr.pkgPath, fr, time.Since(t0), err); reportErr != nil && err == nil {
+			return nil, fmt.Errorf("failed to write the render report: %w", reportErr)
+		}
+	}
 	if err != nil {
 		printOpt := printer.NewOpt()
 		pr.OptPrintf(printOpt, "[FAIL] %q in %v\n", fr.name, time.Since(t0).Truncate(time.Millisecond*100))
@@ -646,3 +646,50 @@ func TestPrintFnStderr(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveExecPath(t *testing.T) {
+	pkgDir := t.TempDir()
+	if err := os.MkdirAll(path.Join(pkgDir, "bin"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(pkgDir, "bin", "fn"), []byte("#!/bin/sh\ncat\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	pkgPath := types.UniquePath(pkgDir)
+
+	tests := map[string]struct {
+		pkgPath  types.UniquePath
+		execPath string
+		expected string
+	}{
+		"executable in the package": {
+			pkgPath:  pkgPath,
+			execPath: "./bin/fn",
+			expected: path.Join(pkgDir, "bin", "fn"),
+		},
+		"executable missing in the package": {
+			pkgPath:  pkgPath,
+			execPath: "./bin/other",
+			expected: "./bin/other",
+		},
+		"executable in PATH": {
+			pkgPath:  pkgPath,
+			execPath: "sed",
+			expected: "sed",
+		},
+		"absolute path": {
+			pkgPath:  pkgPath,
+			execPath: "/usr/bin/sed",
+			expected: "/usr/bin/sed",
+		},
+		"no package": {
+			execPath: "./bin/fn",
+			expected: "./bin/fn",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, resolveExecPath(tc.pkgPath, tc.execPath))
+		})
+	}
+}
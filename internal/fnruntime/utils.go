@@ -17,6 +17,7 @@ package fnruntime
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/GoogleContainerTools/kpt/internal/types"
@@ -172,6 +173,31 @@ func SelectInput(input []*yaml.RNode, selectors, exclusions []kptfilev1.Selector
 	return filteredInput, nil
 }
 
+// ConditionMet returns true if the condition under which a function is run is
+// met for the given input resources. A nil condition is always met.
+func ConditionMet(input []*yaml.RNode, when *kptfilev1.FunctionCondition) bool {
+	if when == nil {
+		return true
+	}
+	for name, value := range when.Env {
+		v, found := os.LookupEnv(name)
+		if !found || (value != "" && v != value) {
+			return false
+		}
+	}
+	if len(when.Resources) == 0 {
+		return true
+	}
+	for _, node := range input {
+		for _, selector := range when.Resources {
+			if IsMatch(node, selector) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IsMatch returns true if the resource matches input selection criteria
 func IsMatch(node *yaml.RNode, selector kptfilev1.Selector) bool {
 	// keep expanding with new selectors
@@ -156,3 +156,61 @@ func TestNewConfigMap(t *testing.T) {
 	assert.Contains(t, mapAsString, `integer: "8081"`)
 	assert.Contains(t, mapAsString, `float: "1.23"`)
 }
+
+func TestConditionMet(t *testing.T) {
+	t.Setenv("KPT_TEST_STAGE", "prod")
+	input, err := yaml.Parse(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment`)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		when     *kptfile.FunctionCondition
+		expected bool
+	}{
+		"no condition": {
+			expected: true,
+		},
+		"env matches": {
+			when:     &kptfile.FunctionCondition{Env: map[string]string{"KPT_TEST_STAGE": "prod"}},
+			expected: true,
+		},
+		"env does not match": {
+			when:     &kptfile.FunctionCondition{Env: map[string]string{"KPT_TEST_STAGE": "dev"}},
+			expected: false,
+		},
+		"env is set": {
+			when:     &kptfile.FunctionCondition{Env: map[string]string{"KPT_TEST_STAGE": ""}},
+			expected: true,
+		},
+		"env is not set": {
+			when:     &kptfile.FunctionCondition{Env: map[string]string{"KPT_TEST_UNSET": ""}},
+			expected: false,
+		},
+		"resource matches": {
+			when: &kptfile.FunctionCondition{Resources: []kptfile.Selector{
+				{Kind: "Service"},
+				{Kind: "Deployment"},
+			}},
+			expected: true,
+		},
+		"no resource matches": {
+			when:     &kptfile.FunctionCondition{Resources: []kptfile.Selector{{Kind: "Service"}}},
+			expected: false,
+		},
+		"env matches but no resource matches": {
+			when: &kptfile.FunctionCondition{
+				Env:       map[string]string{"KPT_TEST_STAGE": "prod"},
+				Resources: []kptfile.Selector{{Kind: "Service"}},
+			},
+			expected: false,
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ConditionMet([]*yaml.RNode{input}, tc.when))
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 
 	"github.com/GoogleContainerTools/kpt/pkg/wasm"
@@ -54,6 +55,20 @@ func NewWasmFn(loader WasmLoader) (*WasmFn, error) {
 			nodejs:      nf,
 		}, nil
 	case "":
+		// Binaries built without cgo, e.g. for riscv64, don't include
+		// wasmtime, so node.js is used if it is installed.
+		if !wasmtimeSupported {
+			if _, err := exec.LookPath(nodejsBin); err == nil {
+				nf, err := NewNodejsFn(loader)
+				if err != nil {
+					return nil, err
+				}
+				return &WasmFn{
+					runtimeType: Nodejs,
+					nodejs:      nf,
+				}, nil
+			}
+		}
 		fallthrough
 	case string(Wasmtime):
 		wf, err := NewWasmtimeFn(loader)
@@ -29,6 +29,10 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+// wasmtimeSupported is true since wasmtime is compiled into binaries built
+// with cgo.
+const wasmtimeSupported = true
+
 type WasmtimeFn struct {
 	wasmexec.Memory
 	*wasmtime.Instance
@@ -25,7 +25,10 @@ import (
 )
 
 const (
-	msg = "wasmtime support is not complied into this binary. Binaries with wasmtime is avilable at github.com/GoogleContainerTools/kpt"
+	msg = "wasmtime support is not complied into this binary. Install node.js to run wasm functions with it, or use a binary with wasmtime available at github.com/GoogleContainerTools/kpt"
+
+	// wasmtimeSupported is false since wasmtime requires cgo.
+	wasmtimeSupported = false
 )
 
 type WasmtimeFn struct {
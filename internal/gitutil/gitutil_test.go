@@ -433,6 +433,52 @@ func getRepoAndVerify(t *testing.T, repo, branchName string) string {
 	return dir
 }
 
+func TestGitUpstreamRepo_ResolveVersionRange(t *testing.T) {
+	gur := &GitUpstreamRepo{
+		Tags: map[string]string{
+			"v0.2.0":        "a",
+			"v0.2.5":        "b",
+			"v0.3.0":        "c",
+			"1.2.0":         "d",
+			"v1.4.2":        "e",
+			"v1.5.0-rc.1":   "f",
+			"v2.0.0":        "g",
+			"kafka/v1.2.1":  "h",
+			"kafka/v1.9.0":  "i",
+			"not-a-version": "j",
+		},
+	}
+	testCases := map[string]struct {
+		prefix  string
+		r       string
+		want    string
+		wantErr string
+	}{
+		"caret":                {r: "^1.2.0", want: "v1.4.2"},
+		"caret 0.x":            {r: "^0.2.1", want: "v0.2.5"},
+		"tilde":                {r: "~1.2", want: "1.2.0"},
+		"tilde major":          {r: "~1", want: "v1.4.2"},
+		"leading v":            {r: "^v2.0.0", want: "v2.0.0"},
+		"prefix":               {prefix: "kafka", r: "^1.0.0", want: "kafka/v1.9.0"},
+		"no match":             {r: "^3.0.0"},
+		"invalid":              {r: "^1.x", wantErr: `invalid version range "^1.x"`},
+		"pre-release in range": {r: "^1.5.0-rc.0", wantErr: `invalid version range "^1.5.0-rc.0"`},
+	}
+	for tn, tc := range testCases {
+		tc := tc
+		t.Run(tn, func(t *testing.T) {
+			got, found, err := gur.ResolveVersionRange(tc.prefix, tc.r)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want != "", found)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func toKeys(m map[string]string) []string {
 	keys := make([]string, 0)
 	for k := range m {
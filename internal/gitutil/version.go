@@ -0,0 +1,100 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// IsVersionRange returns true if ref is a semver range rather than a git
+// ref, e.g. ^1.2.0 or ~2.1.
+func IsVersionRange(ref string) bool {
+	return strings.HasPrefix(ref, "^") || strings.HasPrefix(ref, "~")
+}
+
+// versionRange is the range of versions matching a semver range, from min
+// (inclusive) to max (exclusive).
+type versionRange struct {
+	min, max string
+}
+
+// parseVersionRange parses a semver range:
+//   - ^1.2.3 matches the versions >= 1.2.3 and < 2.0.0. For 0.x versions,
+//     ^0.2.3 matches the versions >= 0.2.3 and < 0.3.0.
+//   - ~1.2.3 matches the versions >= 1.2.3 and < 1.3.0, and ~1 the versions
+//     >= 1.0.0 and < 2.0.0.
+func parseVersionRange(r string) (versionRange, error) {
+	op, v := r[:1], "v"+strings.TrimPrefix(r[1:], "v")
+	if !semver.IsValid(v) || semver.Prerelease(v) != "" || semver.Build(v) != "" {
+		return versionRange{}, fmt.Errorf("invalid version range %q", r)
+	}
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	nums := make([]int, 3)
+	for i := range parts {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return versionRange{}, fmt.Errorf("invalid version range %q", r)
+		}
+		nums[i] = n
+	}
+	major, minor := nums[0], nums[1]
+	max := fmt.Sprintf("v%d.0.0", major+1)
+	if (op == "^" && major == 0) || (op == "~" && len(parts) > 1) {
+		max = fmt.Sprintf("v%d.%d.0", major, minor+1)
+	}
+	return versionRange{min: semver.Canonical(v), max: max}, nil
+}
+
+// matches returns true if the version is in the range. Pre-release versions
+// never match.
+func (r versionRange) matches(v string) bool {
+	return semver.IsValid(v) && semver.Prerelease(v) == "" &&
+		semver.Compare(v, r.min) >= 0 && semver.Compare(v, r.max) < 0
+}
+
+// ResolveVersionRange returns the tag with the highest version matching the
+// semver range. Only the tags named prefix/<version> are considered, or
+// <version> if prefix is empty. Versions may omit the leading v. If no tag
+// matches, the second return value will be false.
+func (gur *GitUpstreamRepo) ResolveVersionRange(prefix, r string) (string, bool, error) {
+	vr, err := parseVersionRange(r)
+	if err != nil {
+		return "", false, err
+	}
+	if prefix != "" {
+		prefix = strings.Trim(prefix, "/") + "/"
+	}
+	var best, bestVersion string
+	for tag := range gur.Tags {
+		if !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		v := "v" + strings.TrimPrefix(strings.TrimPrefix(tag, prefix), "v")
+		if !vr.matches(v) {
+			continue
+		}
+		// pick the same tag every time for equal versions, e.g. v1.2 and
+		// v1.2.0.
+		if best == "" || semver.Compare(v, bestVersion) > 0 ||
+			(semver.Compare(v, bestVersion) == 0 && tag < best) {
+			best, bestVersion = tag, v
+		}
+	}
+	return best, best != "", nil
+}
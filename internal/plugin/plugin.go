@@ -0,0 +1,190 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin implements kpt plugins: executables named kpt-<name> on
+// the PATH that are invoked as `kpt <name>`, the same way kubectl invokes
+// kubectl-<name> executables.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/spf13/cobra"
+)
+
+// Prefix is the prefix of the file name of plugin executables.
+const Prefix = "kpt-"
+
+// Environment variables set by kpt when executing a plugin, in addition to
+// the environment kpt was invoked with.
+const (
+	// EnvPluginName is the name of the plugin, i.e. the executable name
+	// without the kpt- prefix.
+	EnvPluginName = "KPT_PLUGIN_NAME"
+	// EnvExecutable is the absolute path of the kpt executable, so plugins
+	// can call back into the same kpt version.
+	EnvExecutable = "KPT_EXECUTABLE"
+	// EnvVersion is the version of kpt invoking the plugin.
+	EnvVersion = "KPT_VERSION"
+	// EnvPackagePath is the absolute path of the package the plugin was
+	// invoked on, if its first positional argument is a kpt package.
+	EnvPackagePath = "KPT_PACKAGE_PATH"
+	// EnvOutput is the output format requested with --output (or -o), if
+	// any.
+	EnvOutput = "KPT_OUTPUT"
+)
+
+// Plugin is a plugin executable matched by the command line.
+type Plugin struct {
+	// Name is the name of the plugin, e.g. foo-bar for kpt-foo-bar.
+	Name string
+	// Path is the path of the plugin executable.
+	Path string
+	// Args are the arguments passed to the plugin.
+	Args []string
+}
+
+// Lookup returns the plugin to execute for the command line arguments
+// (without the program name), or false if the arguments refer to a builtin
+// command or no matching plugin is found on the PATH.
+//
+// As with kubectl, the longest match wins: `kpt foo bar baz` runs
+// kpt-foo-bar with the argument baz if it exists, and kpt-foo with the
+// arguments bar baz otherwise. Dashes in command names are mapped to
+// underscores in the executable name.
+func Lookup(root *cobra.Command, args []string) (*Plugin, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	if _, _, err := root.Find(args); err == nil {
+		return nil, false
+	}
+
+	var names []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		names = append(names, strings.ReplaceAll(arg, "-", "_"))
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	// The commands added by cobra on execution are not found above.
+	switch names[0] {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return nil, false
+	}
+
+	for n := len(names); n > 0; n-- {
+		name := strings.Join(names[:n], "-")
+		path, err := exec.LookPath(Prefix + name)
+		if err != nil || path == "" {
+			continue
+		}
+		return &Plugin{
+			Name: name,
+			Path: path,
+			Args: args[n:],
+		}, true
+	}
+	return nil, false
+}
+
+// Env returns the environment the plugin is executed with.
+func (p *Plugin) Env(version string) []string {
+	env := append(os.Environ(),
+		EnvPluginName+"="+p.Name,
+		EnvVersion+"="+version,
+	)
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, EnvExecutable+"="+exe)
+	}
+	if pkgPath := packagePath(p.Args); pkgPath != "" {
+		env = append(env, EnvPackagePath+"="+pkgPath)
+	}
+	if output := outputFormat(p.Args); output != "" {
+		env = append(env, EnvOutput+"="+output)
+	}
+	return env
+}
+
+// Run executes the plugin with the given streams and returns its exit code.
+func (p *Plugin) Run(ctx context.Context, version string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	cmd := exec.CommandContext(ctx, p.Path, p.Args...)
+	cmd.Env = p.Env(version)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, fmt.Errorf("failed to execute plugin %q: %w", p.Name, err)
+	}
+	return 0, nil
+}
+
+// packagePath returns the absolute path of the first positional argument
+// if it is a directory containing a Kptfile.
+func packagePath(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if i > 0 && (args[i-1] == "-o" || args[i-1] == "--output") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(arg, kptfilev1.KptFileName)); err != nil {
+			return ""
+		}
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			return ""
+		}
+		return abs
+	}
+	return ""
+}
+
+// outputFormat returns the value of the --output or -o flag in args.
+func outputFormat(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return ""
+		case strings.HasPrefix(arg, "--output="):
+			return strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-o="):
+			return strings.TrimPrefix(arg, "-o=")
+		case arg == "--output" || arg == "-o":
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+		case strings.HasPrefix(arg, "-o") && !strings.HasPrefix(arg, "--"):
+			return strings.TrimPrefix(arg, "-o")
+		}
+	}
+	return ""
+}
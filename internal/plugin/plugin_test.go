@@ -0,0 +1,153 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+const script = `#!/bin/sh
+echo "name=$KPT_PLUGIN_NAME version=$KPT_VERSION pkg=$KPT_PACKAGE_PATH output=$KPT_OUTPUT args=$*"
+exit 3
+`
+
+func setupPlugins(t *testing.T, names ...string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test scripts require a POSIX shell")
+	}
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, Prefix+name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func rootCommand() *cobra.Command {
+	root := &cobra.Command{Use: "kpt"}
+	pkg := &cobra.Command{Use: "pkg"}
+	pkg.AddCommand(&cobra.Command{Use: "get", Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(pkg)
+	return root
+}
+
+func TestLookup(t *testing.T) {
+	dir := setupPlugins(t, "foo", "foo-bar", "my_plugin", "help")
+
+	testCases := map[string]struct {
+		args     []string
+		wantName string
+		wantArgs []string
+	}{
+		"builtin command": {
+			args: []string{"pkg", "get", "foo"},
+		},
+		"cobra help command": {
+			args: []string{"help"},
+		},
+		"no plugin": {
+			args: []string{"baz"},
+		},
+		"flag before plugin name": {
+			args: []string{"--foo"},
+		},
+		"plugin": {
+			args:     []string{"foo", "a", "--flag"},
+			wantName: "foo",
+			wantArgs: []string{"a", "--flag"},
+		},
+		"longest match": {
+			args:     []string{"foo", "bar", "a"},
+			wantName: "foo-bar",
+			wantArgs: []string{"a"},
+		},
+		"dashes map to underscores": {
+			args:     []string{"my-plugin"},
+			wantName: "my_plugin",
+			wantArgs: []string{},
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			p, found := Lookup(rootCommand(), tc.args)
+			if tc.wantName == "" {
+				assert.False(t, found)
+				return
+			}
+			if assert.True(t, found) {
+				assert.Equal(t, tc.wantName, p.Name)
+				assert.Equal(t, filepath.Join(dir, Prefix+tc.wantName), p.Path)
+				assert.Equal(t, tc.wantArgs, p.Args)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	setupPlugins(t, "foo")
+	pkg := t.TempDir()
+	if err := os.WriteFile(filepath.Join(pkg, "Kptfile"), []byte("kind: Kptfile\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, found := Lookup(rootCommand(), []string{"foo", pkg, "--output=json"})
+	if !assert.True(t, found) {
+		return
+	}
+	stdout := &bytes.Buffer{}
+	exitCode, err := p.Run(context.Background(), "v1.2.3", strings.NewReader(""), stdout, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+	assert.Equal(t, "name=foo version=v1.2.3 pkg="+pkg+" output=json args="+pkg+" --output=json\n", stdout.String())
+}
+
+func TestOutputFormat(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"pkg"}},
+		{args: []string{"--output=yaml"}, want: "yaml"},
+		{args: []string{"--output", "json", "pkg"}, want: "json"},
+		{args: []string{"-o", "json"}, want: "json"},
+		{args: []string{"-ojson"}, want: "json"},
+		{args: []string{"--", "-o", "json"}},
+	} {
+		assert.Equal(t, tc.want, outputFormat(tc.args), "args: %v", tc.args)
+	}
+}
+
+func TestPackagePath(t *testing.T) {
+	pkg := t.TempDir()
+	if err := os.WriteFile(filepath.Join(pkg, "Kptfile"), []byte("kind: Kptfile\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, pkg, packagePath([]string{"-o", "json", pkg}))
+	assert.Equal(t, "", packagePath([]string{t.TempDir(), pkg}))
+	assert.Equal(t, "", packagePath(nil))
+}
@@ -24,11 +24,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
 	"github.com/GoogleContainerTools/kpt/internal/gitutil"
 	"github.com/GoogleContainerTools/kpt/internal/pkg"
 	"github.com/GoogleContainerTools/kpt/internal/util/addmergecomment"
 	"github.com/GoogleContainerTools/kpt/internal/util/fetch"
 	"github.com/GoogleContainerTools/kpt/internal/util/pkgutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/render"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
 	"sigs.k8s.io/kustomize/kyaml/errors"
@@ -47,6 +49,9 @@ const (
 	TypeCombined Type = "combined"
 	// 3way shows changes in local and remote changes side-by-side
 	Type3Way Type = "3way"
+	// TypeRendered shows changes in the rendered local pkg relative to the
+	// rendered upstream source pkg at target version
+	TypeRendered Type = "rendered"
 )
 
 // A collection of user-readable "source" definitions for diffed packages.
@@ -72,7 +77,7 @@ func (dt Type) String() string {
 	return string(dt)
 }
 
-var SupportedDiffTypes = []Type{TypeLocal, TypeRemote, TypeCombined, Type3Way}
+var SupportedDiffTypes = []Type{TypeLocal, TypeRemote, TypeCombined, Type3Way, TypeRendered}
 
 func SupportedDiffTypesLabel() string {
 	var labels []string
@@ -100,6 +105,10 @@ type Command struct {
 	// DiffToolOpts refers to the commandline options to for the diffing tool.
 	DiffToolOpts string
 
+	// OutputFormat is the format in which the changes are rendered by kpt
+	// itself. If empty, the changes are shown using the DiffTool.
+	OutputFormat OutputFormat
+
 	// When Debug is true, command will run with verbose logging and will not
 	// cleanup the staged packages to assist with debugging.
 	Debug bool
@@ -108,6 +117,10 @@ type Command struct {
 	// command.
 	Output io.Writer
 
+	// RunnerOptions control the execution of the functions of the package
+	// pipelines when rendering the packages for the rendered diff type.
+	RunnerOptions fnruntime.RunnerOptions
+
 	// PkgDiffer specifies package differ
 	PkgDiffer PkgDiffer
 
@@ -182,7 +195,8 @@ func (c *Command) Run(ctx context.Context) error {
 
 	if c.DiffType == TypeRemote ||
 		c.DiffType == TypeCombined ||
-		c.DiffType == Type3Way {
+		c.DiffType == Type3Way ||
+		c.DiffType == TypeRendered {
 		// get the upstream pkg at the target version
 		upstreamTargetPkgName := NameStagingDirectory(TargetRemotePackageSource,
 			c.Ref)
@@ -210,19 +224,58 @@ func (c *Command) Run(ctx context.Context) error {
 		return c.PkgDiffer.Diff(currPkg, upstreamTargetPkg)
 	case Type3Way:
 		return c.PkgDiffer.Diff(currPkg, upstreamPkg, upstreamTargetPkg)
+	case TypeRendered:
+		for _, p := range []string{currPkg, upstreamTargetPkg} {
+			if err := c.render(ctx, p); err != nil {
+				return err
+			}
+		}
+		return c.PkgDiffer.Diff(currPkg, upstreamTargetPkg)
 	default:
 		return errors.Errorf("unsupported diff type '%s'", c.DiffType)
 	}
 }
 
+// render hydrates the staged package in place by running its pipeline.
+func (c *Command) render(ctx context.Context, dir string) error {
+	_, err := (&render.Renderer{
+		PkgPath:       dir,
+		RunnerOptions: c.RunnerOptions,
+		FileSystem:    filesys.FileSystemOrOnDisk{},
+	}).Execute(ctx)
+	if err != nil {
+		return errors.Errorf("failed to render package '%s': %v", dir, err)
+	}
+	return nil
+}
+
 func (c *Command) Validate() error {
 	switch c.DiffType {
-	case TypeLocal, TypeCombined, TypeRemote, Type3Way:
+	case TypeLocal, TypeCombined, TypeRemote, Type3Way, TypeRendered:
 	default:
 		return errors.Errorf("invalid diff-type '%s': supported diff-types are: %s",
 			c.DiffType, SupportedDiffTypesLabel())
 	}
 
+	if c.OutputFormat != "" {
+		switch c.OutputFormat {
+		case OutputUnified, OutputHTML, OutputPatch, OutputJSON, OutputYAML:
+		default:
+			return errors.Errorf("invalid output '%s': supported outputs are: %s",
+				c.OutputFormat, SupportedOutputFormatsLabel())
+		}
+		if c.DiffType == Type3Way {
+			return errors.Errorf("diff-type '%s' is not supported with output '%s'", c.DiffType, c.OutputFormat)
+		}
+		// the rendered resources are not the files of the local package, so
+		// a patch of them cannot be applied.
+		if c.DiffType == TypeRendered && c.OutputFormat == OutputPatch {
+			return errors.Errorf("diff-type '%s' is not supported with output '%s'", c.DiffType, c.OutputFormat)
+		}
+		// the changes are rendered by kpt, so no diff tool is needed.
+		return nil
+	}
+
 	path, err := exec.LookPath(c.DiffTool)
 	if err != nil {
 		return errors.Errorf("diff-tool '%s' not found in the PATH", c.DiffTool)
@@ -239,6 +292,17 @@ func (c *Command) DefaultValues() {
 	if c.PkgGetter == nil {
 		c.PkgGetter = defaultPkgGetter{}
 	}
+	if c.RunnerOptions.ResolveToImage == nil {
+		c.RunnerOptions.InitDefaults()
+	}
+	if c.PkgDiffer == nil && c.OutputFormat != "" {
+		c.PkgDiffer = &renderingPkgDiffer{
+			Format:   c.OutputFormat,
+			DiffType: c.DiffType,
+			Output:   c.Output,
+			Package:  c.Path,
+		}
+	}
 	if c.PkgDiffer == nil {
 		c.PkgDiffer = &defaultPkgDiffer{
 			DiffType:     c.DiffType,
@@ -22,10 +22,13 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
 	"github.com/GoogleContainerTools/kpt/internal/testutil"
 	"github.com/GoogleContainerTools/kpt/internal/testutil/pkgbuilder"
 	. "github.com/GoogleContainerTools/kpt/internal/util/diff"
@@ -391,6 +394,76 @@ func TestCommand_InvalidRef(t *testing.T) {
 	assert.Contains(t, err.Error(), "unknown revision or path not in the working tree.")
 }
 
+func TestCommand_DiffRendered(t *testing.T) {
+	t.Setenv(fnruntime.PreferBuiltinEnv, "true")
+
+	kptfile := pkgbuilder.NewKptfile().
+		WithPipeline(pkgbuilder.NewFunction("gcr.io/kpt-fn/set-namespace:v0.4.1").
+			WithConfigPath("namespace.yaml"))
+	namespaceConfig := func(namespace string) string {
+		return `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: namespace
+  annotations:
+    config.kubernetes.io/local-config: "true"
+data:
+  namespace: ` + namespace + "\n"
+	}
+	reposChanges := map[string][]testutil.Content{
+		testutil.Upstream: {
+			{
+				Pkg: pkgbuilder.NewRootPkg().
+					WithKptfile(kptfile).
+					WithFile("namespace.yaml", namespaceConfig("staging")).
+					WithResource(pkgbuilder.DeploymentResource),
+				Branch: "main",
+				Tag:    "v1",
+			},
+			{
+				Pkg: pkgbuilder.NewRootPkg().
+					WithKptfile(kptfile).
+					WithFile("namespace.yaml", namespaceConfig("prod")).
+					WithResource(pkgbuilder.DeploymentResource),
+			},
+		},
+	}
+
+	g := &testutil.TestSetupManager{
+		T:            t,
+		ReposChanges: reposChanges,
+		GetRef:       "v1",
+	}
+	defer g.Clean()
+
+	if !g.Init() {
+		return
+	}
+
+	diffOutput := &bytes.Buffer{}
+	err := (&Command{
+		Path:         g.LocalWorkspace.FullPackagePath(),
+		Ref:          "main",
+		DiffType:     TypeRendered,
+		OutputFormat: OutputUnified,
+		Output:       diffOutput,
+	}).Run(fake.CtxWithDefaultPrinter())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// the namespace is only set on the deployment when the packages are
+	// rendered, which changes its ID.
+	assert.Contains(t, diffOutput.String(), "# apps/v1/Deployment staging/mysql-deployment (deployment.yaml) deleted\n")
+	assert.Contains(t, diffOutput.String(), "# apps/v1/Deployment prod/mysql-deployment (deployment.yaml) added\n")
+	assert.Contains(t, diffOutput.String(), "-  namespace: staging\n+  namespace: prod\n")
+
+	// the local package is not rendered in place.
+	b, err := os.ReadFile(filepath.Join(g.LocalWorkspace.FullPackagePath(), "deployment.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "namespace: myspace\n")
+}
+
 // Validate that all three directories are staged and provided to diff command
 func TestCommand_Diff3Parameters(t *testing.T) {
 	reposChanges := map[string][]testutil.Content{
@@ -0,0 +1,483 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/util/addmergecomment"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// OutputFormat is the format in which kpt renders the differences between
+// packages itself, instead of delegating to the diff tool.
+type OutputFormat string
+
+const (
+	// OutputUnified renders a unified diff per resource.
+	OutputUnified OutputFormat = "unified"
+	// OutputHTML renders a self-contained HTML page with the unified diffs
+	// per resource.
+	OutputHTML OutputFormat = "html"
+	// OutputPatch renders a unified diff per file, with paths relative to
+	// the package, which can be applied with `git apply` or `patch -p1`.
+	OutputPatch OutputFormat = "patch"
+	// OutputJSON and OutputYAML write a machine-readable command result
+	// holding the changed resources.
+	OutputJSON OutputFormat = OutputFormat(output.JSON)
+	OutputYAML OutputFormat = OutputFormat(output.YAML)
+)
+
+var SupportedOutputFormats = []OutputFormat{OutputUnified, OutputHTML, OutputPatch, OutputJSON, OutputYAML}
+
+func SupportedOutputFormatsLabel() string {
+	var labels []string
+	for _, f := range SupportedOutputFormats {
+		labels = append(labels, string(f))
+	}
+	return strings.Join(labels, ", ")
+}
+
+// contextLines is the number of unchanged lines shown around changes.
+const contextLines = 3
+
+// ChangeType is the type of change of a resource between two packages.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeDeleted  ChangeType = "deleted"
+	ChangeModified ChangeType = "modified"
+)
+
+// ResourceDiff holds the changes of a single resource, or of a file that
+// doesn't contain resources.
+type ResourceDiff struct {
+	// ID identifies the resource, e.g. `apps/v1/Deployment default/app`.
+	// It is the file path for files that don't contain resources.
+	ID string `json:"id"`
+	// Path is the path of the file holding the resource, relative to the
+	// package.
+	Path string `json:"path"`
+	// Change is the type of change.
+	Change ChangeType `json:"change"`
+	// Unified is the unified diff of the resource.
+	Unified string `json:"unified"`
+}
+
+// renderingPkgDiffer implements PkgDiffer by rendering the differences
+// between the resources of two packages in the given output format.
+type renderingPkgDiffer struct {
+	Format OutputFormat
+	// DiffType is the type of the diff, which determines if the first
+	// package is the local package.
+	DiffType Type
+	Output   io.Writer
+	// Package is the path of the package being diffed, recorded in the
+	// machine-readable output.
+	Package string
+}
+
+func (d *renderingPkgDiffer) Diff(pkgs ...string) error {
+	if len(pkgs) != 2 {
+		return errors.Errorf("--output %s supports comparison of exactly 2 packages, got %d", d.Format, len(pkgs))
+	}
+	// add merge comments before comparing so that there are no unwanted diffs
+	commented := pkgs
+	if d.Format == OutputPatch && (d.DiffType == TypeLocal || d.DiffType == TypeCombined) {
+		// the patch must apply to the files of the local package as they are.
+		commented = pkgs[1:]
+	}
+	if err := addmergecomment.Process(commented...); err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		if err := (&defaultPkgDiffer{}).prepareForDiff(pkg); err != nil {
+			return err
+		}
+	}
+	if d.Format == OutputPatch {
+		return RenderPatch(d.Output, pkgs[0], pkgs[1])
+	}
+	diffs, err := ResourceDiffs(pkgs[0], pkgs[1])
+	if err != nil {
+		return err
+	}
+	switch d.Format {
+	case OutputUnified:
+		return RenderUnified(d.Output, diffs)
+	case OutputHTML:
+		return RenderHTML(d.Output, filepath.Base(pkgs[0]), filepath.Base(pkgs[1]), diffs)
+	case OutputJSON, OutputYAML:
+		return output.Write(d.Output, output.Format(d.Format), "pkg diff", d.Package, diffs, nil)
+	default:
+		return errors.Errorf("unsupported output format '%s'", d.Format)
+	}
+}
+
+// ResourceDiffs returns the changes between the resources of packages from
+// and to, grouped per resource and sorted by path and resource ID. Files
+// that don't hold resources are compared line by line.
+func ResourceDiffs(from, to string) ([]ResourceDiff, error) {
+	fromEntries, err := readEntries(from)
+	if err != nil {
+		return nil, err
+	}
+	toEntries, err := readEntries(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for k := range fromEntries {
+		keys[k] = true
+	}
+	for k := range toEntries {
+		keys[k] = true
+	}
+
+	var diffs []ResourceDiff
+	for k := range keys {
+		f, inFrom := fromEntries[k]
+		t, inTo := toEntries[k]
+		if inFrom && inTo && f.content == t.content {
+			continue
+		}
+		rd := ResourceDiff{ID: k}
+		fromName, toName := "a/"+f.path, "b/"+t.path
+		switch {
+		case !inFrom:
+			rd.Change, rd.Path, fromName = ChangeAdded, t.path, "/dev/null"
+		case !inTo:
+			rd.Change, rd.Path, toName = ChangeDeleted, f.path, "/dev/null"
+		default:
+			rd.Change, rd.Path = ChangeModified, t.path
+		}
+		rd.Unified, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(f.content),
+			B:        splitLines(t.content),
+			FromFile: fromName,
+			ToFile:   toName,
+			Context:  contextLines,
+		})
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, rd)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Path != diffs[j].Path {
+			return diffs[i].Path < diffs[j].Path
+		}
+		return diffs[i].ID < diffs[j].ID
+	})
+	return diffs, nil
+}
+
+// noNewlineMarker follows a last line without line ending in a unified
+// diff, like it does in the output of diff and git.
+const noNewlineMarker = "\\ No newline at end of file\n"
+
+// splitLines splits s into lines, keeping the line endings. A last line
+// without line ending gets noNewlineMarker appended, so it differs from the
+// same line with a newline and applying the patch keeps the file as it is.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n" + noNewlineMarker
+	}
+	return lines
+}
+
+type entry struct {
+	path    string
+	content string
+}
+
+// readEntries returns the resources and other files of the package keyed
+// by resource ID or file path.
+func readEntries(pkgPath string) (map[string]entry, error) {
+	entries := map[string]entry{}
+
+	nodes, err := (&kio.LocalPackageReader{
+		PackagePath:       pkgPath,
+		PreserveSeqIndent: true,
+		WrapBareSeqNode:   true,
+	}).Read()
+	if err != nil {
+		return nil, err
+	}
+	resourceFiles := map[string]bool{}
+	for _, node := range nodes {
+		path, _, err := kioutil.GetFileAnnotations(node)
+		if err != nil {
+			return nil, err
+		}
+		resourceFiles[path] = true
+		if err := clearReaderAnnotations(node); err != nil {
+			return nil, err
+		}
+		content, err := node.String()
+		if err != nil {
+			return nil, err
+		}
+		entries[resourceID(node)] = entry{path: path, content: content}
+	}
+
+	err = filepath.Walk(pkgPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(pkgPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if resourceFiles[rel] {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		entries[rel] = entry{path: rel, content: string(b)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func resourceID(node *yaml.RNode) string {
+	name := node.GetName()
+	if ns := node.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	return fmt.Sprintf("%s/%s %s", node.GetApiVersion(), node.GetKind(), name)
+}
+
+// clearReaderAnnotations removes the annotations set when reading the
+// resources, so they don't show up in the diffs.
+func clearReaderAnnotations(node *yaml.RNode) error {
+	for _, a := range []string{
+		kioutil.PathAnnotation, kioutil.IndexAnnotation, kioutil.SeqIndentAnnotation, kioutil.IdAnnotation,
+		kioutil.LegacyPathAnnotation, kioutil.LegacyIndexAnnotation, kioutil.LegacyIdAnnotation,
+	} {
+		if err := node.PipeE(yaml.ClearAnnotation(a)); err != nil {
+			return err
+		}
+	}
+	return yaml.ClearEmptyAnnotations(node)
+}
+
+// RenderUnified writes the diffs in unified format, with a header per
+// resource. The output uses standard unified diff headers, so it can be
+// highlighted by pagers and editors that support diffs.
+func RenderUnified(w io.Writer, diffs []ResourceDiff) error {
+	for _, d := range diffs {
+		if _, err := fmt.Fprintf(w, "# %s (%s) %s\n%s", d.ID, d.Path, d.Change, d.Unified); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderPatch writes the changes between the files of packages from and to
+// as a patch, with a unified diff per changed file sorted by path. The file
+// paths are relative to the packages and prefixed with a/ and b/, so the
+// patch can be applied in the directory of a package with `git apply` or
+// `patch -p1`.
+func RenderPatch(w io.Writer, from, to string) error {
+	fromFiles, err := readFiles(from)
+	if err != nil {
+		return err
+	}
+	toFiles, err := readFiles(to)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	for p := range fromFiles {
+		paths = append(paths, p)
+	}
+	for p := range toFiles {
+		if _, found := fromFiles[p]; !found {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		f, inFrom := fromFiles[p]
+		t, inTo := toFiles[p]
+		if inFrom && inTo && f == t {
+			continue
+		}
+		fromName, toName := "a/"+p, "b/"+p
+		switch {
+		case !inFrom:
+			fromName = "/dev/null"
+		case !inTo:
+			toName = "/dev/null"
+		}
+		unified, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(f),
+			B:        splitLines(t),
+			FromFile: fromName,
+			ToFile:   toName,
+			Context:  contextLines,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "diff -u %s %s\n%s", fromName, toName, unified); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFiles returns the content of the files of the package keyed by their
+// slash-separated path relative to the package.
+func readFiles(pkgPath string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.Walk(pkgPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(pkgPath, path)
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// diffLine is a line of a unified diff with the CSS class highlighting it.
+type diffLine struct {
+	Class string
+	Text  string
+}
+
+func diffLines(unified string) []diffLine {
+	var lines []diffLine
+	for _, l := range strings.SplitAfter(unified, "\n") {
+		if l == "" {
+			continue
+		}
+		class := "ctx"
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+			class = "file"
+		case strings.HasPrefix(l, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(l, "+"):
+			class = "add"
+		case strings.HasPrefix(l, "-"):
+			class = "del"
+		}
+		lines = append(lines, diffLine{Class: class, Text: l})
+	}
+	return lines
+}
+
+var htmlTemplate = template.Must(template.New("diff").Funcs(template.FuncMap{
+	"lines": diffLines,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>kpt pkg diff: {{.From}} .. {{.To}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table.summary { border-collapse: collapse; margin-bottom: 2em; }
+table.summary td, table.summary th { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+details { margin-bottom: 1em; border: 1px solid #ddd; border-radius: 4px; }
+summary { padding: 6px; background: #f6f8fa; cursor: pointer; font-family: monospace; }
+pre.diff { margin: 0; padding: 6px; overflow-x: auto; }
+.add { background: #e6ffec; }
+.del { background: #ffebe9; }
+.hunk { color: #6f42c1; }
+.file { color: #57606a; font-weight: bold; }
+.added { color: #1a7f37; }
+.deleted { color: #cf222e; }
+.modified { color: #9a6700; }
+</style>
+</head>
+<body>
+<h1>kpt pkg diff: {{.From}} .. {{.To}}</h1>
+{{- if not .Diffs}}
+<p>No differences.</p>
+{{- else}}
+<table class="summary">
+<tr><th>Resource</th><th>File</th><th>Change</th></tr>
+{{- range $i, $d := .Diffs}}
+<tr><td><a href="#r{{$i}}">{{$d.ID}}</a></td><td>{{$d.Path}}</td><td class="{{$d.Change}}">{{$d.Change}}</td></tr>
+{{- end}}
+</table>
+{{- range $i, $d := .Diffs}}
+<details id="r{{$i}}" open>
+<summary>{{$d.ID}} ({{$d.Path}}) <span class="{{$d.Change}}">{{$d.Change}}</span></summary>
+<pre class="diff language-diff">{{range lines $d.Unified}}<span class="{{.Class}}">{{.Text}}</span>{{end}}</pre>
+</details>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderHTML writes the diffs as a self-contained HTML page, with a summary
+// of the changed resources followed by the diff of each resource.
+func RenderHTML(w io.Writer, from, to string, diffs []ResourceDiff) error {
+	return htmlTemplate.Execute(w, struct {
+		From  string
+		To    string
+		Diffs []ResourceDiff
+	}{
+		From:  from,
+		To:    to,
+		Diffs: diffs,
+	})
+}
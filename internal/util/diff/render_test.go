@@ -0,0 +1,241 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestResourceDiffs(t *testing.T) {
+	from := writeFiles(t, map[string]string{
+		"resources.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: removed
+`,
+		"README.md": "unchanged\n",
+	})
+	to := writeFiles(t, map[string]string{
+		"resources.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+`,
+		"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: default
+`,
+		"README.md": "unchanged\n",
+	})
+
+	diffs, err := ResourceDiffs(from, to)
+	assert.NoError(t, err)
+	if !assert.Len(t, diffs, 3) {
+		return
+	}
+
+	assert.Equal(t, "apps/v1/Deployment app", diffs[0].ID)
+	assert.Equal(t, "resources.yaml", diffs[0].Path)
+	assert.Equal(t, ChangeModified, diffs[0].Change)
+	assert.Equal(t, `--- a/resources.yaml
++++ b/resources.yaml
+@@ -3,4 +3,4 @@
+ metadata:
+   name: app
+ spec:
+-  replicas: 1
++  replicas: 3
+`, diffs[0].Unified)
+
+	assert.Equal(t, "v1/ConfigMap removed", diffs[1].ID)
+	assert.Equal(t, ChangeDeleted, diffs[1].Change)
+	assert.Contains(t, diffs[1].Unified, "+++ /dev/null\n")
+
+	assert.Equal(t, "v1/Service default/app", diffs[2].ID)
+	assert.Equal(t, "service.yaml", diffs[2].Path)
+	assert.Equal(t, ChangeAdded, diffs[2].Change)
+	assert.Contains(t, diffs[2].Unified, "--- /dev/null\n")
+}
+
+func TestRender(t *testing.T) {
+	diffs := []ResourceDiff{
+		{
+			ID:     "apps/v1/Deployment app",
+			Path:   "deployment.yaml",
+			Change: ChangeModified,
+			Unified: `--- a/deployment.yaml
++++ b/deployment.yaml
+@@ -1 +1 @@
+-  image: <old>
++  image: <new>
+`,
+		},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, RenderUnified(out, diffs))
+	assert.Equal(t, "# apps/v1/Deployment app (deployment.yaml) modified\n"+diffs[0].Unified, out.String())
+
+	out.Reset()
+	assert.NoError(t, RenderHTML(out, "local-v1", "remote-v1", diffs))
+	html := out.String()
+	assert.Contains(t, html, "<title>kpt pkg diff: local-v1 .. remote-v1</title>")
+	assert.Contains(t, html, `<td><a href="#r0">apps/v1/Deployment app</a></td><td>deployment.yaml</td><td class="modified">modified</td>`)
+	assert.Contains(t, html, `<span class="del">-  image: &lt;old&gt;`+"\n</span>")
+	assert.Contains(t, html, `<span class="add">&#43;  image: &lt;new&gt;`+"\n</span>")
+	assert.Contains(t, html, `<span class="hunk">@@ -1 &#43;1 @@`)
+
+	out.Reset()
+	assert.NoError(t, RenderHTML(out, "local-v1", "remote-v1", nil))
+	assert.Contains(t, out.String(), "<p>No differences.</p>")
+}
+
+func TestRenderPatch(t *testing.T) {
+	from := writeFiles(t, map[string]string{
+		"deployment.yaml": "kind: Deployment\nspec:\n  image: nginx:1.1\n",
+		"README.md":       "old\n",
+	})
+	to := writeFiles(t, map[string]string{
+		"deployment.yaml": "kind: Deployment\nspec:\n  image: nginx:1.2\n",
+		"service.yaml":    "kind: Service\n",
+	})
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, RenderPatch(out, from, to))
+	assert.Equal(t, `diff -u a/README.md /dev/null
+--- a/README.md
++++ /dev/null
+@@ -1 +0,0 @@
+-old
+diff -u a/deployment.yaml b/deployment.yaml
+--- a/deployment.yaml
++++ b/deployment.yaml
+@@ -1,3 +1,3 @@
+ kind: Deployment
+ spec:
+-  image: nginx:1.1
++  image: nginx:1.2
+diff -u /dev/null b/service.yaml
+--- /dev/null
++++ b/service.yaml
+@@ -0,0 +1 @@
++kind: Service
+`, out.String())
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return
+	}
+	// the patch applies to the package it was created from.
+	cmd := exec.Command("git", "apply", "-")
+	cmd.Dir = from
+	cmd.Stdin = out
+	b, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(b))
+	for name, content := range map[string]string{
+		"deployment.yaml": "kind: Deployment\nspec:\n  image: nginx:1.2\n",
+		"service.yaml":    "kind: Service\n",
+	} {
+		b, err := os.ReadFile(filepath.Join(from, name))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(b))
+	}
+	assert.NoFileExists(t, filepath.Join(from, "README.md"))
+}
+
+func TestRenderPatchNoNewlineAtEndOfFile(t *testing.T) {
+	from := writeFiles(t, map[string]string{
+		"a.txt": "one\ntwo",
+		"b.txt": "one\ntwo\n",
+		"c.txt": "one\ntwo",
+	})
+	to := writeFiles(t, map[string]string{
+		"a.txt": "one\ntwo\n",
+		"b.txt": "one\ntwo",
+		"c.txt": "zero\ntwo",
+	})
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, RenderPatch(out, from, to))
+	assert.Equal(t, `diff -u a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
+\ No newline at end of file
++two
+diff -u a/b.txt b/b.txt
+--- a/b.txt
++++ b/b.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++two
+\ No newline at end of file
+diff -u a/c.txt b/c.txt
+--- a/c.txt
++++ b/c.txt
+@@ -1,2 +1,2 @@
+-one
++zero
+ two
+\ No newline at end of file
+`, out.String())
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return
+	}
+	cmd := exec.Command("git", "apply", "-")
+	cmd.Dir = from
+	cmd.Stdin = out
+	b, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(b))
+	for name, content := range map[string]string{
+		"a.txt": "one\ntwo\n",
+		"b.txt": "one\ntwo",
+		"c.txt": "zero\ntwo",
+	} {
+		b, err := os.ReadFile(filepath.Join(from, name))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(b))
+	}
+}
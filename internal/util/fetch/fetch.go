@@ -55,6 +55,14 @@ func (c Command) Run(ctx context.Context) error {
 		return errors.E(op, c.Pkg.UniquePath, err)
 	}
 
+	if kf.Upstream.Type == kptfilev1.OciOrigin {
+		err = NewOciCloner(kf.Upstream.Oci.Image).cloneAndCopy(ctx, c.Pkg.UniquePath.String())
+		if err != nil {
+			return errors.E(op, c.Pkg.UniquePath, err)
+		}
+		return nil
+	}
+
 	g := kf.Upstream.Git
 	repoSpec := &git.RepoSpec{
 		OrgRepo: g.Repo,
@@ -76,6 +84,13 @@ func (c Command) validate(kf *kptfilev1.KptFile) error {
 		return errors.E(op, errors.MissingParam, fmt.Errorf("kptfile doesn't contain upstream information"))
 	}
 
+	if kf.Upstream.Type == kptfilev1.OciOrigin {
+		if kf.Upstream.Oci == nil || len(kf.Upstream.Oci.Image) == 0 {
+			return errors.E(op, errors.MissingParam, fmt.Errorf("kptfile upstream doesn't have oci image information"))
+		}
+		return nil
+	}
+
 	if kf.Upstream.Git == nil {
 		return errors.E(op, errors.MissingParam, fmt.Errorf("kptfile upstream doesn't have git information"))
 	}
@@ -156,6 +171,24 @@ func (c *Cloner) cloneAndCopy(ctx context.Context, dest string) error {
 	return nil
 }
 
+// resolveVersionRange returns the tag with the highest version matching the
+// semver range. Like for other refs, the tags with the package directory as
+// a prefix, e.g. kafka/v1.2.0, take precedence over the tags of the repo.
+func resolveVersionRange(repo *gitutil.GitUpstreamRepo, dir, versionRange string) (string, error) {
+	ps := strings.Split(dir, "/")
+	for len(ps) != 0 {
+		tag, found, err := repo.ResolveVersionRange(path.Join(ps...), versionRange)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return tag, nil
+		}
+		ps = ps[:len(ps)-1]
+	}
+	return "", fmt.Errorf("no tag matches the version range %q", versionRange)
+}
+
 // ClonerUsingGitExec uses a local git install, as opposed
 // to say, some remote API, to obtain a local clone of
 // a remote repo. It looks for tags with the directory as a prefix to allow
@@ -178,6 +211,15 @@ func (c *Cloner) ClonerUsingGitExec(ctx context.Context) error {
 		c.cachedRepo[c.repoSpec.CloneSpec()] = upstreamRepo
 	}
 
+	// Resolve a semver range to the tag with the highest matching version.
+	if gitutil.IsVersionRange(c.repoSpec.Ref) {
+		ref, err := resolveVersionRange(upstreamRepo, c.repoSpec.Path, c.repoSpec.Ref)
+		if err != nil {
+			return errors.E(op, errors.Git, errors.Repo(c.repoSpec.CloneSpec()), err)
+		}
+		c.repoSpec.Ref = ref
+	}
+
 	// Check if we have a ref in the upstream that matches the package-specific
 	// reference. If we do, we use that reference.
 	ps := strings.Split(c.repoSpec.Path, "/")
@@ -27,6 +27,7 @@ import (
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
 	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
+	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
@@ -701,3 +702,98 @@ func TestCommand_Run_failInvalidTag(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+// TestCommand_Run_oci verifies that Command will pull a package stored as
+// an image in an OCI registry, and pin the pulled digest in the upstreamLock.
+func TestCommand_Run_oci(t *testing.T) {
+	image := testutil.SetupOciRegistry(t) + "/blueprints/dataset1:v1"
+	lockedImage := testutil.PushOciPackage(t, image, testutil.Dataset1)
+
+	w, clean := testutil.SetupWorkspace(t)
+	defer clean()
+	w.PackageDir = "dataset1"
+	if !assert.NoError(t, os.MkdirAll(w.FullPackagePath(), 0700)) {
+		t.FailNow()
+	}
+	kf := kptfileutil.DefaultKptfile(w.PackageDir)
+	kf.Upstream = &kptfilev1.Upstream{
+		Type:           kptfilev1.OciOrigin,
+		Oci:            &kptfilev1.Oci{Image: image},
+		UpdateStrategy: kptfilev1.ResourceMerge,
+	}
+	if !assert.NoError(t, kptfileutil.WriteFile(w.FullPackagePath(), kf)) {
+		t.FailNow()
+	}
+
+	err := Command{
+		Pkg: pkgtesting.CreatePkgOrFail(t, w.FullPackagePath()),
+	}.Run(fake.CtxWithDefaultPrinter())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	ds, err := testutil.GetTestDataPath()
+	assert.NoError(t, err)
+	diff, err := testutil.Diff(filepath.Join(ds, testutil.Dataset1), w.FullPackagePath(), false)
+	assert.NoError(t, err)
+	assert.Empty(t, diff.Difference(testutil.KptfileSet).List())
+
+	kf, err = pkg.ReadKptfile(filesys.FileSystemOrOnDisk{}, w.FullPackagePath())
+	assert.NoError(t, err)
+	assert.Equal(t, &kptfilev1.UpstreamLock{
+		Type: kptfilev1.OciOrigin,
+		Oci:  &kptfilev1.OciLock{Image: lockedImage},
+	}, kf.UpstreamLock)
+}
+
+// TestCommand_Run_ociMissingImage verifies that Command fails when the
+// image doesn't exist in the registry.
+func TestCommand_Run_ociMissingImage(t *testing.T) {
+	image := testutil.SetupOciRegistry(t) + "/blueprints/missing:v1"
+
+	w, clean := testutil.SetupWorkspace(t)
+	defer clean()
+	w.PackageDir = "missing"
+	if !assert.NoError(t, os.MkdirAll(w.FullPackagePath(), 0700)) {
+		t.FailNow()
+	}
+	kf := kptfileutil.DefaultKptfile(w.PackageDir)
+	kf.Upstream = &kptfilev1.Upstream{
+		Type: kptfilev1.OciOrigin,
+		Oci:  &kptfilev1.Oci{Image: image},
+	}
+	if !assert.NoError(t, kptfileutil.WriteFile(w.FullPackagePath(), kf)) {
+		t.FailNow()
+	}
+
+	err := Command{
+		Pkg: pkgtesting.CreatePkgOrFail(t, w.FullPackagePath()),
+	}.Run(fake.CtxWithDefaultPrinter())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "error pulling image")
+	}
+}
+
+// TestOciCloner_invalidPath verifies that pulling an image with files outside
+// of the package fails, and doesn't leave the temporary directory behind.
+func TestOciCloner_invalidPath(t *testing.T) {
+	image := testutil.SetupOciRegistry(t) + "/blueprints/escape:v1"
+	img, err := crane.Image(map[string][]byte{"../escape.yaml": []byte("kind: ConfigMap\n")})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, crane.Push(img, image)) {
+		t.FailNow()
+	}
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	c := NewOciCloner(image)
+	err = c.ClonerUsingOciPull(fake.CtxWithDefaultPrinter())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid path")
+	}
+	entries, err := os.ReadDir(tmp)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/pkg"
+	"github.com/GoogleContainerTools/kpt/internal/types"
+	"github.com/GoogleContainerTools/kpt/internal/util/pkgutil"
+	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
+	"github.com/google/go-containerregistry/pkg/gcrane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// OciCloner pulls a package stored as an image in an OCI registry. The
+// contents of the image layers are the files of the package.
+type OciCloner struct {
+	// Image is the image to pull, referenced by tag or digest.
+	Image string
+
+	// Dir is the temporary directory the package was extracted to.
+	Dir string
+
+	// LockedImage is the pulled image pinned to the digest of its manifest.
+	LockedImage string
+
+	// options are passed to the registry client. Credentials are looked up
+	// in the docker config unless overridden.
+	options []remote.Option
+}
+
+func NewOciCloner(image string, opts ...remote.Option) *OciCloner {
+	return &OciCloner{
+		Image:   image,
+		options: opts,
+	}
+}
+
+// AbsPath returns the absolute path to the extracted package.
+func (c *OciCloner) AbsPath() string {
+	return c.Dir
+}
+
+// cloneAndCopy pulls the image and copies the package into the directory
+// specified by dest.
+func (c *OciCloner) cloneAndCopy(ctx context.Context, dest string) error {
+	const op errors.Op = "fetch.OciCloner.cloneAndCopy"
+	pr := printer.FromContextOrDie(ctx)
+
+	if err := c.ClonerUsingOciPull(ctx); err != nil {
+		return errors.E(op, types.UniquePath(dest), err)
+	}
+	defer os.RemoveAll(c.Dir)
+
+	pr.Printf("Adding package from image %q.\n", c.Image)
+	if err := pkgutil.CopyPackage(c.Dir, dest, true, pkg.All); err != nil {
+		return errors.E(op, types.UniquePath(dest), err)
+	}
+
+	if err := kptfileutil.UpdateKptfileWithoutOrigin(dest, c.Dir, false); err != nil {
+		return errors.E(op, types.UniquePath(dest), err)
+	}
+
+	if err := kptfileutil.UpdateUpstreamLockFromOCI(dest, c.LockedImage); err != nil {
+		return errors.E(op, types.UniquePath(dest), err)
+	}
+	return nil
+}
+
+// ClonerUsingOciPull pulls the image from the registry and extracts its
+// contents into a new temporary directory. The digest of the pulled image is
+// recorded in LockedImage so the exact same content can be fetched again.
+func (c *OciCloner) ClonerUsingOciPull(ctx context.Context) error {
+	const op errors.Op = "fetch.ClonerUsingOciPull"
+
+	ref, err := name.ParseReference(c.Image)
+	if err != nil {
+		return errors.E(op, errors.InvalidParam, fmt.Errorf("cannot parse image %q: %w", c.Image, err))
+	}
+
+	options := append([]remote.Option{
+		remote.WithAuthFromKeychain(gcrane.Keychain),
+		remote.WithContext(ctx),
+	}, c.options...)
+	image, err := remote.Image(ref, options...)
+	if err != nil {
+		return errors.E(op, fmt.Errorf("error pulling image %q: %w", c.Image, err))
+	}
+	digest, err := image.Digest()
+	if err != nil {
+		return errors.E(op, fmt.Errorf("error getting digest of image %q: %w", c.Image, err))
+	}
+
+	c.Dir, err = os.MkdirTemp("", "kpt-get-")
+	if err != nil {
+		return errors.E(op, errors.Internal, fmt.Errorf("error creating temp directory: %w", err))
+	}
+
+	rc := mutate.Extract(image)
+	defer rc.Close()
+	if err := extractTar(ctx, rc, c.Dir); err != nil {
+		_ = os.RemoveAll(c.Dir)
+		c.Dir = ""
+		return errors.E(op, fmt.Errorf("error extracting image %q: %w", c.Image, err))
+	}
+
+	c.LockedImage = ref.Context().Digest(digest.String()).String()
+	return nil
+}
+
+// extractTar writes the directories and regular files from the tar stream
+// into dir. Like copyDir, it ignores symlinks.
+func extractTar(ctx context.Context, r io.Reader, dir string) error {
+	pr := printer.FromContextOrDie(ctx)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		p := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if p != dir && !strings.HasPrefix(p, dir+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %q in image", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink, tar.TypeLink:
+			pr.Printf("[Warn] Ignoring symlink %q \n", hdr.Name)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	goerrors "errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -40,12 +41,16 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/kio"
 )
 
-// Command fetches a package from a git repository, copies it to a local
-// directory, and expands any remote subpackages.
+// Command fetches a package from a git repository or an OCI registry, copies
+// it to a local directory, and expands any remote subpackages.
 type Command struct {
 	// Git contains information about the git repo to fetch
 	Git *kptfilev1.Git
 
+	// Oci contains information about the image to fetch. Only one of Git
+	// and Oci may be set.
+	Oci *kptfilev1.Oci
+
 	// Destination is the output directory to clone the package to.  Defaults to the name of the package --
 	// either the base repo name, or the base subdirectory name.
 	Destination string
@@ -61,6 +66,21 @@ type Command struct {
 	// Kptfile. This determines how changes will be merged when updating the
 	// package.
 	UpdateStrategy kptfilev1.UpdateStrategyType
+
+	// Include are glob patterns of the paths of the subpackages to fetch,
+	// relative to the root package. Subpackages of included subpackages are
+	// fetched as well. All subpackages are fetched if empty.
+	Include []string
+
+	// Exclude are glob patterns of the paths of subpackages which are not
+	// fetched, relative to the root package. Excluding a subpackage
+	// excludes its subpackages.
+	Exclude []string
+
+	// Depth is the maximum nesting depth of the fetched subpackages, e.g. 1
+	// only fetches the direct subpackages of the root package. Subpackages
+	// at any depth are fetched if 0.
+	Depth int
 }
 
 // Run runs the Command.
@@ -79,20 +99,28 @@ func (c Command) Run(ctx context.Context) error {
 		return errors.E(op, errors.IO, types.UniquePath(c.Destination), err)
 	}
 
-	// normalize path to a filepath
-	repoDir := c.Git.Directory
-	if !strings.HasSuffix(repoDir, "file://") {
-		// Convert from separator to slash and back.
-		// This ensures all separators are compatible with the local OS.
-		repoDir = filepath.FromSlash(filepath.ToSlash(repoDir))
-	}
-	c.Git.Directory = repoDir
-
 	kf := kptfileutil.DefaultKptfile(c.Name)
-	kf.Upstream = &kptfilev1.Upstream{
-		Type:           kptfilev1.GitOrigin,
-		Git:            c.Git,
-		UpdateStrategy: c.UpdateStrategy,
+	if c.Oci != nil {
+		kf.Upstream = &kptfilev1.Upstream{
+			Type:           kptfilev1.OciOrigin,
+			Oci:            c.Oci,
+			UpdateStrategy: c.UpdateStrategy,
+		}
+	} else {
+		// normalize path to a filepath
+		repoDir := c.Git.Directory
+		if !strings.HasSuffix(repoDir, "file://") {
+			// Convert from separator to slash and back.
+			// This ensures all separators are compatible with the local OS.
+			repoDir = filepath.FromSlash(filepath.ToSlash(repoDir))
+		}
+		c.Git.Directory = repoDir
+
+		kf.Upstream = &kptfilev1.Upstream{
+			Type:           kptfilev1.GitOrigin,
+			Git:            c.Git,
+			UpdateStrategy: c.UpdateStrategy,
+		}
 	}
 
 	err = kptfileutil.WriteFile(c.Destination, kf)
@@ -154,6 +182,7 @@ func (c Command) fetchPackages(ctx context.Context, rootPkg *pkg.Pkg) error {
 	// for remote subpackages.
 	s := stack.NewPkgStack()
 	s.Push(rootPkg)
+	depths := map[types.UniquePath]int{rootPkg.UniquePath: 0}
 
 	for s.Len() > 0 {
 		p := s.Pop()
@@ -166,7 +195,11 @@ func (c Command) fetchPackages(ctx context.Context, rootPkg *pkg.Pkg) error {
 		if kf.Upstream != nil && kf.UpstreamLock == nil {
 			packageCount++
 			pr.PrintPackage(p, !(p == rootPkg))
-			pr.Printf("Fetching %s@%s\n", kf.Upstream.Git.Repo, kf.Upstream.Git.Ref)
+			if kf.Upstream.Type == kptfilev1.OciOrigin {
+				pr.Printf("Fetching %s\n", kf.Upstream.Oci.Image)
+			} else {
+				pr.Printf("Fetching %s@%s\n", kf.Upstream.Git.Repo, kf.Upstream.Git.Ref)
+			}
 			err := (&fetch.Command{
 				Pkg: p,
 			}).Run(ctx)
@@ -180,6 +213,19 @@ func (c Command) fetchPackages(ctx context.Context, rootPkg *pkg.Pkg) error {
 			return errors.E(op, p.UniquePath, err)
 		}
 		for _, subPkg := range subPkgs {
+			depth := depths[p.UniquePath] + 1
+			rel, err := filepath.Rel(rootPkg.UniquePath.String(), subPkg.UniquePath.String())
+			if err != nil {
+				return errors.E(op, subPkg.UniquePath, err)
+			}
+			if !c.includesSubpackage(filepath.ToSlash(rel), depth, c.matchesInclude(p, rootPkg)) {
+				pr.Printf("Skipping subpackage %q.\n", rel)
+				if err := os.RemoveAll(subPkg.UniquePath.String()); err != nil {
+					return errors.E(op, errors.IO, subPkg.UniquePath, err)
+				}
+				continue
+			}
+			depths[subPkg.UniquePath] = depth
 			s.Push(subPkg)
 		}
 	}
@@ -187,25 +233,85 @@ func (c Command) fetchPackages(ctx context.Context, rootPkg *pkg.Pkg) error {
 	return nil
 }
 
+// includesSubpackage returns true if the subpackage at the relative path rel
+// and the given depth should be fetched. parentIncluded is true if the parent
+// package was matched by an include pattern.
+func (c Command) includesSubpackage(rel string, depth int, parentIncluded bool) bool {
+	if c.Depth > 0 && depth > c.Depth {
+		return false
+	}
+	for _, pattern := range c.Exclude {
+		if match, _ := path.Match(pattern, rel); match {
+			return false
+		}
+	}
+	if len(c.Include) == 0 || parentIncluded {
+		return true
+	}
+	segments := strings.Split(rel, "/")
+	for _, pattern := range c.Include {
+		if match, _ := path.Match(pattern, rel); match {
+			return true
+		}
+		// Keep the subpackages holding the subpackages the pattern may
+		// match.
+		patternSegments := strings.Split(pattern, "/")
+		if len(patternSegments) > len(segments) {
+			if match, _ := path.Match(strings.Join(patternSegments[:len(segments)], "/"), rel); match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesInclude returns true if the path of the package p relative to the
+// root package, or the path of one of its parent packages, matches an include
+// pattern.
+func (c Command) matchesInclude(p, rootPkg *pkg.Pkg) bool {
+	rel, err := filepath.Rel(rootPkg.UniquePath.String(), p.UniquePath.String())
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for rel != "." && rel != "/" {
+		for _, pattern := range c.Include {
+			if match, _ := path.Match(pattern, rel); match {
+				return true
+			}
+		}
+		rel = path.Dir(rel)
+	}
+	return false
+}
+
 // DefaultValues sets values to the default values if they were unspecified
 func (c *Command) DefaultValues() error {
 	const op errors.Op = "get.DefaultValues"
-	if c.Git == nil {
+	switch {
+	case c.Git != nil && c.Oci != nil:
+		return errors.E(op, errors.InvalidParam, fmt.Errorf("must specify only one of git repo and oci image information"))
+	case c.Oci != nil:
+		if len(c.Oci.Image) == 0 {
+			return errors.E(op, errors.MissingParam, fmt.Errorf("must specify image"))
+		}
+	case c.Git != nil:
+		g := c.Git
+		if len(g.Repo) == 0 {
+			return errors.E(op, errors.MissingParam, fmt.Errorf("must specify repo"))
+		}
+		if len(g.Ref) == 0 {
+			return errors.E(op, errors.MissingParam, fmt.Errorf("must specify ref"))
+		}
+		if len(g.Directory) == 0 {
+			return errors.E(op, errors.MissingParam, fmt.Errorf("must specify directory"))
+		}
+	default:
 		return errors.E(op, errors.MissingParam, fmt.Errorf("must specify git repo information"))
 	}
-	g := c.Git
-	if len(g.Repo) == 0 {
-		return errors.E(op, errors.MissingParam, fmt.Errorf("must specify repo"))
-	}
-	if len(g.Ref) == 0 {
-		return errors.E(op, errors.MissingParam, fmt.Errorf("must specify ref"))
-	}
 	if len(c.Destination) == 0 {
 		return errors.E(op, errors.MissingParam, fmt.Errorf("must specify destination"))
 	}
-	if len(g.Directory) == 0 {
-		return errors.E(op, errors.MissingParam, fmt.Errorf("must specify directory"))
-	}
 
 	if !filepath.IsAbs(c.Destination) {
 		return errors.E(op, errors.InvalidParam, fmt.Errorf("destination must be an absolute path"))
@@ -216,6 +322,16 @@ func (c *Command) DefaultValues() error {
 		c.Name = filepath.Base(c.Destination)
 	}
 
+	for _, pattern := range append(append([]string{}, c.Include...), c.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.E(op, errors.InvalidParam, fmt.Errorf("invalid subpackage pattern %q: %w", pattern, err))
+		}
+	}
+
+	if c.Depth < 0 {
+		return errors.E(op, errors.InvalidParam, fmt.Errorf("depth must not be negative"))
+	}
+
 	// default the update strategy to resource-merge
 	if len(c.UpdateStrategy) == 0 {
 		c.UpdateStrategy = kptfilev1.ResourceMerge
@@ -588,6 +588,51 @@ func TestCommand_Run_ref(t *testing.T) {
 				WithResource(pkgbuilder.DeploymentResource).
 				WithResource(pkgbuilder.SecretResource),
 		},
+		"version range": {
+			reposContent: map[string][]testutil.Content{
+				testutil.Upstream: {
+					{
+						Pkg: pkgbuilder.NewRootPkg().
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("kafka").
+									WithResource(pkgbuilder.DeploymentResource),
+							),
+						Branch: "master",
+						Tag:    "kafka/v1.2.0",
+					},
+					{
+						Pkg: pkgbuilder.NewRootPkg().
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("kafka").
+									WithResource(pkgbuilder.DeploymentResource).
+									WithResource(pkgbuilder.SecretResource),
+							),
+						Tag: "kafka/v1.3.1",
+					},
+					{
+						Pkg: pkgbuilder.NewRootPkg().
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("kafka").
+									WithResource(pkgbuilder.DeploymentResource).
+									WithResource(pkgbuilder.ConfigMapResource),
+							),
+						Tag: "kafka/v2.0.0",
+					},
+				},
+			},
+			directory: "kafka",
+			ref: func(_ map[string]*testutil.TestGitRepo) string {
+				return "^1.2.0"
+			},
+			expected: pkgbuilder.NewRootPkg().
+				WithKptfile(
+					pkgbuilder.NewKptfile().
+						WithUpstreamRef(testutil.Upstream, "kafka", "^1.2.0", "resource-merge").
+						WithUpstreamLockRef(testutil.Upstream, "kafka", "kafka/v1.3.1", 1),
+				).
+				WithResource(pkgbuilder.DeploymentResource).
+				WithResource(pkgbuilder.SecretResource),
+		},
 		"commit sha": {
 			reposContent: map[string][]testutil.Content{
 				testutil.Upstream: {
@@ -892,6 +937,9 @@ func TestCommand_Run_subpackages(t *testing.T) {
 		directory      string
 		ref            string
 		updateStrategy kptfilev1.UpdateStrategyType
+		include        []string
+		exclude        []string
+		depth          int
 		reposContent   map[string][]testutil.Content
 		expectedResult *pkgbuilder.RootPkg
 		expectedErrMsg string
@@ -1196,6 +1244,208 @@ func TestCommand_Run_subpackages(t *testing.T) {
 						WithResource(pkgbuilder.ConfigMapResource),
 				),
 		},
+		"package with included subpackages": {
+			directory: "/",
+			ref:       "master",
+			include:   []string{"db"},
+			reposContent: map[string][]testutil.Content{
+				testutil.Upstream: {
+					{
+						Branch: "master",
+						Pkg: pkgbuilder.NewRootPkg().
+							WithKptfile().
+							WithResource(pkgbuilder.DeploymentResource).
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("app").
+									WithKptfile().
+									WithResource(pkgbuilder.DeploymentResource),
+								pkgbuilder.NewSubPkg("db").
+									WithKptfile().
+									WithResource(pkgbuilder.ConfigMapResource).
+									WithSubPackages(
+										pkgbuilder.NewSubPkg("backup").
+											WithKptfile().
+											WithResource(pkgbuilder.SecretResource),
+									),
+							),
+					},
+				},
+			},
+			expectedResult: pkgbuilder.NewRootPkg().
+				WithKptfile(
+					pkgbuilder.NewKptfile().
+						WithUpstreamRef("upstream", "/", "master", "resource-merge").
+						WithUpstreamLockRef("upstream", "/", "master", 0),
+				).
+				WithResource(pkgbuilder.DeploymentResource).
+				WithSubPackages(
+					pkgbuilder.NewSubPkg("db").
+						WithKptfile().
+						WithResource(pkgbuilder.ConfigMapResource).
+						WithSubPackages(
+							pkgbuilder.NewSubPkg("backup").
+								WithKptfile().
+								WithResource(pkgbuilder.SecretResource),
+						),
+				),
+		},
+		"package with excluded subpackages": {
+			directory: "/",
+			ref:       "master",
+			exclude:   []string{"app", "db/*"},
+			reposContent: map[string][]testutil.Content{
+				testutil.Upstream: {
+					{
+						Branch: "master",
+						Pkg: pkgbuilder.NewRootPkg().
+							WithKptfile().
+							WithResource(pkgbuilder.DeploymentResource).
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("app").
+									WithKptfile().
+									WithResource(pkgbuilder.DeploymentResource),
+								pkgbuilder.NewSubPkg("db").
+									WithKptfile().
+									WithResource(pkgbuilder.ConfigMapResource).
+									WithSubPackages(
+										pkgbuilder.NewSubPkg("backup").
+											WithKptfile().
+											WithResource(pkgbuilder.SecretResource),
+									),
+							),
+					},
+				},
+			},
+			expectedResult: pkgbuilder.NewRootPkg().
+				WithKptfile(
+					pkgbuilder.NewKptfile().
+						WithUpstreamRef("upstream", "/", "master", "resource-merge").
+						WithUpstreamLockRef("upstream", "/", "master", 0),
+				).
+				WithResource(pkgbuilder.DeploymentResource).
+				WithSubPackages(
+					pkgbuilder.NewSubPkg("db").
+						WithKptfile().
+						WithResource(pkgbuilder.ConfigMapResource),
+				),
+		},
+		"package with subpackage depth": {
+			directory: "/",
+			ref:       "master",
+			depth:     1,
+			reposContent: map[string][]testutil.Content{
+				testutil.Upstream: {
+					{
+						Branch: "master",
+						Pkg: pkgbuilder.NewRootPkg().
+							WithKptfile().
+							WithResource(pkgbuilder.DeploymentResource).
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("app").
+									WithKptfile().
+									WithResource(pkgbuilder.DeploymentResource),
+								pkgbuilder.NewSubPkg("db").
+									WithKptfile().
+									WithResource(pkgbuilder.ConfigMapResource).
+									WithSubPackages(
+										pkgbuilder.NewSubPkg("backup").
+											WithKptfile().
+											WithResource(pkgbuilder.SecretResource),
+									),
+							),
+					},
+				},
+			},
+			expectedResult: pkgbuilder.NewRootPkg().
+				WithKptfile(
+					pkgbuilder.NewKptfile().
+						WithUpstreamRef("upstream", "/", "master", "resource-merge").
+						WithUpstreamLockRef("upstream", "/", "master", 0),
+				).
+				WithResource(pkgbuilder.DeploymentResource).
+				WithSubPackages(
+					pkgbuilder.NewSubPkg("app").
+						WithKptfile().
+						WithResource(pkgbuilder.DeploymentResource),
+					pkgbuilder.NewSubPkg("db").
+						WithKptfile().
+						WithResource(pkgbuilder.ConfigMapResource),
+				),
+		},
+		"package with included nested subpackage": {
+			directory: "/",
+			ref:       "master",
+			include:   []string{"db/backup"},
+			reposContent: map[string][]testutil.Content{
+				testutil.Upstream: {
+					{
+						Branch: "master",
+						Pkg: pkgbuilder.NewRootPkg().
+							WithKptfile().
+							WithResource(pkgbuilder.DeploymentResource).
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("app").
+									WithKptfile().
+									WithResource(pkgbuilder.DeploymentResource),
+								pkgbuilder.NewSubPkg("db").
+									WithKptfile().
+									WithResource(pkgbuilder.ConfigMapResource).
+									WithSubPackages(
+										pkgbuilder.NewSubPkg("backup").
+											WithKptfile().
+											WithResource(pkgbuilder.SecretResource),
+									),
+							),
+					},
+				},
+			},
+			expectedResult: pkgbuilder.NewRootPkg().
+				WithKptfile(
+					pkgbuilder.NewKptfile().
+						WithUpstreamRef("upstream", "/", "master", "resource-merge").
+						WithUpstreamLockRef("upstream", "/", "master", 0),
+				).
+				WithResource(pkgbuilder.DeploymentResource).
+				WithSubPackages(
+					pkgbuilder.NewSubPkg("db").
+						WithKptfile().
+						WithResource(pkgbuilder.ConfigMapResource).
+						WithSubPackages(
+							pkgbuilder.NewSubPkg("backup").
+								WithKptfile().
+								WithResource(pkgbuilder.SecretResource),
+						),
+				),
+		},
+		"invalid subpackage pattern": {
+			directory: "/",
+			ref:       "master",
+			include:   []string{"[db"},
+			reposContent: map[string][]testutil.Content{
+				testutil.Upstream: {
+					{
+						Branch: "master",
+						Pkg: pkgbuilder.NewRootPkg().
+							WithKptfile().
+							WithResource(pkgbuilder.DeploymentResource).
+							WithSubPackages(
+								pkgbuilder.NewSubPkg("app").
+									WithKptfile().
+									WithResource(pkgbuilder.DeploymentResource),
+								pkgbuilder.NewSubPkg("db").
+									WithKptfile().
+									WithResource(pkgbuilder.ConfigMapResource).
+									WithSubPackages(
+										pkgbuilder.NewSubPkg("backup").
+											WithKptfile().
+											WithResource(pkgbuilder.SecretResource),
+									),
+							),
+					},
+				},
+			},
+			expectedErrMsg: `invalid subpackage pattern "[db"`,
+		},
 		"fetch subpackage on a different branch than master": {
 			directory: "/bar",
 			ref:       "main",
@@ -1395,6 +1645,9 @@ func TestCommand_Run_subpackages(t *testing.T) {
 				},
 				Destination:    destinationDir,
 				UpdateStrategy: tc.updateStrategy,
+				Include:        tc.include,
+				Exclude:        tc.exclude,
+				Depth:          tc.depth,
 			}.Run(fake.CtxWithDefaultPrinter())
 
 			if tc.expectedErrMsg != "" {
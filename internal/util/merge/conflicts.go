@@ -0,0 +1,318 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// The git-style markers around the local and upstream versions of a
+// conflicting resource.
+const (
+	ConflictMarkerLocal    = "<<<<<<< local"
+	ConflictMarkerSep      = "======="
+	ConflictMarkerUpstream = ">>>>>>> upstream"
+)
+
+// ConflictSide is the version of the resources accepted when resolving
+// conflicts.
+type ConflictSide string
+
+const (
+	// Ours accepts the local version of the resources.
+	Ours ConflictSide = "ours"
+	// Theirs accepts the upstream version of the resources.
+	Theirs ConflictSide = "theirs"
+)
+
+// Conflict is a resource which was changed in conflicting ways locally and
+// in upstream, i.e. at least one field was changed to different values.
+type Conflict struct {
+	// File is the absolute path of the file holding the local resource.
+	File string
+	// Fields are the paths of the fields with conflicting changes.
+	Fields []string
+	// Local and Upstream are the local and upstream versions of the
+	// resource.
+	Local    *yaml.RNode
+	Upstream *yaml.RNode
+}
+
+// ID returns a human readable identifier of the conflicting resource.
+func (c Conflict) ID() string {
+	name := c.Local.GetName()
+	if ns := c.Local.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	return c.Local.GetKind() + " " + name
+}
+
+// conflictingFields returns the paths of the fields of the resource which
+// were changed locally and in upstream to different values. origin is nil
+// if the resource was added both locally and in upstream.
+func conflictingFields(origin, upstream, local *yaml.RNode) ([]string, error) {
+	values := make([]map[string]string, 3)
+	for i, n := range []*yaml.RNode{origin, upstream, local} {
+		values[i] = map[string]string{}
+		if n == nil {
+			continue
+		}
+		c := n.Copy()
+		if err := stripKyamlAnnos(c); err != nil {
+			return nil, err
+		}
+		flattenFields("", c.YNode(), values[i])
+	}
+	o, u, l := values[0], values[1], values[2]
+
+	paths := map[string]bool{}
+	for _, m := range values {
+		for p := range m {
+			paths[p] = true
+		}
+	}
+	var fields []string
+	for p := range paths {
+		ov, inO := o[p]
+		uv, inU := u[p]
+		lv, inL := l[p]
+		upstreamChanged := inO != inU || ov != uv
+		localChanged := inO != inL || ov != lv
+		if upstreamChanged && localChanged && (inU != inL || uv != lv) {
+			fields = append(fields, p)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// flattenFields adds the scalar fields of node to fields keyed by their
+// path. Elements of lists of objects with a name are keyed by the name, like
+// they are merged, and other list elements by their index.
+func flattenFields(path string, node *yaml.Node, fields map[string]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			flattenFields(path, n, fields)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			flattenFields(key, node.Content[i+1], fields)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			key := fmt.Sprintf("%s[%d]", path, i)
+			if name := elementName(n); name != "" {
+				key = fmt.Sprintf("%s[name=%s]", path, name)
+			}
+			flattenFields(key, n, fields)
+		}
+	case yaml.AliasNode:
+		flattenFields(path, node.Alias, fields)
+	default:
+		fields[path] = node.Value
+	}
+}
+
+// elementName returns the name of a list element which is an object.
+func elementName(node *yaml.Node) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "name" && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// WriteConflictMarkers replaces the conflicting resources in their files with
+// the local and upstream versions of the resource between git-style conflict
+// markers. It returns the files with conflict markers, sorted by path.
+func WriteConflictMarkers(conflicts []Conflict) ([]string, error) {
+	byFile := map[string][]Conflict{}
+	for _, c := range conflicts {
+		byFile[c.File] = append(byFile[c.File], c)
+	}
+	var files []string
+	for file, fileConflicts := range byFile {
+		if err := writeConflictMarkers(file, fileConflicts); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func writeConflictMarkers(file string, conflicts []Conflict) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:            bytes.NewReader(b),
+		PreserveSeqIndent: true,
+		WrapBareSeqNode:   true,
+	}).Read()
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", file, err)
+	}
+
+	matcher := &ResourceMergeMatcher{}
+	var out strings.Builder
+	for i, node := range nodes {
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		var conflict *Conflict
+		for j := range conflicts {
+			if matcher.IsSameResource(node, conflicts[j].Local) {
+				conflict = &conflicts[j]
+				break
+			}
+		}
+		if conflict == nil {
+			s, err := resourceString(node)
+			if err != nil {
+				return err
+			}
+			out.WriteString(s)
+			continue
+		}
+		local, err := resourceString(conflict.Local)
+		if err != nil {
+			return err
+		}
+		upstream, err := resourceString(conflict.Upstream)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&out, "%s\n%s%s\n%s%s\n", ConflictMarkerLocal, local, ConflictMarkerSep, upstream, ConflictMarkerUpstream)
+	}
+	return os.WriteFile(file, []byte(out.String()), info.Mode())
+}
+
+// resourceString returns the resource as it is written to a file.
+func resourceString(node *yaml.RNode) (string, error) {
+	n := node.Copy()
+	if err := stripKyamlAnnos(n); err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := (kio.ByteWriter{Writer: &b}).Write([]*yaml.RNode{n}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// HasConflictMarkers returns true if content has unresolved conflicts.
+func HasConflictMarkers(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if line == ConflictMarkerLocal {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveConflicts returns content with each conflict replaced by the version
+// of the given side.
+func ResolveConflicts(content string, side ConflictSide) (string, error) {
+	const (
+		outside = iota
+		inLocal
+		inUpstream
+	)
+	state := outside
+	var out strings.Builder
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		switch strings.TrimSuffix(line, "\n") {
+		case ConflictMarkerLocal:
+			if state != outside {
+				return "", fmt.Errorf("line %d: unexpected conflict marker %q", i+1, ConflictMarkerLocal)
+			}
+			state = inLocal
+			continue
+		case ConflictMarkerSep:
+			if state == inLocal {
+				state = inUpstream
+				continue
+			}
+		case ConflictMarkerUpstream:
+			if state != inUpstream {
+				return "", fmt.Errorf("line %d: unexpected conflict marker %q", i+1, ConflictMarkerUpstream)
+			}
+			state = outside
+			continue
+		}
+		if state == outside || (state == inLocal && side == Ours) || (state == inUpstream && side == Theirs) {
+			out.WriteString(line)
+		}
+	}
+	if state != outside {
+		return "", fmt.Errorf("unterminated conflict, missing %q", ConflictMarkerUpstream)
+	}
+	return out.String(), nil
+}
+
+// FilesWithConflictMarkers returns the files in the package, including its
+// subpackages, which have unresolved conflicts. The paths are relative to
+// the package and sorted.
+func FilesWithConflictMarkers(pkgPath string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(pkgPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if HasConflictMarkers(string(b)) {
+			rel, err := filepath.Rel(pkgPath, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/internal/util/merge"
+	"github.com/stretchr/testify/assert"
+)
+
+const conflictsOrigin = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: b
+`
+
+const conflictsUpstream = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: c
+`
+
+const conflictsLocal = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: b
+  d: e
+`
+
+func TestMerge3_Conflicts(t *testing.T) {
+	dirs := map[string]string{}
+	for name, content := range map[string]string{
+		"origin":   conflictsOrigin,
+		"upstream": conflictsUpstream,
+		"local":    conflictsLocal,
+	} {
+		dirs[name] = t.TempDir()
+		if err := os.WriteFile(filepath.Join(dirs[name], "resources.yaml"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conflicts, err := merge.Merge3{
+		OriginalPath: dirs["origin"],
+		UpdatedPath:  dirs["upstream"],
+		DestPath:     dirs["local"],
+		MergeOnPath:  true,
+	}.MergeAndFindConflicts()
+	if !assert.NoError(t, err) || !assert.Len(t, conflicts, 1) {
+		return
+	}
+	// Only the replicas were changed to different values, the image was
+	// changed to the same value and the ConfigMap changes don't overlap.
+	c := conflicts[0]
+	assert.Equal(t, filepath.Join(dirs["local"], "resources.yaml"), c.File)
+	assert.Equal(t, []string{"spec.replicas"}, c.Fields)
+	assert.Equal(t, "Deployment app", c.ID())
+
+	files, err := merge.WriteConflictMarkers(conflicts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{c.File}, files)
+	b, err := os.ReadFile(c.File)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `<<<<<<< local
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+=======
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+>>>>>>> upstream
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: c
+  d: e
+`, string(b))
+
+	// Conflict markers in other files, e.g. written by the
+	// preserve-local-changes strategy, are found too.
+	readme := filepath.Join(dirs["local"], "README.md")
+	assert.NoError(t, os.WriteFile(readme, []byte("<<<<<<< local\na\n=======\nb\n>>>>>>> upstream\n"), 0600))
+
+	found, err := merge.FilesWithConflictMarkers(dirs["local"])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "resources.yaml"}, found)
+}
+
+func TestResolveConflicts(t *testing.T) {
+	content := `a: b
+<<<<<<< local
+c: local
+=======
+c: upstream
+>>>>>>> upstream
+---
+d: e
+`
+	testCases := map[string]struct {
+		content string
+		side    merge.ConflictSide
+		want    string
+		wantErr string
+	}{
+		"ours": {
+			content: content,
+			side:    merge.Ours,
+			want:    "a: b\nc: local\n---\nd: e\n",
+		},
+		"theirs": {
+			content: content,
+			side:    merge.Theirs,
+			want:    "a: b\nc: upstream\n---\nd: e\n",
+		},
+		"no conflicts": {
+			content: "a: b\n",
+			side:    merge.Ours,
+			want:    "a: b\n",
+		},
+		"unterminated conflict": {
+			content: "<<<<<<< local\na: b\n=======\n",
+			side:    merge.Ours,
+			wantErr: `unterminated conflict, missing ">>>>>>> upstream"`,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			got, err := merge.ResolveConflicts(tc.content, tc.side)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.False(t, merge.HasConflictMarkers(got))
+		})
+	}
+}
@@ -51,6 +51,14 @@ type Merge3 struct {
 }
 
 func (m Merge3) Merge() error {
+	_, err := m.MergeAndFindConflicts()
+	return err
+}
+
+// MergeAndFindConflicts performs the merge like Merge, and returns the
+// resources with fields changed to different values locally and in upstream.
+// The upstream values of those fields win in the merged resources.
+func (m Merge3) MergeAndFindConflicts() ([]Conflict, error) {
 	// If subpackages are not included when doing the merge, first
 	// look up the known subpackages in destination so we can make sure
 	// those are ignored when reading files from original and updated.
@@ -59,7 +67,7 @@ func (m Merge3) Merge() error {
 		var err error
 		relPaths, err = m.findExclusions()
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -104,17 +112,21 @@ func (m Merge3) Merge() error {
 	})
 
 	rmMatcher := ResourceMergeMatcher{MergeOnPath: m.MergeOnPath}
-	resourceHandler := resourceHandler{}
+	resourceHandler := resourceHandler{destPath: m.DestPath}
 	kyamlMerge := filters.Merge3{
 		Matcher: &rmMatcher,
 		Handler: &resourceHandler,
 	}
 
-	return kio.Pipeline{
+	err := kio.Pipeline{
 		Inputs:  inputs,
 		Filters: []kio.Filter{kyamlMerge},
 		Outputs: []kio.Writer{dest},
 	}.Execute()
+	if err != nil {
+		return nil, err
+	}
+	return resourceHandler.conflicts, nil
 }
 
 func (m Merge3) findExclusions() ([]string, error) {
@@ -297,6 +309,8 @@ func metadataComment(node *yaml.RNode) string {
 // there is no diff between origin and local.
 type resourceHandler struct {
 	keptResources []*yaml.RNode
+	destPath      string
+	conflicts     []Conflict
 }
 
 func (r *resourceHandler) Handle(origin, upstream, local *yaml.RNode) (filters.ResourceMergeStrategy, error) {
@@ -327,11 +341,38 @@ func (r *resourceHandler) Handle(origin, upstream, local *yaml.RNode) (filters.R
 	case origin != nil && local == nil:
 		strategy = filters.Skip
 	default:
+		if err := r.findConflict(origin, upstream, local); err != nil {
+			return strategy, err
+		}
 		strategy = filters.Merge
 	}
 	return strategy, nil
 }
 
+// findConflict records the resource as a conflict if fields were changed to
+// different values locally and in upstream. The Kptfile is merged separately
+// and never conflicts.
+func (r *resourceHandler) findConflict(origin, upstream, local *yaml.RNode) error {
+	if local.GetKind() == kptfilev1.KptFileKind {
+		return nil
+	}
+	fields, err := conflictingFields(origin, upstream, local)
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+	if err := kioutil.CopyLegacyAnnotations(local); err != nil {
+		return err
+	}
+	path := local.GetAnnotations()[kioutil.PathAnnotation]
+	r.conflicts = append(r.conflicts, Conflict{
+		File:     filepath.Join(r.destPath, path),
+		Fields:   fields,
+		Local:    local.Copy(),
+		Upstream: upstream.Copy(),
+	})
+	return nil
+}
+
 func (*resourceHandler) equals(r1, r2 *yaml.RNode) (bool, error) {
 	// We need to create new copies of the resources since we need to
 	// mutate them before comparing them.
@@ -0,0 +1,167 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package output implements the machine-readable output of kpt commands.
+//
+// Commands supporting machine-readable output write a single CommandResult
+// in the format selected with --output, or --results-format for the fn
+// commands, to stdout, whether they succeed or fail. Human-oriented progress
+// messages are written to stderr as usual. The exit code is 0 if the command
+// succeeded and 1 otherwise, and is also recorded in the CommandResult.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Format is a machine-readable output format.
+type Format string
+
+const (
+	// None selects the human-oriented output of the command.
+	None Format = ""
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+var SupportedFormats = []Format{JSON, YAML}
+
+func SupportedFormatsLabel() string {
+	var labels []string
+	for _, f := range SupportedFormats {
+		labels = append(labels, string(f))
+	}
+	return strings.Join(labels, ", ")
+}
+
+// IsFormat returns true if s is a machine-readable output format.
+func IsFormat(s string) bool {
+	for _, f := range SupportedFormats {
+		if string(f) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFormat returns the output format s, which must be empty or one of
+// the supported formats.
+func ParseFormat(s string) (Format, error) {
+	if s == "" || IsFormat(s) {
+		return Format(s), nil
+	}
+	return None, fmt.Errorf("invalid output %q: supported outputs are: %s", s, SupportedFormatsLabel())
+}
+
+// Exit codes of kpt commands.
+const (
+	ExitSuccess = 0
+	ExitFailure = 1
+)
+
+const (
+	ResultAPIVersion = "kpt.dev/v1"
+	ResultKind       = "CommandResult"
+)
+
+// CommandResult is the machine-readable result of a kpt command.
+type CommandResult struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Command is the command that was executed, e.g. `pkg get`.
+	Command string `json:"command"`
+	// ExitCode is the exit code of the command.
+	ExitCode int `json:"exitCode"`
+	// Error is the error message if the command failed.
+	Error string `json:"error,omitempty"`
+	// Package is the absolute path of the package the command operated on,
+	// if any.
+	Package string `json:"package,omitempty"`
+	// Data holds the command specific result, e.g. the changed resources
+	// for `pkg diff`.
+	Data interface{} `json:"data,omitempty"`
+}
+
+// UpstreamData is the result data of commands fetching a package from
+// upstream, i.e. `pkg get` and `pkg update`.
+type UpstreamData struct {
+	Upstream     *kptfilev1.Upstream     `json:"upstream,omitempty"`
+	UpstreamLock *kptfilev1.UpstreamLock `json:"upstreamLock,omitempty"`
+}
+
+// FnResultsData returns the function results of `fn render` and `fn eval`
+// as result data. The results are converted using their YAML field names, so
+// they match the results written to --results-dir.
+func FnResultsData(results *fnresult.ResultList) interface{} {
+	if results == nil {
+		return nil
+	}
+	b, err := kyaml.Marshal(results)
+	if err != nil {
+		return nil
+	}
+	var data interface{}
+	if err := yaml.Unmarshal(b, &data); err != nil {
+		return nil
+	}
+	return data
+}
+
+// Write writes the result of the command that completed with err to w in
+// the given format, and returns err so callers can return it as the result
+// of the command.
+func Write(w io.Writer, format Format, command, pkgPath string, data interface{}, err error) error {
+	result := CommandResult{
+		APIVersion: ResultAPIVersion,
+		Kind:       ResultKind,
+		Command:    command,
+		ExitCode:   ExitSuccess,
+		Package:    pkgPath,
+		Data:       data,
+	}
+	if err != nil {
+		result.ExitCode = ExitFailure
+		result.Error = err.Error()
+	}
+
+	var b []byte
+	var merr error
+	switch format {
+	case JSON:
+		b, merr = json.MarshalIndent(result, "", "  ")
+		b = append(b, '\n')
+	case YAML:
+		b, merr = yaml.Marshal(result)
+	default:
+		merr = fmt.Errorf("unsupported output format %q", format)
+	}
+	if merr != nil {
+		if err != nil {
+			return err
+		}
+		return merr
+	}
+	if _, werr := w.Write(b); werr != nil && err == nil {
+		return werr
+	}
+	return err
+}
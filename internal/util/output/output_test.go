@@ -0,0 +1,212 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"", "json", "yaml"} {
+		f, err := ParseFormat(s)
+		assert.NoError(t, err)
+		assert.Equal(t, Format(s), f)
+	}
+	_, err := ParseFormat("table")
+	assert.EqualError(t, err, `invalid output "table": supported outputs are: json, yaml`)
+}
+
+func TestWrite(t *testing.T) {
+	testCases := map[string]struct {
+		format   Format
+		data     interface{}
+		err      error
+		expected string
+	}{
+		"json success": {
+			format: JSON,
+			data:   map[string]string{"key": "value"},
+			expected: `{
+  "apiVersion": "kpt.dev/v1",
+  "kind": "CommandResult",
+  "command": "pkg get",
+  "exitCode": 0,
+  "package": "/pkg",
+  "data": {
+    "key": "value"
+  }
+}
+`,
+		},
+		"yaml failure": {
+			format: YAML,
+			err:    errors.New("failed"),
+			expected: `apiVersion: kpt.dev/v1
+command: pkg get
+error: failed
+exitCode: 1
+kind: CommandResult
+package: /pkg
+`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := Write(out, tc.format, "pkg get", "/pkg", tc.data, tc.err)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestFnResultsData(t *testing.T) {
+	results := fnresult.NewResultList()
+	results.Items = append(results.Items, fnresult.Result{
+		Image:    "gcr.io/kpt-fn/set-namespace:v0.4.1",
+		ExitCode: 1,
+	})
+	data := FnResultsData(results)
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "kpt.dev/v1",
+		"kind":       "FunctionResultList",
+		"metadata":   map[string]interface{}{"name": "fnresults"},
+		"exitCode":   float64(0),
+		"items": []interface{}{map[string]interface{}{
+			"image":    "gcr.io/kpt-fn/set-namespace:v0.4.1",
+			"exitCode": float64(1),
+		}},
+	}, data)
+	assert.Nil(t, FnResultsData(nil))
+}
+
+func TestWriteSARIF(t *testing.T) {
+	results := fnresult.NewResultList()
+	results.Items = append(results.Items, fnresult.Result{
+		Image:    "gcr.io/kpt-fn/kubeval:v0.3",
+		ExitCode: 1,
+		Results: []*framework.Result{{
+			Message:  "missing replicas",
+			Severity: framework.Error,
+			ResourceRef: &yaml.ResourceIdentifier{
+				TypeMeta: yaml.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				NameMeta: yaml.NameMeta{Name: "app", Namespace: "prod"},
+			},
+			Field: &framework.Field{Path: "spec.replicas"},
+			File:  &framework.File{Path: "deployment.yaml"},
+		}, {
+			Message: "deprecated field",
+		}},
+	})
+
+	b := &bytes.Buffer{}
+	err := WriteSARIF(b, "/tmp/pkg", results, errors.New("fn.render: pipeline failed"))
+	assert.EqualError(t, err, "fn.render: pipeline failed")
+	assert.Equal(t, `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "gcr.io/kpt-fn/kubeval:v0.3"
+        }
+      },
+      "originalUriBaseIds": {
+        "PKGROOT": {
+          "uri": "file:///tmp/pkg/"
+        }
+      },
+      "invocations": [
+        {
+          "executionSuccessful": false,
+          "exitCode": 1
+        }
+      ],
+      "results": [
+        {
+          "level": "error",
+          "message": {
+            "text": "missing replicas"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "deployment.yaml",
+                  "uriBaseId": "PKGROOT"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "apps/v1/Deployment/prod/app",
+                  "kind": "resource"
+                }
+              ]
+            }
+          ],
+          "properties": {
+            "field": "spec.replicas"
+          }
+        },
+        {
+          "level": "warning",
+          "message": {
+            "text": "deprecated field"
+          }
+        }
+      ]
+    },
+    {
+      "tool": {
+        "driver": {
+          "name": "kpt"
+        }
+      },
+      "invocations": [
+        {
+          "executionSuccessful": false,
+          "exitCode": 1,
+          "toolExecutionNotifications": [
+            {
+              "level": "error",
+              "message": {
+                "text": "fn.render: pipeline failed"
+              }
+            }
+          ]
+        }
+      ],
+      "results": []
+    }
+  ]
+}
+`, b.String())
+}
+
+func TestIsFnResultsFormat(t *testing.T) {
+	assert.True(t, IsFnResultsFormat("json"))
+	assert.True(t, IsFnResultsFormat("sarif"))
+	assert.False(t, IsFnResultsFormat("table"))
+	assert.False(t, IsFormat("sarif"))
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+
+	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+)
+
+// SARIF writes function results as a SARIF log, so CI systems can annotate
+// changes with the findings of validator functions. It is only supported by
+// commands running functions.
+const SARIF Format = "sarif"
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifPkgRoot is the base of the artifact URIs, which are relative to
+	// the package.
+	sarifPkgRoot = "PKGROOT"
+)
+
+// IsFnResultsFormat returns true if s is a machine-readable output format
+// for function results, i.e. a supported format or SARIF.
+func IsFnResultsFormat(s string) bool {
+	return IsFormat(s) || s == string(SARIF)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+	Invocations        []sarifInvocation           `json:"invocations"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ExitCode                   int                 `json:"exitCode"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifResult struct {
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the function results of the command that completed with
+// err to w as a SARIF log with a run per function, and returns err so
+// callers can return it as the result of the command. If err is set, a run
+// for kpt itself records the error.
+func WriteSARIF(w io.Writer, pkgPath string, results *fnresult.ResultList, err error) error {
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{},
+	}
+	var baseIDs map[string]sarifArtifactLoc
+	if pkgPath != "" {
+		baseIDs = map[string]sarifArtifactLoc{
+			sarifPkgRoot: {URI: "file://" + strings.TrimSuffix(path.Clean(pkgPath), "/") + "/"},
+		}
+	}
+	if results != nil {
+		for _, item := range results.Items {
+			name := item.Image
+			if name == "" {
+				name = item.ExecPath
+			}
+			run := sarifRun{
+				Tool:               sarifTool{Driver: sarifDriver{Name: name}},
+				OriginalURIBaseIDs: baseIDs,
+				Invocations: []sarifInvocation{{
+					ExecutionSuccessful: item.ExitCode == 0,
+					ExitCode:            item.ExitCode,
+				}},
+				Results: []sarifResult{},
+			}
+			for _, r := range item.Results {
+				run.Results = append(run.Results, toSARIFResult(r))
+			}
+			log.Runs = append(log.Runs, run)
+		}
+	}
+	if err != nil {
+		log.Runs = append(log.Runs, sarifRun{
+			Tool: sarifTool{Driver: sarifDriver{Name: "kpt"}},
+			Invocations: []sarifInvocation{{
+				ExecutionSuccessful: false,
+				ExitCode:            ExitFailure,
+				ToolExecutionNotifications: []sarifNotification{{
+					Level:   "error",
+					Message: sarifMessage{Text: err.Error()},
+				}},
+			}},
+			Results: []sarifResult{},
+		})
+	}
+
+	b, merr := json.MarshalIndent(log, "", "  ")
+	if merr != nil {
+		if err != nil {
+			return err
+		}
+		return merr
+	}
+	b = append(b, '\n')
+	if _, werr := w.Write(b); werr != nil && err == nil {
+		return werr
+	}
+	return err
+}
+
+func toSARIFResult(r *framework.Result) sarifResult {
+	result := sarifResult{
+		Level:   sarifLevel(r.Severity),
+		Message: sarifMessage{Text: r.Message},
+	}
+
+	var loc sarifLocation
+	if r.File != nil && r.File.Path != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLoc{URI: r.File.Path, URIBaseID: sarifPkgRoot},
+		}
+	}
+	if r.ResourceRef != nil {
+		ref := r.ResourceRef
+		name := strings.Join(nonEmpty(ref.APIVersion, ref.Kind, ref.Namespace, ref.Name), "/")
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: name, Kind: "resource"}}
+	}
+	if loc.PhysicalLocation != nil || loc.LogicalLocations != nil {
+		result.Locations = []sarifLocation{loc}
+	}
+	if r.Field != nil && r.Field.Path != "" {
+		result.Properties = map[string]string{"field": r.Field.Path}
+	}
+	return result
+}
+
+// sarifLevel returns the SARIF level of a function result severity.
+func sarifLevel(s framework.Severity) string {
+	switch s {
+	case framework.Error:
+		return "error"
+	case framework.Info:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+func nonEmpty(values ...string) []string {
+	var s []string
+	for _, v := range values {
+		if v != "" {
+			s = append(s, v)
+		}
+	}
+	return s
+}
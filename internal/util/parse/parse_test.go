@@ -267,3 +267,78 @@ func Test_GitParseArgs(t *testing.T) {
 		})
 	}
 }
+
+func Test_OciParseArgs(t *testing.T) {
+	tests := map[string]struct {
+		arg      string
+		expected OciTarget
+		errS     string
+	}{
+		"tag": {
+			arg: "oci://us-docker.pkg.dev/my-project/blueprints/basens:v1",
+			expected: OciTarget{
+				Oci:         v1.Oci{Image: "us-docker.pkg.dev/my-project/blueprints/basens:v1"},
+				Destination: "basens",
+			},
+		},
+		"digest": {
+			arg: "oci://localhost:5000/basens@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			expected: OciTarget{
+				Oci:         v1.Oci{Image: "localhost:5000/basens@sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+				Destination: "basens",
+			},
+		},
+		"invalid image": {
+			arg:  "oci://us-docker.pkg.dev/My-Project/basens:v1",
+			errS: `invalid image "us-docker.pkg.dev/My-Project/basens:v1"`,
+		},
+	}
+	for name, test := range tests {
+		test := test // capture range variable
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, IsOciArg(test.arg))
+			actual, err := OciParseArgs([]string{test.arg, ""})
+			if test.errS != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.errS)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_OciImageWithVersion(t *testing.T) {
+	digest := "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	tests := map[string]struct {
+		image    string
+		version  string
+		expected string
+	}{
+		"tag to tag": {
+			image:    "localhost:5000/basens:v1",
+			version:  "v2",
+			expected: "localhost:5000/basens:v2",
+		},
+		"tag to digest": {
+			image:    "localhost:5000/basens:v1",
+			version:  digest,
+			expected: "localhost:5000/basens@" + digest,
+		},
+		"digest to tag": {
+			image:    "localhost:5000/basens@" + digest,
+			version:  "v2",
+			expected: "localhost:5000/basens:v2",
+		},
+	}
+	for name, test := range tests {
+		test := test // capture range variable
+		t.Run(name, func(t *testing.T) {
+			actual, err := OciImageWithVersion(test.image, test.version)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -25,7 +25,34 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, key client.ObjectKey, new v1alpha1.PackageRevisionLifecycle) error {
+const (
+	// ProposeMessageAnnotation records the message given when the package
+	// revision was last proposed.
+	ProposeMessageAnnotation = "porch.kpt.dev/propose-message"
+	// ApproveMessageAnnotation records the message given when the package
+	// revision was approved.
+	ApproveMessageAnnotation = "porch.kpt.dev/approve-message"
+	// RejectMessageAnnotation records the message given when a proposal for
+	// the package revision was last rejected.
+	RejectMessageAnnotation = "porch.kpt.dev/reject-message"
+)
+
+// SetLifecycleMessage records a message describing a lifecycle transition
+// on the package revision. Empty messages are not recorded.
+func SetLifecycleMessage(pr *v1alpha1.PackageRevision, annotation, message string) {
+	if message == "" {
+		return
+	}
+	if pr.Annotations == nil {
+		pr.Annotations = map[string]string{}
+	}
+	pr.Annotations[annotation] = message
+}
+
+// UpdatePackageRevisionApproval moves a proposed package revision to the new
+// lifecycle through the approval subresource. A non-empty message is
+// recorded on the package revision.
+func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, key client.ObjectKey, new v1alpha1.PackageRevisionLifecycle, message string) error {
 	scheme := runtime.NewScheme()
 	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
 		return err
@@ -55,6 +82,11 @@ func UpdatePackageRevisionApproval(ctx context.Context, client rest.Interface, k
 
 	// Approve - change the package revision kind to "final".
 	pr.Spec.Lifecycle = new
+	if new == v1alpha1.PackageRevisionLifecyclePublished {
+		SetLifecycleMessage(&pr, ApproveMessageAnnotation, message)
+	} else {
+		SetLifecycleMessage(&pr, RejectMessageAnnotation, message)
+	}
 
 	opts := metav1.UpdateOptions{}
 	result := &v1alpha1.PackageRevision{}
@@ -0,0 +1,126 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpdatePackageRevisionApproval(t *testing.T) {
+	testCases := map[string]struct {
+		lifecycle  v1alpha1.PackageRevisionLifecycle
+		new        v1alpha1.PackageRevisionLifecycle
+		message    string
+		wantErr    bool
+		wantUpdate bool
+		wantAnnots map[string]string
+	}{
+		"approve with message": {
+			lifecycle:  v1alpha1.PackageRevisionLifecycleProposed,
+			new:        v1alpha1.PackageRevisionLifecyclePublished,
+			message:    "lgtm",
+			wantUpdate: true,
+			wantAnnots: map[string]string{ApproveMessageAnnotation: "lgtm"},
+		},
+		"reject with message": {
+			lifecycle:  v1alpha1.PackageRevisionLifecycleProposed,
+			new:        v1alpha1.PackageRevisionLifecycleDraft,
+			message:    "missing quota",
+			wantUpdate: true,
+			wantAnnots: map[string]string{RejectMessageAnnotation: "missing quota"},
+		},
+		"approve without message": {
+			lifecycle:  v1alpha1.PackageRevisionLifecycleProposed,
+			new:        v1alpha1.PackageRevisionLifecyclePublished,
+			wantUpdate: true,
+		},
+		"already published": {
+			lifecycle: v1alpha1.PackageRevisionLifecyclePublished,
+			new:       v1alpha1.PackageRevisionLifecyclePublished,
+		},
+		"cannot approve draft": {
+			lifecycle: v1alpha1.PackageRevisionLifecycleDraft,
+			new:       v1alpha1.PackageRevisionLifecyclePublished,
+			wantErr:   true,
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			var updated *v1alpha1.PackageRevision
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				const path = "/apis/porch.kpt.dev/v1alpha1/namespaces/ns/packagerevisions/pr"
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == path:
+					_ = json.NewEncoder(w).Encode(&v1alpha1.PackageRevision{
+						TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "PackageRevision"},
+						ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns"},
+						Spec:       v1alpha1.PackageRevisionSpec{Lifecycle: tc.lifecycle},
+					})
+				case r.Method == http.MethodPut && r.URL.Path == path+"/approval":
+					updated = &v1alpha1.PackageRevision{}
+					if err := json.NewDecoder(r.Body).Decode(updated); err != nil {
+						t.Errorf("failed to decode approval request: %v", err)
+					}
+					_ = json.NewEncoder(w).Encode(updated)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			flags := genericclioptions.NewConfigFlags(false)
+			flags.APIServer = &server.URL
+			c, err := CreateRESTClient(flags)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			err = UpdatePackageRevisionApproval(context.Background(), c, client.ObjectKey{Namespace: "ns", Name: "pr"}, tc.new, tc.message)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if !tc.wantUpdate {
+				assert.Nil(t, updated)
+				return
+			}
+			if assert.NotNil(t, updated) {
+				assert.Equal(t, tc.new, updated.Spec.Lifecycle)
+				for k, v := range tc.wantAnnots {
+					assert.Equal(t, v, updated.Annotations[k])
+				}
+				if len(tc.wantAnnots) == 0 {
+					assert.Empty(t, updated.Annotations)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ValidArgsFunc is the signature cobra uses for dynamic completion of
+// positional arguments and flag values.
+type ValidArgsFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// Completer provides shell completion of porch resource names by querying
+// the porch server configured by the kubeconfig flags. Completion never
+// fails; if porch can't be reached no suggestions are returned.
+type Completer struct {
+	Flags *genericclioptions.ConfigFlags
+}
+
+// PackageRevisionArgs completes package revision names for positional
+// arguments. At most maxArgs arguments are completed, or any number if
+// maxArgs is 0. If lifecycles are provided, only package revisions in one of
+// them are suggested.
+func (c Completer) PackageRevisionArgs(maxArgs int, lifecycles ...v1alpha1.PackageRevisionLifecycle) ValidArgsFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		prs, err := c.listPackageRevisions(cmd.Context())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var suggestions []string
+		for _, pr := range prs {
+			if len(lifecycles) > 0 && !hasLifecycle(pr.Spec.Lifecycle, lifecycles) {
+				continue
+			}
+			if !strings.HasPrefix(pr.Name, toComplete) || contains(args, pr.Name) {
+				continue
+			}
+			suggestions = append(suggestions, fmt.Sprintf("%s\t%s", pr.Name, describe(&pr)))
+		}
+		sort.Strings(suggestions)
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// RepositoryArgs completes repository names for positional arguments. At
+// most maxArgs arguments are completed, or any number if maxArgs is 0.
+func (c Completer) RepositoryArgs(maxArgs int) ValidArgsFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		suggestions, directive := c.Repositories(cmd, args, toComplete)
+		var filtered []string
+		for _, s := range suggestions {
+			if name, _, _ := strings.Cut(s, "\t"); !contains(args, name) {
+				filtered = append(filtered, s)
+			}
+		}
+		return filtered, directive
+	}
+}
+
+// Repositories completes the names of registered repositories.
+func (c Completer) Repositories(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cl, err := CreateClientWithFlags(c.Flags)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(contextOrBackground(cmd.Context()), Expiration)
+	defer cancel()
+
+	var repos configapi.RepositoryList
+	if err := cl.List(ctx, &repos, client.InNamespace(c.namespace())); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var suggestions []string
+	for _, repo := range repos.Items {
+		if !strings.HasPrefix(repo.Name, toComplete) {
+			continue
+		}
+		desc := repo.Spec.Description
+		if desc == "" {
+			desc = string(repo.Spec.Type)
+		}
+		suggestions = append(suggestions, fmt.Sprintf("%s\t%s", repo.Name, desc))
+	}
+	sort.Strings(suggestions)
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// PackageNames completes the names of packages. If the command has a
+// --repository flag that is set, only packages in that repository are
+// suggested.
+func (c Completer) PackageNames(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prs, err := c.listPackageRevisions(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repository := flagValue(cmd, "repository")
+	seen := map[string]bool{}
+	var suggestions []string
+	for _, pr := range prs {
+		if repository != "" && pr.Spec.RepositoryName != repository {
+			continue
+		}
+		name := pr.Spec.PackageName
+		if seen[name] || !strings.HasPrefix(name, toComplete) {
+			continue
+		}
+		seen[name] = true
+		suggestions = append(suggestions, name)
+	}
+	sort.Strings(suggestions)
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Revisions completes the revisions of published packages. If the command
+// has a --name or --repository flag that is set, only revisions of the
+// matching packages are suggested.
+func (c Completer) Revisions(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prs, err := c.listPackageRevisions(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	name := flagValue(cmd, "name")
+	repository := flagValue(cmd, "repository")
+	seen := map[string]bool{}
+	var suggestions []string
+	for _, pr := range prs {
+		if name != "" && pr.Spec.PackageName != name {
+			continue
+		}
+		if repository != "" && pr.Spec.RepositoryName != repository {
+			continue
+		}
+		revision := pr.Spec.Revision
+		if revision == "" || seen[revision] || !strings.HasPrefix(revision, toComplete) {
+			continue
+		}
+		seen[revision] = true
+		suggestions = append(suggestions, revision)
+	}
+	sort.Strings(suggestions)
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (c Completer) listPackageRevisions(ctx context.Context) ([]v1alpha1.PackageRevision, error) {
+	cl, err := CreateClientWithFlags(c.Flags)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(contextOrBackground(ctx), Expiration)
+	defer cancel()
+
+	var prs v1alpha1.PackageRevisionList
+	if err := cl.List(ctx, &prs, client.InNamespace(c.namespace())); err != nil {
+		return nil, err
+	}
+	return prs.Items, nil
+}
+
+// namespace returns the namespace set with --namespace, falling back to
+// the namespace of the current kubeconfig context.
+func (c Completer) namespace() string {
+	if c.Flags.Namespace != nil && *c.Flags.Namespace != "" {
+		return *c.Flags.Namespace
+	}
+	namespace, _, err := c.Flags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return ""
+	}
+	return namespace
+}
+
+// describe returns the description shown next to a package revision name
+// by shells that support completion descriptions.
+func describe(pr *v1alpha1.PackageRevision) string {
+	revision := pr.Spec.Revision
+	if revision == "" {
+		revision = string(pr.Spec.WorkspaceName)
+	}
+	return fmt.Sprintf("%s/%s %s (%s)", pr.Spec.RepositoryName, pr.Spec.PackageName, revision, pr.Spec.Lifecycle)
+}
+
+func hasLifecycle(lifecycle v1alpha1.PackageRevisionLifecycle, lifecycles []v1alpha1.PackageRevisionLifecycle) bool {
+	for _, l := range lifecycles {
+		if l == lifecycle {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func flagValue(cmd *cobra.Command, name string) string {
+	f := cmd.Flags().Lookup(name)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+func contextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
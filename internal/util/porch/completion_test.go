@@ -0,0 +1,154 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	configapi "github.com/GoogleContainerTools/kpt/porch/api/porchconfig/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func newCompletionServer(t *testing.T) *genericclioptions.ConfigFlags {
+	pr := func(name, repo, pkg, revision string, lifecycle v1alpha1.PackageRevisionLifecycle) v1alpha1.PackageRevision {
+		return v1alpha1.PackageRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: v1alpha1.PackageRevisionSpec{
+				RepositoryName: repo,
+				PackageName:    pkg,
+				Revision:       revision,
+				WorkspaceName:  "ws",
+				Lifecycle:      lifecycle,
+			},
+		}
+	}
+	prs := v1alpha1.PackageRevisionList{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "PackageRevisionList"},
+		Items: []v1alpha1.PackageRevision{
+			pr("blueprints-basens-v1", "blueprints", "basens", "v1", v1alpha1.PackageRevisionLifecyclePublished),
+			pr("blueprints-basens-v2", "blueprints", "basens", "v2", v1alpha1.PackageRevisionLifecyclePublished),
+			pr("blueprints-empty-v1", "blueprints", "empty", "v1", v1alpha1.PackageRevisionLifecyclePublished),
+			pr("deployments-app-draft", "deployments", "app", "", v1alpha1.PackageRevisionLifecycleDraft),
+		},
+	}
+	repos := configapi.RepositoryList{
+		TypeMeta: metav1.TypeMeta{APIVersion: configapi.GroupVersion.String(), Kind: "RepositoryList"},
+		Items: []configapi.Repository{
+			{ObjectMeta: metav1.ObjectMeta{Name: "blueprints", Namespace: "ns"}, Spec: configapi.RepositorySpec{Description: "Blueprints"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "deployments", Namespace: "ns"}, Spec: configapi.RepositorySpec{Type: configapi.RepositoryTypeGit}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/apis/porch.kpt.dev/v1alpha1/namespaces/ns/packagerevisions":
+			_ = json.NewEncoder(w).Encode(&prs)
+		case "/apis/config.porch.kpt.dev/v1alpha1/namespaces/ns/repositories":
+			_ = json.NewEncoder(w).Encode(&repos)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ns := "ns"
+	flags := genericclioptions.NewConfigFlags(false)
+	flags.APIServer = &server.URL
+	flags.Namespace = &ns
+	return flags
+}
+
+func newCompletionCommand(flags ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.Flags().String("name", "", "")
+	cmd.Flags().String("repository", "", "")
+	_ = cmd.Flags().Parse(flags)
+	return cmd
+}
+
+func TestCompleter(t *testing.T) {
+	c := Completer{Flags: newCompletionServer(t)}
+
+	testCases := map[string]struct {
+		complete ValidArgsFunc
+		flags    []string
+		args     []string
+		prefix   string
+		want     []string
+	}{
+		"all package revisions": {
+			complete: c.PackageRevisionArgs(0),
+			want: []string{
+				"blueprints-basens-v1\tblueprints/basens v1 (Published)",
+				"blueprints-basens-v2\tblueprints/basens v2 (Published)",
+				"blueprints-empty-v1\tblueprints/empty v1 (Published)",
+				"deployments-app-draft\tdeployments/app ws (Draft)",
+			},
+		},
+		"package revisions by lifecycle and prefix": {
+			complete: c.PackageRevisionArgs(0, v1alpha1.PackageRevisionLifecyclePublished),
+			args:     []string{"blueprints-basens-v1"},
+			prefix:   "blueprints-b",
+			want:     []string{"blueprints-basens-v2\tblueprints/basens v2 (Published)"},
+		},
+		"package revision args exhausted": {
+			complete: c.PackageRevisionArgs(1),
+			args:     []string{"blueprints-basens-v1"},
+		},
+		"repositories": {
+			complete: c.RepositoryArgs(1),
+			want:     []string{"blueprints\tBlueprints", "deployments\tgit"},
+		},
+		"package names in repository": {
+			complete: c.PackageNames,
+			flags:    []string{"--repository=blueprints"},
+			want:     []string{"basens", "empty"},
+		},
+		"revisions of package": {
+			complete: c.Revisions,
+			flags:    []string{"--name=basens"},
+			want:     []string{"v1", "v2"},
+		},
+	}
+
+	for tn := range testCases {
+		tc := testCases[tn]
+		t.Run(tn, func(t *testing.T) {
+			got, directive := tc.complete(newCompletionCommand(tc.flags...), tc.args, tc.prefix)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+		})
+	}
+}
+
+func TestCompleterUnreachable(t *testing.T) {
+	server := "http://127.0.0.1:1"
+	flags := genericclioptions.NewConfigFlags(false)
+	flags.APIServer = &server
+	got, directive := Completer{Flags: flags}.PackageRevisionArgs(0)(newCompletionCommand(), nil, "")
+	assert.Empty(t, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+)
+
+// Annotations recording the provenance of a promoted package revision.
+const (
+	// PromotedFromAnnotation records the name of the package revision the
+	// package revision was promoted from.
+	PromotedFromAnnotation = "porch.kpt.dev/promoted-from"
+	// PromotedFromRepositoryAnnotation records the repository of the
+	// package revision the package revision was promoted from.
+	PromotedFromRepositoryAnnotation = "porch.kpt.dev/promoted-from-repository"
+	// PromotedFromPackageAnnotation records the package of the package
+	// revision the package revision was promoted from.
+	PromotedFromPackageAnnotation = "porch.kpt.dev/promoted-from-package"
+	// PromotedFromRevisionAnnotation records the revision of the package
+	// revision the package revision was promoted from.
+	PromotedFromRevisionAnnotation = "porch.kpt.dev/promoted-from-revision"
+)
+
+// SetPromotionProvenance records on the package revision that it was
+// promoted from the source package revision.
+func SetPromotionProvenance(pr, source *v1alpha1.PackageRevision) {
+	if pr.Annotations == nil {
+		pr.Annotations = map[string]string{}
+	}
+	pr.Annotations[PromotedFromAnnotation] = source.Name
+	pr.Annotations[PromotedFromRepositoryAnnotation] = source.Spec.RepositoryName
+	pr.Annotations[PromotedFromPackageAnnotation] = source.Spec.PackageName
+	pr.Annotations[PromotedFromRevisionAnnotation] = source.Spec.Revision
+}
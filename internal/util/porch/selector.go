@@ -0,0 +1,107 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Selector selects the package revisions a bulk operation applies to, as an
+// alternative to listing their names as arguments.
+type Selector struct {
+	// LabelSelector is a label selector, e.g. `app=foo,tier!=db`.
+	LabelSelector string
+	// Repository is the name of the repository of the package revisions.
+	Repository string
+	// Package is the name of the package of the package revisions.
+	Package string
+}
+
+// AddFlags adds the --selector, --repository and --name flags to the command.
+func (s *Selector) AddFlags(c *cobra.Command, flags *genericclioptions.ConfigFlags) {
+	c.Flags().StringVarP(&s.LabelSelector, "selector", "l", "",
+		"Label selector of the package revisions to operate on, e.g. app=foo.")
+	c.Flags().StringVar(&s.Repository, "repository", "",
+		"Operate on the package revisions in this repository.")
+	c.Flags().StringVar(&s.Package, "name", "",
+		"Operate on the revisions of the package with this name.")
+
+	completer := Completer{Flags: flags}
+	_ = c.RegisterFlagCompletionFunc("repository", completer.Repositories)
+	_ = c.RegisterFlagCompletionFunc("name", completer.PackageNames)
+}
+
+// IsEmpty returns true if no selection criteria are set.
+func (s *Selector) IsEmpty() bool {
+	return s.LabelSelector == "" && s.Repository == "" && s.Package == ""
+}
+
+// Select returns the names of the package revisions in the namespace that
+// match the selector and are in one of the lifecycles, sorted by name.
+func (s *Selector) Select(ctx context.Context, c client.Reader, namespace string, lifecycles ...v1alpha1.PackageRevisionLifecycle) ([]string, error) {
+	opts := []client.ListOption{client.InNamespace(namespace)}
+	if s.LabelSelector != "" {
+		selector, err := labels.Parse(s.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", s.LabelSelector, err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	var prs v1alpha1.PackageRevisionList
+	if err := c.List(ctx, &prs, opts...); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, pr := range prs.Items {
+		if s.Repository != "" && pr.Spec.RepositoryName != s.Repository {
+			continue
+		}
+		if s.Package != "" && pr.Spec.PackageName != s.Package {
+			continue
+		}
+		if len(lifecycles) > 0 && !hasLifecycle(pr.Spec.Lifecycle, lifecycles) {
+			continue
+		}
+		names = append(names, pr.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// BulkResult reports the outcome of an operation on many package revisions.
+type BulkResult struct {
+	Succeeded []string
+	Failed    []string
+}
+
+// Summary returns a one line summary of the operation, e.g.
+// `3 of 4 package revisions proposed, 1 failed`.
+func (r *BulkResult) Summary(verb string) string {
+	total := len(r.Succeeded) + len(r.Failed)
+	summary := fmt.Sprintf("%d of %d package revisions %s", len(r.Succeeded), total, verb)
+	if len(r.Failed) > 0 {
+		summary += fmt.Sprintf(", %d failed", len(r.Failed))
+	}
+	return summary
+}
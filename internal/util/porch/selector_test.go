@@ -0,0 +1,96 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSelector(t *testing.T) {
+	pr := func(name, repo, pkg string, lifecycle v1alpha1.PackageRevisionLifecycle, labels map[string]string) client.Object {
+		return &v1alpha1.PackageRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: labels},
+			Spec: v1alpha1.PackageRevisionSpec{
+				RepositoryName: repo,
+				PackageName:    pkg,
+				Lifecycle:      lifecycle,
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pr("deployments-app-east", "deployments", "app-east", v1alpha1.PackageRevisionLifecycleDraft, map[string]string{"app": "app"}),
+		pr("deployments-app-west", "deployments", "app-west", v1alpha1.PackageRevisionLifecycleDraft, map[string]string{"app": "app"}),
+		pr("deployments-app-north", "deployments", "app-north", v1alpha1.PackageRevisionLifecyclePublished, map[string]string{"app": "app"}),
+		pr("deployments-db", "deployments", "db", v1alpha1.PackageRevisionLifecycleDraft, nil),
+		pr("staging-app-east", "staging", "app-east", v1alpha1.PackageRevisionLifecycleDraft, map[string]string{"app": "app"}),
+	).Build()
+
+	testCases := map[string]struct {
+		selector   Selector
+		lifecycles []v1alpha1.PackageRevisionLifecycle
+		expected   []string
+		err        string
+	}{
+		"label selector": {
+			selector:   Selector{LabelSelector: "app=app"},
+			lifecycles: []v1alpha1.PackageRevisionLifecycle{v1alpha1.PackageRevisionLifecycleDraft},
+			expected:   []string{"deployments-app-east", "deployments-app-west", "staging-app-east"},
+		},
+		"repository": {
+			selector: Selector{Repository: "deployments"},
+			expected: []string{"deployments-app-east", "deployments-app-north", "deployments-app-west", "deployments-db"},
+		},
+		"package and label selector": {
+			selector: Selector{LabelSelector: "app", Package: "app-east"},
+			expected: []string{"deployments-app-east", "staging-app-east"},
+		},
+		"no match": {
+			selector:   Selector{Repository: "staging"},
+			lifecycles: []v1alpha1.PackageRevisionLifecycle{v1alpha1.PackageRevisionLifecyclePublished},
+		},
+		"invalid label selector": {
+			selector: Selector{LabelSelector: "app in"},
+			err:      `invalid selector "app in"`,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			names, err := tc.selector.Select(context.Background(), c, "ns", tc.lifecycles...)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestBulkResultSummary(t *testing.T) {
+	r := BulkResult{Succeeded: []string{"a", "b"}}
+	assert.Equal(t, "2 of 2 package revisions approved", r.Summary("approved"))
+	r.Failed = []string{"c"}
+	assert.Equal(t, "2 of 3 package revisions approved, 1 failed", r.Summary("approved"))
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package push contains libraries for publishing a local package as an
+// image in an OCI registry, in the format read by fetch.OciCloner.
+package push
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/google/go-containerregistry/pkg/gcrane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// OciPusher pushes a package as an image in an OCI registry. The files of
+// the package are stored in a single image layer.
+type OciPusher struct {
+	// Image is the image to push, referenced by tag or digest.
+	Image string
+
+	// LockedImage is the pushed image pinned to the digest of its manifest.
+	LockedImage string
+
+	// options are passed to the registry client. Credentials are looked up
+	// in the docker config unless overridden.
+	options []remote.Option
+}
+
+func NewOciPusher(image string, opts ...remote.Option) *OciPusher {
+	return &OciPusher{
+		Image:   image,
+		options: opts,
+	}
+}
+
+// Push pushes the content of dir to the registry, and records the digest of
+// the pushed image in LockedImage.
+func (p *OciPusher) Push(ctx context.Context, dir string) error {
+	const op errors.Op = "push.OciPusher.Push"
+
+	ref, err := name.ParseReference(p.Image)
+	if err != nil {
+		return errors.E(op, errors.InvalidParam, fmt.Errorf("cannot parse image %q: %w", p.Image, err))
+	}
+
+	b, err := tarDir(dir)
+	if err != nil {
+		return errors.E(op, fmt.Errorf("error creating layer from %q: %w", dir, err))
+	}
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	})
+	if err != nil {
+		return errors.E(op, errors.Internal, err)
+	}
+	image, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		return errors.E(op, errors.Internal, err)
+	}
+
+	options := append([]remote.Option{
+		remote.WithAuthFromKeychain(gcrane.Keychain),
+		remote.WithContext(ctx),
+	}, p.options...)
+	if err := remote.Write(ref, image, options...); err != nil {
+		return errors.E(op, fmt.Errorf("error pushing image %q: %w", p.Image, err))
+	}
+	digest, err := image.Digest()
+	if err != nil {
+		return errors.E(op, fmt.Errorf("error getting digest of image %q: %w", p.Image, err))
+	}
+	p.LockedImage = ref.Context().Digest(digest.String()).String()
+	return nil
+}
+
+// tarDir writes the directories and regular files in dir to a tar stream,
+// with paths relative to dir. Other files, like symlinks, are skipped as
+// they are when the image is extracted.
+func tarDir(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -15,12 +15,14 @@
 package render
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/GoogleContainerTools/kpt/internal/errors"
 	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
@@ -41,6 +43,10 @@ import (
 
 var errAllowedExecNotSpecified = fmt.Errorf("must run with `--allow-exec` option to allow running function binaries")
 
+func errClusterAccessNotAllowed(function *kptfilev1.Function) error {
+	return fmt.Errorf("function %q requires cluster access: must run with `--allow-cluster-access` option to allow it", function.Image+function.Exec)
+}
+
 // Renderer hydrates a given pkg by running the functions in the input pipeline
 type Renderer struct {
 	// PkgPath is the absolute path to the root package
@@ -63,6 +69,11 @@ type Renderer struct {
 
 	// FileSystem is the input filesystem to operate on
 	FileSystem filesys.FileSystem
+
+	// Parallel is the maximum number of package pipelines run concurrently.
+	// Sibling subpackages are independent, so they are hydrated in parallel
+	// if Parallel is greater than 1.
+	Parallel int
 }
 
 // Execute runs a pipeline.
@@ -80,18 +91,20 @@ func (e *Renderer) Execute(ctx context.Context) (*fnresult.ResultList, error) {
 	hctx := &hydrationContext{
 		root:          root,
 		pkgs:          map[types.UniquePath]*pkgNode{},
-		fnResults:     fnresult.NewResultList(),
 		runnerOptions: e.RunnerOptions,
 		fileSystem:    e.FileSystem,
 		runtime:       e.Runtime,
 	}
+	if e.Parallel > 1 {
+		hctx.pipelines = make(chan struct{}, e.Parallel)
+	}
 
 	if _, err = hydrate(ctx, root, hctx); err != nil {
 		// Note(droot): ignore the error in function result saving
 		// to avoid masking the hydration error.
 		// don't disable the CLI output in case of error
-		_ = e.saveFnResults(ctx, hctx.fnResults)
-		return hctx.fnResults, errors.E(op, root.pkg.UniquePath, err)
+		_ = e.saveFnResults(ctx, root.fnResults)
+		return root.fnResults, errors.E(op, root.pkg.UniquePath, err)
 	}
 
 	// adjust the relative paths of the resources.
@@ -144,7 +157,7 @@ func (e *Renderer) Execute(ctx context.Context) (*fnresult.ResultList, error) {
 		}
 	}
 
-	return hctx.fnResults, e.saveFnResults(ctx, hctx.fnResults)
+	return root.fnResults, e.saveFnResults(ctx, root.fnResults)
 }
 
 func (e *Renderer) saveFnResults(ctx context.Context, fnResults *fnresult.ResultList) error {
@@ -181,9 +194,13 @@ type hydrationContext struct {
 	// executedFunctionCnt is the counter for functions that have been executed.
 	executedFunctionCnt int
 
-	// fnResults stores function results gathered
-	// during pipeline execution.
-	fnResults *fnresult.ResultList
+	// mu guards pkgs, inputFiles and executedFunctionCnt when subpackages
+	// are hydrated concurrently.
+	mu sync.Mutex
+
+	// pipelines limits the number of pipelines running concurrently. It is
+	// nil if subpackages are hydrated sequentially.
+	pipelines chan struct{}
 
 	runnerOptions fnruntime.RunnerOptions
 
@@ -193,6 +210,13 @@ type hydrationContext struct {
 	runtime fn.FunctionRuntime
 }
 
+// functionExecuted increments the counter for executed functions.
+func (hctx *hydrationContext) functionExecuted() {
+	hctx.mu.Lock()
+	defer hctx.mu.Unlock()
+	hctx.executedFunctionCnt++
+}
+
 // pkgNode represents a package being hydrated. Think of it as a node in the hydration DAG.
 type pkgNode struct {
 	pkg *pkg.Pkg
@@ -203,6 +227,11 @@ type pkgNode struct {
 	// KRM resources that we have gathered post hydration for this package.
 	// These inludes resources at this pkg as well all it's children.
 	resources []*yaml.RNode
+
+	// fnResults stores the results of the functions run for this package
+	// and all it's children, in the order the functions were run in
+	// sequential hydration.
+	fnResults *fnresult.ResultList
 }
 
 // newPkgNode returns a pkgNode instance given a path or pkg.
@@ -231,8 +260,9 @@ func newPkgNode(fsys filesys.FileSystem, path string, p *pkg.Pkg) (pn *pkgNode,
 	}
 
 	pn = &pkgNode{
-		pkg:   p,
-		state: Dry, // package starts in dry state
+		pkg:       p,
+		state:     Dry, // package starts in dry state
+		fnResults: fnresult.NewResultList(),
 	}
 	return pn, nil
 }
@@ -255,7 +285,13 @@ func (s hydrationState) String() string {
 func hydrate(ctx context.Context, pn *pkgNode, hctx *hydrationContext) (output []*yaml.RNode, err error) {
 	const op errors.Op = "pkg.render"
 
+	hctx.mu.Lock()
 	curr, found := hctx.pkgs[pn.pkg.UniquePath]
+	if !found {
+		// add it to the discovered package list
+		hctx.pkgs[pn.pkg.UniquePath] = pn
+	}
+	hctx.mu.Unlock()
 	if found {
 		switch curr.state {
 		case Hydrating:
@@ -270,8 +306,6 @@ func hydrate(ctx context.Context, pn *pkgNode, hctx *hydrationContext) (output [
 				fmt.Errorf("package found in invalid state %v", curr.state))
 		}
 	}
-	// add it to the discovered package list
-	hctx.pkgs[pn.pkg.UniquePath] = pn
 	curr = pn
 	// mark the pkg in hydrating
 	curr.state = Hydrating
@@ -289,20 +323,9 @@ func hydrate(ctx context.Context, pn *pkgNode, hctx *hydrationContext) (output [
 		return output, errors.E(op, curr.pkg.UniquePath, err)
 	}
 	// hydrate recursively and gather hydated transitive resources.
-	for _, subpkg := range subpkgs {
-		var transitiveResources []*yaml.RNode
-		var subPkgNode *pkgNode
-
-		if subPkgNode, err = newPkgNode(hctx.fileSystem, "", subpkg); err != nil {
-			return output, errors.E(op, subpkg.UniquePath, err)
-		}
-
-		transitiveResources, err = hydrate(ctx, subPkgNode, hctx)
-		if err != nil {
-			return output, errors.E(op, subpkg.UniquePath, err)
-		}
-
-		input = append(input, transitiveResources...)
+	input, err = hydrateSubpackages(ctx, curr, subpkgs, hctx)
+	if err != nil {
+		return output, err
 	}
 
 	// gather resources present at the current package
@@ -331,6 +354,88 @@ func hydrate(ctx context.Context, pn *pkgNode, hctx *hydrationContext) (output [
 	return output, err
 }
 
+// hydratedSubpackage is the outcome of hydrating a subpackage concurrently
+// with its siblings.
+type hydratedSubpackage struct {
+	node      *pkgNode
+	resources []*yaml.RNode
+	err       error
+
+	// outStream and errStream buffer the output of the hydration, so the
+	// output of concurrent pipelines is not interleaved.
+	outStream bytes.Buffer
+	errStream bytes.Buffer
+}
+
+// hydrateSubpackages hydrates the given direct subpackages of pn and returns
+// their wet resources. The results of the functions run for the subpackages
+// are added to the results of pn.
+// If pipelines can run concurrently, the subpackages are hydrated in
+// parallel, and their resources, results and output are gathered in the
+// order of subpkgs, so they are the same as in sequential hydration.
+func hydrateSubpackages(ctx context.Context, pn *pkgNode, subpkgs []*pkg.Pkg, hctx *hydrationContext) ([]*yaml.RNode, error) {
+	const op errors.Op = "pkg.render"
+
+	var resources []*yaml.RNode
+	if hctx.pipelines == nil || len(subpkgs) < 2 {
+		for _, subpkg := range subpkgs {
+			subPkgNode, err := newPkgNode(hctx.fileSystem, "", subpkg)
+			if err != nil {
+				return resources, errors.E(op, subpkg.UniquePath, err)
+			}
+
+			transitiveResources, err := hydrate(ctx, subPkgNode, hctx)
+			pn.addResults(subPkgNode.fnResults)
+			if err != nil {
+				return resources, errors.E(op, subpkg.UniquePath, err)
+			}
+
+			resources = append(resources, transitiveResources...)
+		}
+		return resources, nil
+	}
+
+	hydrated := make([]hydratedSubpackage, len(subpkgs))
+	var wg sync.WaitGroup
+	for i := range subpkgs {
+		wg.Add(1)
+		go func(subpkg *pkg.Pkg, h *hydratedSubpackage) {
+			defer wg.Done()
+			if h.node, h.err = newPkgNode(hctx.fileSystem, "", subpkg); h.err != nil {
+				return
+			}
+			subCtx := printer.WithContext(ctx, printer.New(&h.outStream, &h.errStream))
+			h.resources, h.err = hydrate(subCtx, h.node, hctx)
+		}(subpkgs[i], &hydrated[i])
+	}
+	wg.Wait()
+
+	pr := printer.FromContextOrDie(ctx)
+	for i := range hydrated {
+		h := &hydrated[i]
+		_, _ = pr.OutStream().Write(h.outStream.Bytes())
+		_, _ = pr.ErrStream().Write(h.errStream.Bytes())
+		if h.node != nil {
+			pn.addResults(h.node.fnResults)
+		}
+		// like in sequential hydration, the first failure is reported and
+		// the later subpackages are ignored.
+		if h.err != nil {
+			return resources, errors.E(op, subpkgs[i].UniquePath, h.err)
+		}
+		resources = append(resources, h.resources...)
+	}
+	return resources, nil
+}
+
+// addResults adds the given function results to the results of the package.
+func (pn *pkgNode) addResults(results *fnresult.ResultList) {
+	pn.fnResults.Items = append(pn.fnResults.Items, results.Items...)
+	if results.ExitCode != 0 {
+		pn.fnResults.ExitCode = results.ExitCode
+	}
+}
+
 // runPipeline runs the pipeline defined at current pkgNode on given input resources.
 func (pn *pkgNode) runPipeline(ctx context.Context, hctx *hydrationContext, input []*yaml.RNode) ([]*yaml.RNode, error) {
 	const op errors.Op = "pipeline.run"
@@ -357,6 +462,12 @@ func (pn *pkgNode) runPipeline(ctx context.Context, hctx *hydrationContext, inpu
 		return nil, err
 	}
 
+	if hctx.pipelines != nil {
+		// wait until less than the maximum number of pipelines are running
+		hctx.pipelines <- struct{}{}
+		defer func() { <-hctx.pipelines }()
+	}
+
 	mutatedResources, err := pn.runMutators(ctx, hctx, input)
 	if err != nil {
 		return nil, errors.E(op, pn.pkg.UniquePath, err)
@@ -381,12 +492,16 @@ func (pn *pkgNode) runMutators(ctx context.Context, hctx *hydrationContext, inpu
 		return input, nil
 	}
 
-	mutators, err := fnChain(ctx, hctx, pn.pkg.UniquePath, pl.Mutators)
+	mutators, err := fnChain(ctx, hctx, pn.pkg.UniquePath, pn.fnResults, pl.Mutators)
 	if err != nil {
 		return nil, err
 	}
 
 	for i, mutator := range mutators {
+		if !fnruntime.ConditionMet(input, pl.Mutators[i].When) {
+			printSkipped(ctx, &pl.Mutators[i])
+			continue
+		}
 		if pl.Mutators[i].ConfigPath != "" {
 			// kpt v1.0.0-beta15+ onwards, functionConfigs are included in the
 			// function inputs during `render` and as a result, they can be
@@ -438,7 +553,7 @@ func (pn *pkgNode) runMutators(ctx context.Context, hctx *hydrationContext, inpu
 		if err != nil {
 			return nil, err
 		}
-		hctx.executedFunctionCnt++
+		hctx.functionExecuted()
 
 		if len(selectors) > 0 || len(exclusions) > 0 {
 			// merge the output resources with input resources
@@ -471,6 +586,10 @@ func (pn *pkgNode) runValidators(ctx context.Context, hctx *hydrationContext, in
 
 	for i := range pl.Validators {
 		function := pl.Validators[i]
+		if !fnruntime.ConditionMet(input, function.When) {
+			printSkipped(ctx, &function)
+			continue
+		}
 		// validators are run on a copy of mutated resources to ensure
 		// resources are not mutated.
 		selectedResources, err := fnruntime.SelectInput(input, function.Selectors, function.Exclusions, &fnruntime.SelectionContext{RootPackagePath: hctx.root.pkg.UniquePath})
@@ -485,21 +604,34 @@ func (pn *pkgNode) runValidators(ctx context.Context, hctx *hydrationContext, in
 		if function.Exec != "" && !hctx.runnerOptions.AllowExec {
 			return errAllowedExecNotSpecified
 		}
+		if function.ClusterAccess && hctx.runnerOptions.ClusterKubeconfig == "" {
+			return errClusterAccessNotAllowed(&function)
+		}
 		opts := hctx.runnerOptions
 		opts.SetPkgPathAnnotation = true
 		opts.DisplayResourceCount = displayResourceCount
-		validator, err = fnruntime.NewRunner(ctx, hctx.fileSystem, &function, pn.pkg.UniquePath, hctx.fnResults, opts, hctx.runtime)
+		validator, err = fnruntime.NewRunner(ctx, hctx.fileSystem, &function, pn.pkg.UniquePath, pn.fnResults, opts, hctx.runtime)
 		if err != nil {
 			return err
 		}
 		if _, err = validator.Filter(cloneResources(selectedResources)); err != nil {
 			return err
 		}
-		hctx.executedFunctionCnt++
+		hctx.functionExecuted()
 	}
 	return nil
 }
 
+// printSkipped prints that the function was not run because its condition is
+// not met.
+func printSkipped(ctx context.Context, function *kptfilev1.Function) {
+	name := function.Image
+	if name == "" {
+		name = function.Exec
+	}
+	printer.FromContextOrDie(ctx).Printf("[SKIPPED] %q: condition not met\n", name)
+}
+
 func cloneResources(input []*yaml.RNode) (output []*yaml.RNode) {
 	for _, resource := range input {
 		output = append(output, resource.Copy())
@@ -582,7 +714,7 @@ func pathRelToRoot(rootPkgPath, subPkgPath, resourcePath string) (relativePath s
 }
 
 // fnChain returns a slice of function runners given a list of functions defined in pipeline.
-func fnChain(ctx context.Context, hctx *hydrationContext, pkgPath types.UniquePath, fns []kptfilev1.Function) ([]*fnruntime.FunctionRunner, error) {
+func fnChain(ctx context.Context, hctx *hydrationContext, pkgPath types.UniquePath, fnResults *fnresult.ResultList, fns []kptfilev1.Function) ([]*fnruntime.FunctionRunner, error) {
 	var runners []*fnruntime.FunctionRunner
 	for i := range fns {
 		var err error
@@ -595,10 +727,13 @@ func fnChain(ctx context.Context, hctx *hydrationContext, pkgPath types.UniquePa
 		if function.Exec != "" && !hctx.runnerOptions.AllowExec {
 			return nil, errAllowedExecNotSpecified
 		}
+		if function.ClusterAccess && hctx.runnerOptions.ClusterKubeconfig == "" {
+			return nil, errClusterAccessNotAllowed(&function)
+		}
 		opts := hctx.runnerOptions
 		opts.SetPkgPathAnnotation = true
 		opts.DisplayResourceCount = displayResourceCount
-		runner, err = fnruntime.NewRunner(ctx, hctx.fileSystem, &function, pkgPath, hctx.fnResults, opts, hctx.runtime)
+		runner, err = fnruntime.NewRunner(ctx, hctx.fileSystem, &function, pkgPath, fnResults, opts, hctx.runtime)
 		if err != nil {
 			return nil, err
 		}
@@ -609,6 +744,8 @@ func fnChain(ctx context.Context, hctx *hydrationContext, pkgPath types.UniquePa
 
 // trackInputFiles records file paths of input resources in the hydration context.
 func trackInputFiles(hctx *hydrationContext, relPath string, input []*yaml.RNode) error {
+	hctx.mu.Lock()
+	defer hctx.mu.Unlock()
 	if hctx.inputFiles == nil {
 		hctx.inputFiles = sets.String{}
 	}
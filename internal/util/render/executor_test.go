@@ -15,11 +15,22 @@
 package render
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/GoogleContainerTools/kpt/internal/fnruntime"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/GoogleContainerTools/kpt/pkg/fn"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 )
 
@@ -227,3 +238,127 @@ metadata:
 		})
 	}
 }
+
+// sleepRuntime runs functions which return their input after sleeping for
+// the duration in the image name, and tracks how many run concurrently.
+type sleepRuntime struct {
+	mu             sync.Mutex
+	running        int
+	maxConcurrency int
+
+	// barrier, if set, holds the first functions back until that many run
+	// concurrently, so that parallel rendering is observed regardless of
+	// scheduling.
+	barrier  int
+	release  sync.Once
+	released chan struct{}
+}
+
+func newSleepRuntime(barrier int) *sleepRuntime {
+	return &sleepRuntime{barrier: barrier, released: make(chan struct{})}
+}
+
+func (s *sleepRuntime) GetRunner(_ context.Context, f *kptfilev1.Function) (fn.FunctionRunner, error) {
+	d, err := time.ParseDuration(filepath.Base(f.Image))
+	if err != nil {
+		return nil, err
+	}
+	return &sleepRunner{runtime: s, duration: d}, nil
+}
+
+type sleepRunner struct {
+	runtime  *sleepRuntime
+	duration time.Duration
+}
+
+func (r *sleepRunner) Run(in io.Reader, out io.Writer) error {
+	s := r.runtime
+	s.mu.Lock()
+	s.running++
+	if s.running > s.maxConcurrency {
+		s.maxConcurrency = s.running
+	}
+	if s.barrier > 0 && s.running == s.barrier {
+		s.release.Do(func() { close(s.released) })
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+	}()
+	if s.barrier > 0 {
+		select {
+		case <-s.released:
+		case <-time.After(10 * time.Second):
+			return fmt.Errorf("%d functions did not run concurrently", s.barrier)
+		}
+	}
+	time.Sleep(r.duration)
+	_, err := io.Copy(out, in)
+	return err
+}
+
+func TestRendererParallel(t *testing.T) {
+	// earlier subpackages take longer to render, so they complete last when
+	// rendered in parallel.
+	pkgs := map[string]string{
+		"/pkg":   "10ms",
+		"/pkg/a": "40ms",
+		"/pkg/b": "20ms",
+		"/pkg/c": "1ms",
+	}
+	render := func(parallel int, runtime *sleepRuntime) (*sleepRuntime, []string, string) {
+		fs := filesys.MakeFsInMemory()
+		for dir, sleep := range pkgs {
+			assert.NoError(t, fs.MkdirAll(dir))
+			assert.NoError(t, fs.WriteFile(filepath.Join(dir, kptfilev1.KptFileName), []byte(fmt.Sprintf(`apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: %s
+pipeline:
+  mutators:
+    - image: example.com/sleep/%s
+`, filepath.Base(dir), sleep))))
+			assert.NoError(t, fs.WriteFile(filepath.Join(dir, "cm.yaml"), []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+`, filepath.Base(dir)))))
+		}
+
+		stderr := &bytes.Buffer{}
+		ctx := printer.WithContext(context.Background(), printer.New(nil, stderr))
+		r := Renderer{
+			PkgPath:    "/pkg",
+			Runtime:    runtime,
+			Output:     &bytes.Buffer{},
+			FileSystem: fs,
+			Parallel:   parallel,
+			RunnerOptions: fnruntime.RunnerOptions{
+				ResolveToImage: func(_ context.Context, image string) (string, error) { return image, nil },
+			},
+		}
+		results, err := r.Execute(ctx)
+		assert.NoError(t, err)
+		var images []string
+		for _, item := range results.Items {
+			images = append(images, item.Image)
+		}
+		// the durations of the functions are not stable.
+		output := regexp.MustCompile(`in .*s\n`).ReplaceAllString(stderr.String(), "in 0s\n")
+		return runtime, images, output
+	}
+
+	sequential, seqImages, seqOutput := render(1, newSleepRuntime(0))
+	assert.Equal(t, 1, sequential.maxConcurrency)
+	assert.Equal(t, []string{"example.com/sleep/40ms", "example.com/sleep/20ms", "example.com/sleep/1ms", "example.com/sleep/10ms"}, seqImages)
+
+	// the barrier makes sure two sibling subpackages are rendered at the
+	// same time, but never more.
+	parallel, images, output := render(2, newSleepRuntime(2))
+	assert.Equal(t, 2, parallel.maxConcurrency)
+	// results and output are in the same order as in sequential rendering.
+	assert.Equal(t, seqImages, images)
+	assert.Equal(t, seqOutput, output)
+}
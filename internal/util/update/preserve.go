@@ -0,0 +1,284 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package update
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/GoogleContainerTools/kpt/internal/errors"
+	"github.com/GoogleContainerTools/kpt/internal/types"
+	"github.com/GoogleContainerTools/kpt/internal/util/pkgutil"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"sigs.k8s.io/kustomize/kyaml/copyutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/sets"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// LocallyOwnedAnnotation marks a resource as owned by the local package. The
+// preserve-local-changes strategy never changes files holding a resource
+// with the annotation set to "true".
+const LocallyOwnedAnnotation = "kpt.dev/locally-owned"
+
+// PreserveLocalChangesUpdater updates a package by replaying the local
+// changes to each file on top of the updated upstream version of the file,
+// like a git rebase. Unlike the resource-merge strategy, the changes are
+// merged line by line, so the local layout and comments of the files are
+// kept. Local and upstream changes to the same lines are written between
+// conflict markers.
+type PreserveLocalChangesUpdater struct{}
+
+func (u PreserveLocalChangesUpdater) Update(options Options) error {
+	const op errors.Op = "update.Update"
+	if err := (ResourceMergeUpdater{preserveLocalChanges: true, keptLocalFiles: options.KeptLocalFiles}).Update(options); err != nil {
+		return errors.E(op, types.UniquePath(options.LocalPath), err)
+	}
+	return nil
+}
+
+// replayLocalChanges updates the files of the package in localDir, except
+// the Kptfile, with the changes between originalDir and updatedDir. Binary
+// files changed both locally and in upstream can't be merged, so their local
+// version is kept and their path is added to kept if set.
+//
+//nolint:gocyclo
+func replayLocalChanges(localDir, updatedDir, originalDir string, kept *[]string) error {
+	const op errors.Op = "update.replayLocalChanges"
+	files := sets.String{}
+	for _, dir := range []string{localDir, updatedDir, originalDir} {
+		dirFiles, err := packageFiles(dir)
+		if err != nil {
+			return errors.E(op, types.UniquePath(localDir), err)
+		}
+		files.Insert(dirFiles.List()...)
+	}
+
+	for _, file := range files.List() {
+		localFile := filepath.Join(localDir, file)
+		local, inLocal, err := readFileIfExists(localFile)
+		if err != nil {
+			return errors.E(op, types.UniquePath(localDir), err)
+		}
+		updated, inUpdated, err := readFileIfExists(filepath.Join(updatedDir, file))
+		if err != nil {
+			return errors.E(op, types.UniquePath(localDir), err)
+		}
+		original, inOriginal, err := readFileIfExists(filepath.Join(originalDir, file))
+		if err != nil {
+			return errors.E(op, types.UniquePath(localDir), err)
+		}
+		isKrm, err := isKrmFile(file)
+		if err != nil {
+			return errors.E(op, types.UniquePath(localDir), err)
+		}
+
+		switch {
+		// File not changed in upstream, so the local version is kept.
+		case inOriginal == inUpdated && bytes.Equal(original, updated):
+			continue
+		// File not changed locally, so the upstream version is taken.
+		case inOriginal == inLocal && bytes.Equal(original, local):
+			if !inUpdated {
+				if err := os.Remove(localFile); err != nil {
+					return errors.E(op, errors.IO, types.UniquePath(localDir), err)
+				}
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(localFile), 0700); err != nil {
+				return errors.E(op, errors.IO, types.UniquePath(localDir), err)
+			}
+			if err := copyutil.SyncFile(filepath.Join(updatedDir, file), localFile); err != nil {
+				return errors.E(op, errors.IO, types.UniquePath(localDir), err)
+			}
+		// Same change locally and in upstream.
+		case inLocal == inUpdated && bytes.Equal(local, updated):
+			continue
+		// File deleted locally, we assume the user knows what they are doing.
+		case !inLocal:
+			continue
+		case isKrm && isLocallyOwned(local):
+			continue
+		// File changed locally and deleted from upstream. The local version
+		// is kept and recorded as locally owned, so it is left alone by
+		// future updates.
+		case !inUpdated:
+			if isKrm {
+				if err := markLocallyOwned(localFile); err != nil {
+					return errors.E(op, types.UniquePath(localDir), err)
+				}
+			}
+		case isBinary(local) || isBinary(original) || isBinary(updated):
+			if kept != nil {
+				*kept = append(*kept, localFile)
+			}
+		default:
+			merged, err := mergeFile(local, original, updated)
+			if err != nil {
+				return errors.E(op, types.UniquePath(localDir), fmt.Errorf("failed to merge %q: %w", file, err))
+			}
+			info, err := os.Stat(localFile)
+			if err != nil {
+				return errors.E(op, errors.IO, types.UniquePath(localDir), err)
+			}
+			if err := os.WriteFile(localFile, merged, info.Mode()); err != nil {
+				return errors.E(op, errors.IO, types.UniquePath(localDir), err)
+			}
+		}
+	}
+	return nil
+}
+
+// packageFiles returns the files of the package in root, without the Kptfile
+// and the files of subpackages. The paths are relative to root.
+func packageFiles(root string) (sets.String, error) {
+	const op errors.Op = "update.packageFiles"
+	files := sets.String{}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return files, nil
+	}
+	err := pkgutil.WalkPackage(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.E(op, errors.IO, err)
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		if rel != kptfilev1.KptFileName {
+			files.Insert(rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return files, nil
+}
+
+// readFileIfExists returns the content of the file and whether it exists.
+func readFileIfExists(path string) ([]byte, bool, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.E(errors.IO, err)
+	}
+	return b, true, nil
+}
+
+// isLocallyOwned returns true if any of the resources in content has the
+// LocallyOwnedAnnotation.
+func isLocallyOwned(content []byte) bool {
+	nodes, err := (&kio.ByteReader{Reader: bytes.NewReader(content)}).Read()
+	if err != nil {
+		return false
+	}
+	for _, n := range nodes {
+		if n.GetAnnotations()[LocallyOwnedAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// markLocallyOwned sets the LocallyOwnedAnnotation on the resources in the
+// file.
+func markLocallyOwned(path string) error {
+	const op errors.Op = "update.markLocallyOwned"
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.E(op, errors.IO, err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return errors.E(op, errors.IO, err)
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:            bytes.NewReader(b),
+		PreserveSeqIndent: true,
+		WrapBareSeqNode:   true,
+	}).Read()
+	if err != nil {
+		return errors.E(op, fmt.Errorf("failed to read %q: %w", path, err))
+	}
+	for _, n := range nodes {
+		if err := n.PipeE(yaml.SetAnnotation(LocallyOwnedAnnotation, "true")); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	var out bytes.Buffer
+	if err := (kio.ByteWriter{Writer: &out}).Write(nodes); err != nil {
+		return errors.E(op, err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), info.Mode()); err != nil {
+		return errors.E(op, errors.IO, err)
+	}
+	return nil
+}
+
+// isBinary returns true if content looks like a binary file, using the same
+// heuristic as git.
+func isBinary(content []byte) bool {
+	const sniffLen = 8000
+	return bytes.IndexByte(content[:min(len(content), sniffLen)], 0) >= 0
+}
+
+// mergeFile merges the changes from original to updated into local with
+// `git merge-file`. Conflicting changes are written between conflict markers
+// matching the ones written by the resource-merge strategy, so the conflicts
+// can be resolved with `kpt pkg resolve`.
+func mergeFile(local, original, updated []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "kpt-merge-file-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var paths []string
+	for i, b := range [][]byte{local, original, updated} {
+		p := filepath.Join(dir, strconv.Itoa(i))
+		if err := os.WriteFile(p, b, 0600); err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"-c", "merge.conflictStyle=merge", "merge-file", "-p",
+		"-L", "local", "-L", "origin", "-L", "upstream"}, paths...)
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+	// git merge-file exits with the number of conflicts, or a negative
+	// value on errors.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return stdout.Bytes(), nil
+	}
+	return nil, fmt.Errorf("%w: %s", err, stderr.String())
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package update_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/GoogleContainerTools/kpt/internal/util/update"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+const preserveKptfile = `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: app
+upstream:
+  type: git
+  git:
+    repo: github.com/GoogleContainerTools/kpt
+    directory: /
+    ref: main
+  updateStrategy: preserve-local-changes
+`
+
+const preserveDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+`
+
+func TestUpdate_PreserveLocalChanges(t *testing.T) {
+	testCases := map[string]struct {
+		original map[string]string
+		updated  map[string]string
+		local    map[string]string
+		expected map[string]string
+		kept     []string
+	}{
+		"replays local changes on upstream": {
+			original: map[string]string{"deployment.yaml": preserveDeployment},
+			updated: map[string]string{
+				"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+`,
+			},
+			local: map[string]string{
+				"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  # scaled for production
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+`,
+			},
+			expected: map[string]string{
+				"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  # scaled for production
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+`,
+			},
+		},
+		"conflicting changes": {
+			original: map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: b\n"},
+			updated:  map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: upstream\n"},
+			local:    map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: local\n"},
+			expected: map[string]string{
+				"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n" +
+					"<<<<<<< local\n  a: local\n=======\n  a: upstream\n>>>>>>> upstream\n",
+			},
+		},
+		"locally owned file": {
+			original: map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: b\n"},
+			updated:  map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: upstream\n"},
+			local: map[string]string{
+				"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n  annotations:\n    kpt.dev/locally-owned: \"true\"\ndata:\n  a: b\n",
+			},
+			expected: map[string]string{
+				"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n  annotations:\n    kpt.dev/locally-owned: \"true\"\ndata:\n  a: b\n",
+			},
+		},
+		"modified locally and deleted from upstream": {
+			original: map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: b\n"},
+			updated:  map[string]string{},
+			local:    map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  a: local\n"},
+			expected: map[string]string{
+				"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n  annotations:\n    kpt.dev/locally-owned: 'true'\ndata:\n  a: local\n",
+			},
+		},
+		"unchanged files follow upstream": {
+			original: map[string]string{"README.md": "v1\n", "old.yaml": preserveDeployment},
+			updated:  map[string]string{"README.md": "v2\n", "new/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n"},
+			local:    map[string]string{"README.md": "v1\n", "old.yaml": preserveDeployment, "local.yaml": preserveDeployment},
+			expected: map[string]string{
+				"README.md":   "v2\n",
+				"old.yaml":    "",
+				"new/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+				"local.yaml":  preserveDeployment,
+			},
+		},
+		"conflicting changes to non KRM files": {
+			original: map[string]string{"README.md": "v1\n"},
+			updated:  map[string]string{"README.md": "v2\n"},
+			local:    map[string]string{"README.md": "local\n"},
+			expected: map[string]string{"README.md": "<<<<<<< local\nlocal\n=======\nv2\n>>>>>>> upstream\n"},
+		},
+		"conflicting changes to binary files keep local": {
+			original: map[string]string{"logo.png": "v1\x00"},
+			updated:  map[string]string{"logo.png": "v2\x00"},
+			local:    map[string]string{"logo.png": "local\x00"},
+			expected: map[string]string{"logo.png": "local\x00"},
+			kept:     []string{"logo.png"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			dirs := map[string]string{}
+			for dir, files := range map[string]map[string]string{
+				"original": tc.original,
+				"updated":  tc.updated,
+				"local":    tc.local,
+			} {
+				dirs[dir] = t.TempDir()
+				files[kptfilev1.KptFileName] = preserveKptfile
+				for f, content := range files {
+					path := filepath.Join(dirs[dir], f)
+					assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+					assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+				}
+			}
+
+			var kept []string
+			err := PreserveLocalChangesUpdater{}.Update(Options{
+				RelPackagePath: "/",
+				LocalPath:      dirs["local"],
+				UpdatedPath:    dirs["updated"],
+				OriginPath:     dirs["original"],
+				IsRoot:         true,
+				KeptLocalFiles: &kept,
+			})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			for f, want := range tc.expected {
+				b, err := os.ReadFile(filepath.Join(dirs["local"], f))
+				if want == "" {
+					assert.True(t, os.IsNotExist(err), "%s should be deleted", f)
+					continue
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, want, string(b), f)
+			}
+			var wantKept []string
+			for _, f := range tc.kept {
+				wantKept = append(wantKept, filepath.Join(dirs["local"], f))
+			}
+			assert.Equal(t, wantKept, kept)
+		})
+	}
+}
@@ -36,7 +36,16 @@ import (
 
 // ResourceMergeUpdater updates a package by fetching the original and updated source
 // packages, and performing a 3-way merge of the Resources.
-type ResourceMergeUpdater struct{}
+type ResourceMergeUpdater struct {
+	// preserveLocalChanges replays the local changes to the files of each
+	// package on top of upstream instead of merging the resources. It is
+	// used by the PreserveLocalChangesUpdater.
+	preserveLocalChanges bool
+
+	// keptLocalFiles collects the files for which the preserve-local-changes
+	// strategy kept the local version instead of merging upstream changes.
+	keptLocalFiles *[]string
+}
 
 func (u ResourceMergeUpdater) Update(options Options) error {
 	const op errors.Op = "update.Update"
@@ -74,7 +83,7 @@ func (u ResourceMergeUpdater) Update(options Options) error {
 		updatedSubPkgPath := filepath.Join(options.UpdatedPath, subPkgPath)
 		originalSubPkgPath := filepath.Join(options.OriginPath, subPkgPath)
 
-		err := u.updatePackage(subPkgPath, localSubPkgPath, updatedSubPkgPath, originalSubPkgPath, isRootPkg)
+		err := u.updatePackage(subPkgPath, localSubPkgPath, updatedSubPkgPath, originalSubPkgPath, isRootPkg, options.Conflicts)
 		if err != nil {
 			return errors.E(op, types.UniquePath(localSubPkgPath), err)
 		}
@@ -85,7 +94,7 @@ func (u ResourceMergeUpdater) Update(options Options) error {
 // updatePackage updates the package in the location specified by localPath
 // using the provided paths to the updated version of the package and the
 // original version of the package.
-func (u ResourceMergeUpdater) updatePackage(subPkgPath, localPath, updatedPath, originalPath string, isRootPkg bool) error {
+func (u ResourceMergeUpdater) updatePackage(subPkgPath, localPath, updatedPath, originalPath string, isRootPkg bool, conflicts *[]merge.Conflict) error {
 	const op errors.Op = "update.updatePackage"
 	localExists, err := pkgutil.Exists(localPath)
 	if err != nil {
@@ -135,7 +144,7 @@ func (u ResourceMergeUpdater) updatePackage(subPkgPath, localPath, updatedPath,
 			}
 		}
 	default:
-		if err := u.mergePackage(localPath, updatedPath, originalPath, subPkgPath, isRootPkg); err != nil {
+		if err := u.mergePackage(localPath, updatedPath, originalPath, subPkgPath, isRootPkg, conflicts); err != nil {
 			return errors.E(op, types.UniquePath(localPath), err)
 		}
 	}
@@ -143,25 +152,35 @@ func (u ResourceMergeUpdater) updatePackage(subPkgPath, localPath, updatedPath,
 }
 
 // mergePackage merge a package. It does a 3-way merge by using the provided
-// paths to the local, updated and original versions of the package.
-func (u ResourceMergeUpdater) mergePackage(localPath, updatedPath, originalPath, _ string, isRootPkg bool) error {
+// paths to the local, updated and original versions of the package. Conflicts
+// found during the merge are added to conflicts if set.
+func (u ResourceMergeUpdater) mergePackage(localPath, updatedPath, originalPath, _ string, isRootPkg bool, conflicts *[]merge.Conflict) error {
 	const op errors.Op = "update.mergePackage"
 	if err := kptfileutil.UpdateKptfile(localPath, updatedPath, originalPath, !isRootPkg); err != nil {
 		return errors.E(op, types.UniquePath(localPath), err)
 	}
+	if u.preserveLocalChanges {
+		if err := replayLocalChanges(localPath, updatedPath, originalPath, u.keptLocalFiles); err != nil {
+			return errors.E(op, types.UniquePath(localPath), err)
+		}
+		return nil
+	}
 
 	// merge the Resources: original + updated + dest => dest
-	err := merge.Merge3{
+	found, err := merge.Merge3{
 		OriginalPath: originalPath,
 		UpdatedPath:  updatedPath,
 		DestPath:     localPath,
 		// TODO: Write a test to ensure this is set
 		MergeOnPath:        true,
 		IncludeSubPackages: false,
-	}.Merge()
+	}.MergeAndFindConflicts()
 	if err != nil {
 		return errors.E(op, types.UniquePath(localPath), err)
 	}
+	if conflicts != nil {
+		*conflicts = append(*conflicts, found...)
+	}
 
 	if err := ReplaceNonKRMFiles(updatedPath, originalPath, localPath); err != nil {
 		return errors.E(op, types.UniquePath(localPath), err)
@@ -30,6 +30,8 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/util/addmergecomment"
 	"github.com/GoogleContainerTools/kpt/internal/util/fetch"
 	"github.com/GoogleContainerTools/kpt/internal/util/git"
+	"github.com/GoogleContainerTools/kpt/internal/util/merge"
+	"github.com/GoogleContainerTools/kpt/internal/util/parse"
 	"github.com/GoogleContainerTools/kpt/internal/util/pkgutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/stack"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
@@ -79,6 +81,16 @@ type Options struct {
 	// updated and origin were fetched based on the information in the
 	// Kptfile from this package.
 	IsRoot bool
+
+	// Conflicts collects the resources with conflicting local and upstream
+	// changes if set. Only the resource-merge strategy finds conflicts, the
+	// preserve-local-changes strategy writes conflict markers directly.
+	Conflicts *[]merge.Conflict
+
+	// KeptLocalFiles collects the absolute paths of the files changed both
+	// locally and in upstream that the preserve-local-changes strategy
+	// couldn't merge, e.g. binary files, and kept the local version of if set.
+	KeptLocalFiles *[]string
 }
 
 // Updater updates a local package
@@ -87,9 +99,10 @@ type Updater interface {
 }
 
 var strategies = map[kptfilev1.UpdateStrategyType]func() Updater{
-	kptfilev1.FastForward:        func() Updater { return FastForwardUpdater{} },
-	kptfilev1.ForceDeleteReplace: func() Updater { return ReplaceUpdater{} },
-	kptfilev1.ResourceMerge:      func() Updater { return ResourceMergeUpdater{} },
+	kptfilev1.FastForward:          func() Updater { return FastForwardUpdater{} },
+	kptfilev1.ForceDeleteReplace:   func() Updater { return ReplaceUpdater{} },
+	kptfilev1.ResourceMerge:        func() Updater { return ResourceMergeUpdater{} },
+	kptfilev1.PreserveLocalChanges: func() Updater { return PreserveLocalChangesUpdater{} },
 }
 
 // Command updates the contents of a local package to a different version.
@@ -103,6 +116,19 @@ type Command struct {
 	// Strategy is the update strategy to use
 	Strategy kptfilev1.UpdateStrategyType
 
+	// MarkConflicts writes the local and upstream versions of resources with
+	// conflicting changes between conflict markers, instead of letting the
+	// upstream changes win. Conflicts are resolved with `kpt pkg resolve`.
+	MarkConflicts bool
+
+	// conflicts collects the conflicts found during the update if
+	// MarkConflicts is set.
+	conflicts *[]merge.Conflict
+
+	// keptLocalFiles collects the files the update kept the local version of
+	// although they were changed in upstream.
+	keptLocalFiles *[]string
+
 	// cachedUpstreamRepos is an upstream repo already fetched for a given repoSpec CloneRef
 	cachedUpstreamRepos map[string]*gitutil.GitUpstreamRepo
 }
@@ -121,13 +147,36 @@ func (u *Command) Run(ctx context.Context) error {
 		return errors.E(op, u.Pkg.UniquePath, err)
 	}
 
-	if rootKf.Upstream == nil || rootKf.Upstream.Git == nil {
+	if rootKf.Upstream == nil || (rootKf.Upstream.Git == nil && rootKf.Upstream.Oci == nil) {
 		return errors.E(op, u.Pkg.UniquePath,
 			fmt.Errorf("package must have an upstream reference"))
 	}
-	originalRootKfRef := rootKf.Upstream.Git.Ref
-	if u.Ref != "" {
-		rootKf.Upstream.Git.Ref = u.Ref
+	files, err := merge.FilesWithConflictMarkers(u.Pkg.UniquePath.String())
+	if err != nil {
+		return errors.E(op, u.Pkg.UniquePath, err)
+	}
+	if len(files) > 0 {
+		return errors.E(op, u.Pkg.UniquePath,
+			fmt.Errorf("package has unresolved conflicts in %s, resolve them with `kpt pkg resolve` first", strings.Join(files, ", ")))
+	}
+	if u.MarkConflicts {
+		u.conflicts = &[]merge.Conflict{}
+	}
+	u.keptLocalFiles = &[]string{}
+	var originalRootKfRef string
+	if rootKf.Upstream.Oci != nil {
+		if u.Ref != "" {
+			image, err := parse.OciImageWithVersion(rootKf.Upstream.Oci.Image, u.Ref)
+			if err != nil {
+				return errors.E(op, u.Pkg.UniquePath, err)
+			}
+			rootKf.Upstream.Oci.Image = image
+		}
+	} else {
+		originalRootKfRef = rootKf.Upstream.Git.Ref
+		if u.Ref != "" {
+			rootKf.Upstream.Git.Ref = u.Ref
+		}
 	}
 	if u.Strategy != "" {
 		rootKf.Upstream.UpdateStrategy = u.Strategy
@@ -164,10 +213,11 @@ func (u *Command) Run(ctx context.Context) error {
 				return errors.E(op, p.UniquePath, err)
 			}
 
-			if subKf.Upstream != nil && subKf.Upstream.Git != nil {
+			if subKf.Upstream != nil && (subKf.Upstream.Git != nil || subKf.Upstream.Oci != nil) {
 				// update subpackage kf ref/strategy if current pkg is a subpkg of root pkg or is root pkg
 				// and if original root pkg ref matches the subpkg ref
-				if shouldUpdateSubPkgRef(subKf, rootKf, originalRootKfRef) {
+				if rootKf.Upstream.Git != nil && subKf.Upstream.Git != nil &&
+					shouldUpdateSubPkgRef(subKf, rootKf, originalRootKfRef) {
 					updateSubKf(subKf, u.Ref, u.Strategy)
 					err = kptfileutil.WriteFile(subPkg.UniquePath.String(), subKf)
 					if err != nil {
@@ -184,6 +234,70 @@ func (u *Command) Run(ctx context.Context) error {
 	if err := addmergecomment.Process(string(u.Pkg.UniquePath)); err != nil {
 		return errors.E(op, u.Pkg.UniquePath, err)
 	}
+	if err := u.warnKeptLocalFiles(ctx); err != nil {
+		return errors.E(op, u.Pkg.UniquePath, err)
+	}
+	return u.markConflicts(ctx)
+}
+
+// warnKeptLocalFiles lists the files which were changed both locally and in
+// upstream but couldn't be merged, so the upstream changes to them aren't
+// dropped silently.
+func (u *Command) warnKeptLocalFiles(ctx context.Context) error {
+	const op errors.Op = "update.warnKeptLocalFiles"
+	pr := printer.FromContextOrDie(ctx)
+	if u.keptLocalFiles == nil || len(*u.keptLocalFiles) == 0 {
+		return nil
+	}
+	pr.Printf("\nKept the local version of files which can't be merged, although they were changed in upstream:\n")
+	for _, f := range *u.keptLocalFiles {
+		rel, err := filepath.Rel(u.Pkg.UniquePath.String(), f)
+		if err != nil {
+			return errors.E(op, u.Pkg.UniquePath, err)
+		}
+		pr.Printf("  %s\n", rel)
+	}
+	pr.Printf("Apply the upstream changes to the %d file(s) manually if needed.\n", len(*u.keptLocalFiles))
+	return nil
+}
+
+// markConflicts writes the conflicts found during the update to the local
+// package and lists the files with conflict markers, including the ones
+// written by the preserve-local-changes strategy. It must run last, since the
+// files with conflict markers are no longer valid yaml.
+func (u *Command) markConflicts(ctx context.Context) error {
+	const op errors.Op = "update.markConflicts"
+	pr := printer.FromContextOrDie(ctx)
+	if u.conflicts != nil && len(*u.conflicts) > 0 {
+		if _, err := merge.WriteConflictMarkers(*u.conflicts); err != nil {
+			return errors.E(op, u.Pkg.UniquePath, err)
+		}
+	}
+	files, err := merge.FilesWithConflictMarkers(u.Pkg.UniquePath.String())
+	if err != nil {
+		return errors.E(op, u.Pkg.UniquePath, err)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	pr.Printf("\nConflicting local and upstream changes in:\n")
+	listed := map[string]bool{}
+	if u.conflicts != nil {
+		for _, c := range *u.conflicts {
+			rel, err := filepath.Rel(u.Pkg.UniquePath.String(), c.File)
+			if err != nil {
+				return errors.E(op, u.Pkg.UniquePath, err)
+			}
+			pr.Printf("  %s: %s (%s)\n", rel, c.ID(), strings.Join(c.Fields, ", "))
+			listed[rel] = true
+		}
+	}
+	for _, f := range files {
+		if !listed[f] {
+			pr.Printf("  %s\n", f)
+		}
+	}
+	pr.Printf("Edit the %d file(s) or run `kpt pkg resolve --ours|--theirs` to resolve the conflicts.\n", len(files))
 	return nil
 }
 
@@ -257,30 +371,17 @@ func (u Command) updateRootPackage(ctx context.Context, p *pkg.Pkg) error {
 	pr := printer.FromContextOrDie(ctx)
 	pr.PrintPackage(p, !(p == u.Pkg))
 
-	g := kf.Upstream.Git
-	updated := &git.RepoSpec{OrgRepo: g.Repo, Path: g.Directory, Ref: g.Ref}
-	pr.Printf("Fetching upstream from %s@%s\n", kf.Upstream.Git.Repo, kf.Upstream.Git.Ref)
-	cloner := fetch.NewCloner(updated, fetch.WithCachedRepo(u.cachedUpstreamRepos))
-	if err := cloner.ClonerUsingGitExec(ctx); err != nil {
+	var updated, origin repoClone
+	var updateLock func(path string) error
+	if kf.Upstream.Oci != nil {
+		updated, origin, updateLock, err = u.pullOciUpstream(ctx, kf)
+	} else {
+		updated, origin, updateLock, err = u.cloneGitUpstream(ctx, kf)
+	}
+	if err != nil {
 		return errors.E(op, p.UniquePath, err)
 	}
 	defer os.RemoveAll(updated.AbsPath())
-
-	var origin repoClone
-	if kf.UpstreamLock != nil {
-		gLock := kf.UpstreamLock.Git
-		originRepoSpec := &git.RepoSpec{OrgRepo: gLock.Repo, Path: gLock.Directory, Ref: gLock.Commit}
-		pr.Printf("Fetching origin from %s@%s\n", kf.Upstream.Git.Repo, kf.Upstream.Git.Ref)
-		if err := fetch.NewCloner(originRepoSpec, fetch.WithCachedRepo(u.cachedUpstreamRepos)).ClonerUsingGitExec(ctx); err != nil {
-			return errors.E(op, p.UniquePath, err)
-		}
-		origin = originRepoSpec
-	} else {
-		origin, err = newNilRepoClone()
-		if err != nil {
-			return errors.E(op, p.UniquePath, err)
-		}
-	}
 	defer os.RemoveAll(origin.AbsPath())
 
 	s := stack.New()
@@ -311,12 +412,90 @@ func (u Command) updateRootPackage(ctx context.Context, p *pkg.Pkg) error {
 		}
 	}
 
-	if err := kptfileutil.UpdateUpstreamLockFromGit(p.UniquePath.String(), updated); err != nil {
+	if err := updateLock(p.UniquePath.String()); err != nil {
 		return errors.E(op, p.UniquePath, err)
 	}
 	return nil
 }
 
+// cloneGitUpstream fetches the updated and origin versions of the package
+// from git. The returned function records the updated commit in the
+// upstreamLock of the package at the given path.
+func (u Command) cloneGitUpstream(ctx context.Context, kf *kptfilev1.KptFile) (repoClone, repoClone, func(string) error, error) {
+	const op errors.Op = "update.cloneGitUpstream"
+	pr := printer.FromContextOrDie(ctx)
+
+	g := kf.Upstream.Git
+	updated := &git.RepoSpec{OrgRepo: g.Repo, Path: g.Directory, Ref: g.Ref}
+	pr.Printf("Fetching upstream from %s@%s\n", kf.Upstream.Git.Repo, kf.Upstream.Git.Ref)
+	cloner := fetch.NewCloner(updated, fetch.WithCachedRepo(u.cachedUpstreamRepos))
+	if err := cloner.ClonerUsingGitExec(ctx); err != nil {
+		return nil, nil, nil, errors.E(op, err)
+	}
+
+	var origin repoClone
+	if kf.UpstreamLock != nil && kf.UpstreamLock.Git != nil {
+		gLock := kf.UpstreamLock.Git
+		originRepoSpec := &git.RepoSpec{OrgRepo: gLock.Repo, Path: gLock.Directory, Ref: gLock.Commit}
+		pr.Printf("Fetching origin from %s@%s\n", kf.Upstream.Git.Repo, kf.Upstream.Git.Ref)
+		if err := fetch.NewCloner(originRepoSpec, fetch.WithCachedRepo(u.cachedUpstreamRepos)).ClonerUsingGitExec(ctx); err != nil {
+			os.RemoveAll(updated.AbsPath())
+			return nil, nil, nil, errors.E(op, err)
+		}
+		origin = originRepoSpec
+	} else {
+		var err error
+		origin, err = newNilRepoClone()
+		if err != nil {
+			os.RemoveAll(updated.AbsPath())
+			return nil, nil, nil, errors.E(op, err)
+		}
+	}
+
+	updateLock := func(path string) error {
+		return kptfileutil.UpdateUpstreamLockFromGit(path, updated)
+	}
+	return updated, origin, updateLock, nil
+}
+
+// pullOciUpstream pulls the updated and origin versions of the package from
+// the OCI registry. The origin is pulled by the digest recorded in the
+// upstreamLock. The returned function records the digest of the updated
+// image in the upstreamLock of the package at the given path.
+func (u Command) pullOciUpstream(ctx context.Context, kf *kptfilev1.KptFile) (repoClone, repoClone, func(string) error, error) {
+	const op errors.Op = "update.pullOciUpstream"
+	pr := printer.FromContextOrDie(ctx)
+
+	updated := fetch.NewOciCloner(kf.Upstream.Oci.Image)
+	pr.Printf("Fetching upstream from %s\n", kf.Upstream.Oci.Image)
+	if err := updated.ClonerUsingOciPull(ctx); err != nil {
+		return nil, nil, nil, errors.E(op, err)
+	}
+
+	var origin repoClone
+	if kf.UpstreamLock != nil && kf.UpstreamLock.Oci != nil {
+		o := fetch.NewOciCloner(kf.UpstreamLock.Oci.Image)
+		pr.Printf("Fetching origin from %s\n", kf.UpstreamLock.Oci.Image)
+		if err := o.ClonerUsingOciPull(ctx); err != nil {
+			os.RemoveAll(updated.AbsPath())
+			return nil, nil, nil, errors.E(op, err)
+		}
+		origin = o
+	} else {
+		var err error
+		origin, err = newNilRepoClone()
+		if err != nil {
+			os.RemoveAll(updated.AbsPath())
+			return nil, nil, nil, errors.E(op, err)
+		}
+	}
+
+	updateLock := func(path string) error {
+		return kptfileutil.UpdateUpstreamLockFromOCI(path, updated.LockedImage)
+	}
+	return updated, origin, updateLock, nil
+}
+
 // updatePackage takes care of updating a single package. The absolute paths to
 // the local, updated and origin packages are provided, as well as the path to the
 // package relative to the root.
@@ -470,6 +649,8 @@ func (u Command) mergePackage(ctx context.Context, localPath, updatedPath, origi
 		UpdatedPath:    updatedPath,
 		OriginPath:     originPath,
 		IsRoot:         isRootPkg,
+		Conflicts:      u.conflicts,
+		KeptLocalFiles: u.keptLocalFiles,
 	}); err != nil {
 		return errors.E(op, types.UniquePath(localPath), err)
 	}
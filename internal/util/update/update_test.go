@@ -26,6 +26,7 @@ import (
 	pkgtest "github.com/GoogleContainerTools/kpt/internal/pkg/testing"
 	"github.com/GoogleContainerTools/kpt/internal/testutil"
 	"github.com/GoogleContainerTools/kpt/internal/testutil/pkgbuilder"
+	"github.com/GoogleContainerTools/kpt/internal/util/fetch"
 	. "github.com/GoogleContainerTools/kpt/internal/util/update"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
@@ -884,6 +885,65 @@ func TestCommand_Run_toTagRef(t *testing.T) {
 	}
 }
 
+// TestCommand_Run_oci updates a package pulled from an OCI registry to a
+// new tag of the image.
+func TestCommand_Run_oci(t *testing.T) {
+	for i := range kptfilev1.UpdateStrategies {
+		strategy := kptfilev1.UpdateStrategies[i]
+		t.Run(string(strategy), func(t *testing.T) {
+			image := testutil.SetupOciRegistry(t) + "/blueprints/" + testPackageName
+			testutil.PushOciPackage(t, image+":v1", testutil.Dataset1)
+			lockedImage := testutil.PushOciPackage(t, image+":v2", testutil.Dataset2)
+
+			w, clean := testutil.SetupWorkspace(t)
+			defer clean()
+			w.PackageDir = testPackageName
+			kf := kptfileutil.DefaultKptfile(testPackageName)
+			kf.Upstream = &kptfilev1.Upstream{
+				Type:           kptfilev1.OciOrigin,
+				Oci:            &kptfilev1.Oci{Image: image + ":v1"},
+				UpdateStrategy: strategy,
+			}
+			testutil.AddKptfileToWorkspace(t, w, kf)
+			if !assert.NoError(t, fetch.Command{
+				Pkg: pkgtest.CreatePkgOrFail(t, w.FullPackagePath()),
+			}.Run(fake.CtxWithDefaultPrinter())) {
+				return
+			}
+
+			// Update the local package
+			if !assert.NoError(t, (&Command{
+				Pkg:      pkgtest.CreatePkgOrFail(t, w.FullPackagePath()),
+				Strategy: strategy,
+				Ref:      "v2",
+			}).Run(fake.CtxWithDefaultPrinter())) {
+				return
+			}
+
+			// Expect the local package to have Dataset2
+			ds, err := testutil.GetTestDataPath()
+			if !assert.NoError(t, err) {
+				return
+			}
+			diff, err := testutil.Diff(filepath.Join(ds, testutil.Dataset2), w.FullPackagePath(), true)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Empty(t, diff.Difference(testutil.KptfileSet).List())
+
+			kf, err = pkg.ReadKptfile(filesys.FileSystemOrOnDisk{}, w.FullPackagePath())
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, image+":v2", kf.Upstream.Oci.Image)
+			assert.Equal(t, &kptfilev1.UpstreamLock{
+				Type: kptfilev1.OciOrigin,
+				Oci:  &kptfilev1.OciLock{Image: lockedImage},
+			}, kf.UpstreamLock)
+		})
+	}
+}
+
 // TestCommand_ResourceMerge_NonKRMUpdates tests if the local non KRM files are updated
 func TestCommand_ResourceMerge_NonKRMUpdates(t *testing.T) {
 	strategies := []kptfilev1.UpdateStrategyType{kptfilev1.ResourceMerge}
@@ -21,6 +21,7 @@
 //go:generate $GOBIN/mdtogo site/reference/cli/alpha/sync internal/docs/generated/syncdocs --license=none --recursive=true --strategy=cmdDocs
 //go:generate $GOBIN/mdtogo site/reference/cli/alpha/wasm internal/docs/generated/wasmdocs --license=none --recursive=true --strategy=cmdDocs
 //go:generate $GOBIN/mdtogo site/reference/cli/alpha/license internal/docs/generated/licensedocs --license=none --recursive=true --strategy=cmdDocs
+//go:generate $GOBIN/mdtogo site/reference/cli/doctor internal/docs/generated/doctordocs --license=none --recursive=false --strategy=cmdDocs
 //go:generate $GOBIN/mdtogo site/reference/cli/README.md internal/docs/generated/overview --license=none --strategy=cmdDocs
 package main
 
@@ -59,6 +60,12 @@ func runMain() int {
 
 	cmd := run.GetMain(ctx)
 
+	// Commands that aren't builtin are dispatched to kpt-<name> plugins
+	// on the PATH.
+	if exitCode, handled := run.RunPlugin(ctx, cmd, os.Args[1:]); handled {
+		return exitCode
+	}
+
 	err = cli.RunNoErrOutput(cmd)
 	if err != nil {
 		return handleErr(cmd, err)
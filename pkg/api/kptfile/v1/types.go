@@ -89,6 +89,10 @@ type OriginType string
 const (
 	// GitOrigin specifies a package as having been cloned from a git repository.
 	GitOrigin OriginType = "git"
+
+	// OciOrigin specifies a package as having been pulled from an OCI
+	// registry.
+	OciOrigin OriginType = "oci"
 )
 
 // UpdateStrategyType defines the strategy for updating a package from upstream.
@@ -105,6 +109,8 @@ func ToUpdateStrategy(strategy string) (UpdateStrategyType, error) {
 		return FastForward, nil
 	case string(ForceDeleteReplace):
 		return ForceDeleteReplace, nil
+	case string(PreserveLocalChanges):
+		return PreserveLocalChanges, nil
 	default:
 		return "", fmt.Errorf("unknown update strategy %q", strategy)
 	}
@@ -119,6 +125,9 @@ const (
 	FastForward UpdateStrategyType = "fast-forward"
 	// ForceDeleteReplace wipes all local changes to the package.
 	ForceDeleteReplace UpdateStrategyType = "force-delete-replace"
+	// PreserveLocalChanges replays the local changes on top of the updated
+	// upstream package, like a git rebase.
+	PreserveLocalChanges UpdateStrategyType = "preserve-local-changes"
 )
 
 // UpdateStrategies is a slice with all the supported update strategies.
@@ -126,6 +135,7 @@ var UpdateStrategies = []UpdateStrategyType{
 	ResourceMerge,
 	FastForward,
 	ForceDeleteReplace,
+	PreserveLocalChanges,
 }
 
 // UpdateStrategiesAsStrings returns a list of update strategies as strings.
@@ -145,6 +155,9 @@ type Upstream struct {
 	// Git is the locator for a package stored on Git.
 	Git *Git `yaml:"git,omitempty" json:"git,omitempty"`
 
+	// Oci is the locator for a package stored in an OCI registry.
+	Oci *Oci `yaml:"oci,omitempty" json:"oci,omitempty"`
+
 	// UpdateStrategy declares how a package will be updated from upstream.
 	UpdateStrategy UpdateStrategyType `yaml:"updateStrategy,omitempty" json:"updateStrategy,omitempty"`
 }
@@ -163,6 +176,13 @@ type Git struct {
 	Ref string `yaml:"ref,omitempty" json:"ref,omitempty"`
 }
 
+// Oci is the user-specified locator for a package in an OCI registry.
+type Oci struct {
+	// Image is the image holding the package, referenced by tag or digest.
+	// e.g. 'us-docker.pkg.dev/my-project/blueprints/basens:v1'
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+}
+
 // UpstreamLock is a resolved locator for the last fetch of the package.
 type UpstreamLock struct {
 	// Type is the type of origin.
@@ -170,6 +190,9 @@ type UpstreamLock struct {
 
 	// Git is the resolved locator for a package on Git.
 	Git *GitLock `yaml:"git,omitempty" json:"git,omitempty"`
+
+	// Oci is the resolved locator for a package in an OCI registry.
+	Oci *OciLock `yaml:"oci,omitempty" json:"oci,omitempty"`
 }
 
 // GitLock is the resolved locator for a package on Git.
@@ -191,6 +214,15 @@ type GitLock struct {
 	Commit string `yaml:"commit,omitempty" json:"commit,omitempty"`
 }
 
+// OciLock is the resolved locator for a package in an OCI registry.
+type OciLock struct {
+	// Image is the image that was pulled, pinned to the digest of the
+	// fetched manifest.
+	// e.g. 'us-docker.pkg.dev/my-project/blueprints/basens@sha256:...'
+	// This is set by kpt for bookkeeping purposes.
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+}
+
 // PackageInfo contains optional information about the package such as license, documentation, etc.
 // These fields are not consumed by any functionality in kpt and are simply passed through.
 // Note that like any other KRM resource, humans and automation can also use `metadata.labels` and
@@ -307,6 +339,12 @@ type Function struct {
 	// 	 exec: /usr/local/bin/my-custom-fn
 	Exec string `yaml:"exec,omitempty" json:"exec,omitempty"`
 
+	// `ClusterAccess` declares that the function reads from the cluster of the
+	// current kubeconfig context, e.g. to look up existing Services or CRD
+	// schemas. The function is only given a kubeconfig for that context when
+	// `kpt fn render` is run with `--allow-cluster-access`.
+	ClusterAccess bool `yaml:"clusterAccess,omitempty" json:"clusterAccess,omitempty"`
+
 	// `ConfigPath` specifies a slash-delimited relative path to a file in the current directory
 	// containing a KRM resource used as the function config. This resource is
 	// excluded when resolving 'sources', and as a result cannot be operated on
@@ -327,6 +365,30 @@ type Function struct {
 	// `Exclude` are used to specify resources on which the function should NOT be executed.
 	// If not specified, all resources selected by `Selectors` are selected.
 	Exclusions []Selector `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+
+	// `When` is the condition under which the function is run, e.g. to only run
+	// it for the production variant of a package. If not specified, the
+	// function is always run.
+	When *FunctionCondition `yaml:"when,omitempty" json:"when,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+// FunctionCondition specifies when a function of the pipeline is run. The function is
+// run if all of the specified requirements are met.
+type FunctionCondition struct {
+	// `Env` are environment variables of `kpt fn render` and the values they
+	// must have, e.g.
+	//
+	//	env:
+	//	  STAGE: prod
+	//
+	// A variable with an empty value must be set to any value.
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+
+	// `Resources` are selectors of resources of which at least one must be in
+	// the input of the function.
+	Resources []Selector `yaml:"resources,omitempty" json:"resources,omitempty"`
 }
 
 // Selector specifies the selection criteria
@@ -0,0 +1,333 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// KubeObject is a resource of a ResourceList. Comments and field order of
+// the resource are kept when it is modified.
+type KubeObject struct {
+	node *yaml.RNode
+}
+
+// KubeObjects is a list of resources.
+type KubeObjects []*KubeObject
+
+// ParseKubeObject parses a single resource from YAML or JSON.
+func ParseKubeObject(b []byte) (*KubeObject, error) {
+	node, err := yaml.Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+	if node.YNode().Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping node for the object, got %s", node.YNode().ShortTag())
+	}
+	return &KubeObject{node: node}, nil
+}
+
+// NewFromTypedObject returns a resource from a typed Go object with json
+// tags, e.g. a corev1.ConfigMap.
+func NewFromTypedObject(v interface{}) (*KubeObject, error) {
+	node, err := toNode(v)
+	if err != nil {
+		return nil, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping node for the object, got %s", node.ShortTag())
+	}
+	return &KubeObject{node: yaml.NewRNode(node)}, nil
+}
+
+// As decodes the resource into the typed Go object with json tags pointed
+// to by ptr.
+func (o *KubeObject) As(ptr interface{}) error {
+	return fromNode(o.node, ptr)
+}
+
+// String returns the resource as YAML.
+func (o *KubeObject) String() string {
+	s, err := o.node.String()
+	if err != nil {
+		return fmt.Sprintf("<invalid object: %v>", err)
+	}
+	return s
+}
+
+func (o *KubeObject) GetAPIVersion() string {
+	return o.node.GetApiVersion()
+}
+
+func (o *KubeObject) GetKind() string {
+	return o.node.GetKind()
+}
+
+func (o *KubeObject) GetName() string {
+	return o.node.GetName()
+}
+
+func (o *KubeObject) SetName(name string) error {
+	return o.node.SetName(name)
+}
+
+func (o *KubeObject) GetNamespace() string {
+	return o.node.GetNamespace()
+}
+
+func (o *KubeObject) SetNamespace(namespace string) error {
+	return o.node.SetNamespace(namespace)
+}
+
+// IsGVK returns true if the resource has the group, version and kind. Empty
+// arguments match everything.
+func (o *KubeObject) IsGVK(group, version, kind string) bool {
+	gv, err := schema.ParseGroupVersion(o.GetAPIVersion())
+	if err != nil {
+		return false
+	}
+	return (group == "" || gv.Group == group) &&
+		(version == "" || gv.Version == version) &&
+		(kind == "" || o.GetKind() == kind)
+}
+
+// IsLocalConfig returns true if the resource is annotated as local
+// configuration, which is not applied to the cluster.
+func (o *KubeObject) IsLocalConfig() bool {
+	return o.GetAnnotation("config.kubernetes.io/local-config") == "true"
+}
+
+// GetLabels returns the labels of the resource, or an empty map.
+func (o *KubeObject) GetLabels() map[string]string {
+	return o.node.GetLabels()
+}
+
+func (o *KubeObject) GetLabel(key string) string {
+	return o.node.GetLabels()[key]
+}
+
+func (o *KubeObject) SetLabel(key, value string) error {
+	return o.SetNestedString(value, "metadata", "labels", key)
+}
+
+func (o *KubeObject) RemoveLabel(key string) error {
+	_, err := o.RemoveNestedField("metadata", "labels", key)
+	return err
+}
+
+// GetAnnotations returns the annotations of the resource, or an empty map.
+func (o *KubeObject) GetAnnotations() map[string]string {
+	return o.node.GetAnnotations()
+}
+
+func (o *KubeObject) GetAnnotation(key string) string {
+	return o.node.GetAnnotations()[key]
+}
+
+func (o *KubeObject) SetAnnotation(key, value string) error {
+	return o.SetNestedString(value, "metadata", "annotations", key)
+}
+
+func (o *KubeObject) RemoveAnnotation(key string) error {
+	_, err := o.RemoveNestedField("metadata", "annotations", key)
+	return err
+}
+
+// GetPath returns the path of the file holding the resource, relative to the
+// root package.
+func (o *KubeObject) GetPath() string {
+	return o.GetAnnotation(kioutil.PathAnnotation)
+}
+
+// NestedString returns the string at the path of fields, e.g.
+// NestedString("spec", "template", "spec", "serviceAccountName"). The second
+// return value is false if the field doesn't exist.
+func (o *KubeObject) NestedString(fields ...string) (string, bool, error) {
+	var v string
+	found, err := o.nested(&v, fields...)
+	return v, found, err
+}
+
+// NestedBool returns the boolean at the path of fields.
+func (o *KubeObject) NestedBool(fields ...string) (bool, bool, error) {
+	var v bool
+	found, err := o.nested(&v, fields...)
+	return v, found, err
+}
+
+// NestedInt64 returns the integer at the path of fields.
+func (o *KubeObject) NestedInt64(fields ...string) (int64, bool, error) {
+	var v int64
+	found, err := o.nested(&v, fields...)
+	return v, found, err
+}
+
+// NestedStringMap returns the map of strings at the path of fields.
+func (o *KubeObject) NestedStringMap(fields ...string) (map[string]string, bool, error) {
+	var v map[string]string
+	found, err := o.nested(&v, fields...)
+	return v, found, err
+}
+
+// NestedStringSlice returns the list of strings at the path of fields.
+func (o *KubeObject) NestedStringSlice(fields ...string) ([]string, bool, error) {
+	var v []string
+	found, err := o.nested(&v, fields...)
+	return v, found, err
+}
+
+// NestedField decodes the value at the path of fields into the typed Go
+// object with json tags pointed to by ptr.
+func (o *KubeObject) NestedField(ptr interface{}, fields ...string) (bool, error) {
+	return o.nested(ptr, fields...)
+}
+
+func (o *KubeObject) nested(ptr interface{}, fields ...string) (bool, error) {
+	node, err := o.node.Pipe(yaml.Lookup(fields...))
+	if err != nil {
+		return false, fmt.Errorf("cannot look up field %s: %w", strings.Join(fields, "."), err)
+	}
+	if yaml.IsMissingOrNull(node) {
+		return false, nil
+	}
+	if err := fromNode(node, ptr); err != nil {
+		return true, fmt.Errorf("cannot decode field %s: %w", strings.Join(fields, "."), err)
+	}
+	return true, nil
+}
+
+// SetNestedString sets the string at the path of fields, creating the
+// missing parent fields.
+func (o *KubeObject) SetNestedString(value string, fields ...string) error {
+	return o.SetNestedField(value, fields...)
+}
+
+func (o *KubeObject) SetNestedBool(value bool, fields ...string) error {
+	return o.SetNestedField(value, fields...)
+}
+
+func (o *KubeObject) SetNestedInt64(value int64, fields ...string) error {
+	return o.SetNestedField(value, fields...)
+}
+
+func (o *KubeObject) SetNestedStringMap(value map[string]string, fields ...string) error {
+	return o.SetNestedField(value, fields...)
+}
+
+func (o *KubeObject) SetNestedStringSlice(value []string, fields ...string) error {
+	return o.SetNestedField(value, fields...)
+}
+
+// SetNestedField sets the value, which may be any Go object encodable to
+// JSON, at the path of fields, creating the missing parent fields.
+func (o *KubeObject) SetNestedField(value interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no field to set")
+	}
+	node, err := toNode(value)
+	if err != nil {
+		return fmt.Errorf("cannot encode field %s: %w", strings.Join(fields, "."), err)
+	}
+	return o.node.PipeE(
+		yaml.LookupCreate(yaml.MappingNode, fields[:len(fields)-1]...),
+		yaml.SetField(fields[len(fields)-1], yaml.NewRNode(node)))
+}
+
+// RemoveNestedField removes the field at the path of fields. It returns
+// false if the field doesn't exist.
+func (o *KubeObject) RemoveNestedField(fields ...string) (bool, error) {
+	if len(fields) == 0 {
+		return false, fmt.Errorf("no field to remove")
+	}
+	parent, err := o.node.Pipe(yaml.Lookup(fields[:len(fields)-1]...))
+	if err != nil || parent == nil {
+		return false, err
+	}
+	removed, err := parent.Pipe(yaml.Clear(fields[len(fields)-1]))
+	return removed != nil, err
+}
+
+// Where returns the resources for which the predicate is true.
+func (objs KubeObjects) Where(predicate func(*KubeObject) bool) KubeObjects {
+	var result KubeObjects
+	for _, o := range objs {
+		if predicate(o) {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// IsGVK returns a predicate for Where matching resources with the group,
+// version and kind. Empty arguments match everything.
+func IsGVK(group, version, kind string) func(*KubeObject) bool {
+	return func(o *KubeObject) bool {
+		return o.IsGVK(group, version, kind)
+	}
+}
+
+func (objs KubeObjects) nodes() []*yaml.RNode {
+	nodes := make([]*yaml.RNode, 0, len(objs))
+	for _, o := range objs {
+		nodes = append(nodes, o.node)
+	}
+	return nodes
+}
+
+// toNode encodes the value to a YAML node through JSON, so the json tags of
+// Kubernetes types are used. The JSON style of the node is cleared so it is
+// formatted like the rest of the resource.
+func toNode(value interface{}) (*yaml.Node, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	clearStyle(node)
+	return node, nil
+}
+
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		clearStyle(n)
+	}
+}
+
+// fromNode decodes the node into the value through JSON, so the json tags
+// of Kubernetes types are used.
+func fromNode(node *yaml.RNode, ptr interface{}) error {
+	// RNode.MarshalJSON only supports mapping nodes, so decode the node
+	// first.
+	var v interface{}
+	if err := node.YNode().Decode(&v); err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, ptr)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app # the app
+  labels:
+    tier: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx
+`
+
+func TestKubeObject_Nested(t *testing.T) {
+	o, err := ParseKubeObject([]byte(deployment))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.True(t, o.IsGVK("apps", "v1", "Deployment"))
+	assert.True(t, o.IsGVK("", "", "Deployment"))
+	assert.False(t, o.IsGVK("", "v1", "ConfigMap"))
+
+	replicas, found, err := o.NestedInt64("spec", "replicas")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(3), replicas)
+
+	_, found, err = o.NestedString("spec", "serviceAccountName")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, _, err = o.NestedString("spec", "template")
+	assert.Error(t, err)
+
+	assert.NoError(t, o.SetNestedInt64(5, "spec", "replicas"))
+	assert.NoError(t, o.SetNestedString("sa", "spec", "template", "spec", "serviceAccountName"))
+	assert.NoError(t, o.SetNestedBool(true, "spec", "paused"))
+	assert.NoError(t, o.SetLabel("app", "true"))
+	assert.NoError(t, o.RemoveLabel("tier"))
+	assert.NoError(t, o.SetAnnotation("owner", "team-a"))
+
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app # the app
+  labels:
+    app: "true"
+  annotations:
+    owner: team-a
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx
+      serviceAccountName: sa
+  paused: true
+`, o.String())
+}
+
+func TestKubeObject_TypedObject(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Data:       map[string]string{"enabled": "true"},
+	}
+	o, err := NewFromTypedObject(cm)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "config", o.GetName())
+	assert.Equal(t, "default", o.GetNamespace())
+	data, found, err := o.NestedStringMap("data")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, map[string]string{"enabled": "true"}, data)
+
+	assert.NoError(t, o.SetNamespace("prod"))
+	got := &corev1.ConfigMap{}
+	assert.NoError(t, o.As(got))
+	assert.Equal(t, "prod", got.Namespace)
+	assert.Equal(t, cm.Data, got.Data)
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Result is a result reported by a function, e.g. a validation error.
+type Result = framework.Result
+
+// Results is the list of results of a function.
+type Results = framework.Results
+
+// Severity is the severity of a result.
+type Severity = framework.Severity
+
+const (
+	Error   Severity = framework.Error
+	Warning Severity = framework.Warning
+	Info    Severity = framework.Info
+)
+
+// ResourceList is the input and output of a KRM function, see
+// https://github.com/kubernetes-sigs/kustomize/blob/master/cmd/config/docs/api-conventions/functions-spec.md.
+type ResourceList struct {
+	// Items are the resources processed by the function.
+	Items KubeObjects
+	// FunctionConfig is the configuration of the function, or nil.
+	FunctionConfig *KubeObject
+	// Results are the results reported by the function.
+	Results Results
+}
+
+// ParseResourceList parses a ResourceList from YAML.
+func ParseResourceList(b []byte) (*ResourceList, error) {
+	rw := &kio.ByteReadWriter{Reader: bytes.NewReader(b), PreserveSeqIndent: true, WrapBareSeqNode: true}
+	nodes, err := rw.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ResourceList: %w", err)
+	}
+	rl := &ResourceList{}
+	for _, n := range nodes {
+		rl.Items = append(rl.Items, &KubeObject{node: n})
+	}
+	if rw.FunctionConfig != nil && !yaml.IsMissingOrNull(rw.FunctionConfig) {
+		rl.FunctionConfig = &KubeObject{node: rw.FunctionConfig}
+	}
+	if rw.Results != nil {
+		if err := rw.Results.YNode().Decode(&rl.Results); err != nil {
+			return nil, fmt.Errorf("failed to read ResourceList results: %w", err)
+		}
+	}
+	return rl, nil
+}
+
+// ToYAML returns the ResourceList as YAML.
+func (rl *ResourceList) ToYAML() ([]byte, error) {
+	out := &bytes.Buffer{}
+	w := kio.ByteWriter{
+		Writer:                out,
+		KeepReaderAnnotations: true,
+		WrappingAPIVersion:    kio.ResourceListAPIVersion,
+		WrappingKind:          kio.ResourceListKind,
+	}
+	if rl.FunctionConfig != nil {
+		w.FunctionConfig = rl.FunctionConfig.node
+	}
+	if len(rl.Results) > 0 {
+		node := &yaml.Node{}
+		if err := node.Encode(rl.Results); err != nil {
+			return nil, err
+		}
+		w.Results = yaml.NewRNode(node)
+	}
+	if err := w.Write(rl.Items.nodes()); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// AddResult adds a result about the resource obj, which may be nil.
+func (rl *ResourceList) AddResult(message string, severity Severity, obj *KubeObject) {
+	r := &Result{Message: message, Severity: severity}
+	if obj != nil {
+		r.ResourceRef = &yaml.ResourceIdentifier{
+			TypeMeta: yaml.TypeMeta{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind()},
+			NameMeta: yaml.NameMeta{Name: obj.GetName(), Namespace: obj.GetNamespace()},
+		}
+		if path := obj.GetPath(); path != "" {
+			r.File = &framework.File{Path: path}
+		}
+	}
+	rl.Results = append(rl.Results, r)
+}
+
+// ResourceListProcessor is a KRM function. Process modifies the ResourceList
+// and returns false if the function failed, in which case its results should
+// explain why. A returned error is added to the results.
+type ResourceListProcessor interface {
+	Process(rl *ResourceList) (bool, error)
+}
+
+// ResourceListProcessorFunc is a ResourceListProcessor implemented by a
+// function.
+type ResourceListProcessorFunc func(rl *ResourceList) (bool, error)
+
+func (f ResourceListProcessorFunc) Process(rl *ResourceList) (bool, error) {
+	return f(rl)
+}
+
+// AsMain runs the function in the main package of a function binary or
+// image, reading the ResourceList from stdin and writing it to stdout. The
+// function binary should exit with a non-zero code if it returns an error:
+//
+//	func main() {
+//		if err := fn.AsMain(fn.ResourceListProcessorFunc(process)); err != nil {
+//			os.Exit(1)
+//		}
+//	}
+func AsMain(p ResourceListProcessor) error {
+	err := Execute(p, os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return err
+}
+
+// Execute runs the function on the ResourceList read from r, and writes the
+// resulting ResourceList to w. The ResourceList is written even if the
+// function fails, so the results are reported.
+func Execute(p ResourceListProcessor, r io.Reader, w io.Writer) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	rl, err := ParseResourceList(b)
+	if err != nil {
+		return err
+	}
+	nodeAnnos, err := kio.PreprocessResourcesForInternalAnnotationMigration(rl.Items.nodes())
+	if err != nil {
+		return err
+	}
+
+	ok, processErr := p.Process(rl)
+	if processErr != nil {
+		rl.Results = append(rl.Results, &Result{Message: processErr.Error(), Severity: Error})
+		ok = false
+	}
+
+	// keep the internal and legacy path and index annotations in sync if
+	// the function changed either of them.
+	if err := kio.ReconcileInternalAnnotations(rl.Items.nodes(), nodeAnnos); err != nil {
+		return err
+	}
+	out, err := rl.ToYAML()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(out); err != nil {
+		return err
+	}
+
+	if !ok {
+		if rl.Results.ExitCode() != 0 {
+			return rl.Results
+		}
+		return fmt.Errorf("function failed")
+	}
+	return nil
+}
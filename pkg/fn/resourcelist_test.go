@@ -0,0 +1,131 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fn
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const resourceList = `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+    annotations:
+      internal.config.kubernetes.io/path: config.yaml
+  data:
+    enabled: "true"
+`
+
+func TestExecute(t *testing.T) {
+	testCases := map[string]struct {
+		process   ResourceListProcessorFunc
+		expectErr string
+		expected  string
+	}{
+		"modifies the items": {
+			process: func(rl *ResourceList) (bool, error) {
+				for _, o := range rl.Items {
+					if err := o.SetNamespace("prod"); err != nil {
+						return false, err
+					}
+				}
+				return true, nil
+			},
+			expected: `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+    annotations:
+      internal.config.kubernetes.io/path: config.yaml
+    namespace: prod
+  data:
+    enabled: "true"
+`,
+		},
+		"reports results": {
+			process: func(rl *ResourceList) (bool, error) {
+				rl.AddResult("enabled must be false", Error, rl.Items[0])
+				return false, nil
+			},
+			expectErr: "enabled must be false",
+			expected: `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+    annotations:
+      internal.config.kubernetes.io/path: config.yaml
+  data:
+    enabled: "true"
+results:
+- message: enabled must be false
+  severity: error
+  resourceRef:
+    apiVersion: v1
+    kind: ConfigMap
+    name: config
+  file:
+    path: config.yaml
+`,
+		},
+		"reports errors": {
+			process: func(rl *ResourceList) (bool, error) {
+				return true, fmt.Errorf("something went wrong")
+			},
+			expectErr: "something went wrong",
+			expected: `apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+    annotations:
+      internal.config.kubernetes.io/path: config.yaml
+  data:
+    enabled: "true"
+results:
+- message: something went wrong
+  severity: error
+`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := Execute(tc.process, strings.NewReader(resourceList), out)
+			if tc.expectErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expectErr)
+			}
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
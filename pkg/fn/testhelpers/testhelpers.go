@@ -0,0 +1,83 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testhelpers contains a test harness for KRM functions written with
+// the fn package.
+package testhelpers
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/pkg/fn"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// InputFileName is the name of the ResourceList given to the function
+	// in a test case directory.
+	InputFileName = "input.yaml"
+	// ExpectedFileName is the name of the ResourceList expected from the
+	// function in a test case directory.
+	ExpectedFileName = "expected.yaml"
+
+	// UpdateGoldenEnv is the env var which, if set to "true", makes
+	// RunGoldenTests write the output of the function to the expected
+	// files instead of comparing them.
+	UpdateGoldenEnv = "KPT_FN_UPDATE_GOLDEN"
+)
+
+// RunGoldenTests runs the function on each test case directory in basedir
+// holding an input.yaml file, and compares the resulting ResourceList,
+// including the results of failed functions, with its expected.yaml file.
+func RunGoldenTests(t *testing.T, basedir string, p fn.ResourceListProcessor) {
+	dirs, err := os.ReadDir(basedir)
+	if err != nil {
+		t.Fatalf("cannot read test cases: %v", err)
+	}
+	update := os.Getenv(UpdateGoldenEnv) == "true"
+	for _, d := range dirs {
+		dir := filepath.Join(basedir, d.Name())
+		if !d.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, InputFileName)); err != nil {
+			continue
+		}
+		t.Run(d.Name(), func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join(dir, InputFileName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := &bytes.Buffer{}
+			// a failed function still writes its results.
+			_ = fn.Execute(p, bytes.NewReader(input), out)
+
+			expectedPath := filepath.Join(dir, ExpectedFileName)
+			if update {
+				if err := os.WriteFile(expectedPath, out.Bytes(), 0600); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(expectedPath)
+			if err != nil {
+				t.Fatalf("cannot read the expected output, run the test with %s=true to write it: %v", UpdateGoldenEnv, err)
+			}
+			assert.Equal(t, string(expected), out.String())
+		})
+	}
+}
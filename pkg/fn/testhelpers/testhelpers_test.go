@@ -0,0 +1,45 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testhelpers
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/pkg/fn"
+)
+
+// setAppLabel sets the app label from the functionConfig on the
+// deployments.
+func setAppLabel(rl *fn.ResourceList) (bool, error) {
+	if rl.FunctionConfig == nil {
+		rl.AddResult("missing functionConfig", fn.Error, nil)
+		return false, nil
+	}
+	app, _, err := rl.FunctionConfig.NestedString("data", "app")
+	if err != nil {
+		return false, err
+	}
+	for _, o := range rl.Items.Where(fn.IsGVK("apps", "v1", "Deployment")) {
+		if err := o.SetLabel("app", app); err != nil {
+			return false, err
+		}
+		rl.AddResult("set the app label", fn.Info, o)
+	}
+	return true, nil
+}
+
+func TestRunGoldenTests(t *testing.T) {
+	RunGoldenTests(t, "testdata", fn.ResourceListProcessorFunc(setAppLabel))
+}
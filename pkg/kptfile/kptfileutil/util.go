@@ -224,6 +224,28 @@ func UpdateUpstreamLockFromGit(path string, spec *git.RepoSpec) error {
 	return nil
 }
 
+// UpdateUpstreamLockFromOCI updates the upstreamLock of the package specified
+// by path to the image that was pulled, pinned by digest.
+func UpdateUpstreamLockFromOCI(path string, image string) error {
+	const op errors.Op = "kptfileutil.UpdateUpstreamLockFromOCI"
+	kpgfile, err := pkg.ReadKptfile(filesys.FileSystemOrOnDisk{}, path)
+	if err != nil {
+		return errors.E(op, types.UniquePath(path), err)
+	}
+
+	kpgfile.UpstreamLock = &kptfilev1.UpstreamLock{
+		Type: kptfilev1.OciOrigin,
+		Oci: &kptfilev1.OciLock{
+			Image: image,
+		},
+	}
+	err = WriteFile(path, kpgfile)
+	if err != nil {
+		return errors.E(op, types.UniquePath(path), err)
+	}
+	return nil
+}
+
 // merge merges the Kptfiles from various sources and updates localKf with output
 // please refer to https://github.com/GoogleContainerTools/kpt/blob/main/docs/design-docs/03-pipeline-merge.md
 // for related design
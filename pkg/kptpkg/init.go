@@ -47,6 +47,10 @@ type InitOptions struct {
 	Desc     string
 	Keywords []string
 	Site     string
+	// Template is the name of the template the package is scaffolded
+	// from, see InitTemplateNames. If empty, only the Kptfile, README and
+	// package context are written.
+	Template string
 }
 
 // DefaultInitilizer implements Initializer interface.
@@ -74,6 +78,19 @@ func (i *DefaultInitializer) Initialize(
 		return errors.Errorf("%s does not exist", p.UniquePath)
 	}
 
+	var tmpl *initTemplate
+	if opts.Template != "" {
+		t, found := initTemplates[opts.Template]
+		if !found {
+			return errors.Errorf("unknown template %q, must be one of %s", opts.Template, strings.Join(InitTemplateNames(), ", "))
+		}
+		tmpl = &t
+		// The pipeline of the template is only written to a new Kptfile.
+		if fsys.Exists(filepath.Join(up, kptfilev1.KptFileName)) {
+			return errors.Errorf("%s already has a Kptfile, --template can only be used for a new package", opts.RelPath)
+		}
+	}
+
 	pr := printer.FromContextOrDie(ctx)
 
 	if !fsys.Exists(filepath.Join(up, kptfilev1.KptFileName)) {
@@ -97,6 +114,10 @@ func (i *DefaultInitializer) Initialize(
 			},
 		}
 
+		if tmpl != nil {
+			k.Pipeline = tmpl.pipeline(pkgName)
+		}
+
 		// serialize the gvk when writing the Kptfile
 		k.Kind = kptfilev1.TypeMeta.Kind
 		k.APIVersion = kptfilev1.TypeMeta.APIVersion
@@ -128,6 +149,10 @@ func (i *DefaultInitializer) Initialize(
 			"Name":        pkgName,
 			"Description": opts.Desc,
 		}
+		if tmpl != nil {
+			templateData["Template"] = opts.Template
+			templateData["TemplateDescription"] = tmpl.description
+		}
 
 		err = t.Execute(buff, templateData)
 		if err != nil {
@@ -150,6 +175,20 @@ func (i *DefaultInitializer) Initialize(
 			return err
 		}
 	}
+
+	if tmpl != nil {
+		for _, fileName := range tmpl.fileNames() {
+			path := filepath.Join(up, fileName)
+			if fsys.Exists(path) {
+				continue
+			}
+			pr.Printf("writing %s\n", filepath.Join(opts.RelPath, fileName))
+			content := strings.ReplaceAll(tmpl.files[fileName], "NAME", pkgName)
+			if err := fsys.WriteFile(path, []byte(content)); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -160,6 +199,10 @@ var manTemplate = `# {{.Name}}
 
 ## Description
 {{.Description}}
+{{- if .Template}}
+
+This package was scaffolded from the {{.Template}} template. {{.TemplateDescription}}
+{{- end}}
 
 ## Usage
 
@@ -0,0 +1,172 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kptpkg
+
+import (
+	"sort"
+
+	"github.com/GoogleContainerTools/kpt/internal/builtins"
+	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
+)
+
+const (
+	setNamespaceImage = "gcr.io/kpt-fn/set-namespace:v0.4.1"
+	setLabelsImage    = "gcr.io/kpt-fn/set-labels:v0.1.5"
+	kubevalImage      = "gcr.io/kpt-fn/kubeval:v0.3"
+)
+
+// initTemplate is a package layout `kpt pkg init --template` scaffolds, in
+// addition to the Kptfile, README and package context every package gets.
+type initTemplate struct {
+	// description is added to the README of the package.
+	description string
+	// pipeline returns the pipeline of the Kptfile for the package name.
+	pipeline func(name string) *kptfilev1.Pipeline
+	// files are the resources of the package by file name, with NAME
+	// replaced with the package name.
+	files map[string]string
+}
+
+var initTemplates = map[string]initTemplate{
+	"deployment": {
+		description: "A Deployment and the Service exposing it. The resources are labeled with the app name and validated against their schemas.",
+		pipeline: func(name string) *kptfilev1.Pipeline {
+			return &kptfilev1.Pipeline{
+				Mutators: []kptfilev1.Function{
+					{Image: setLabelsImage, ConfigMap: map[string]string{"app": name}},
+				},
+				Validators: []kptfilev1.Function{
+					{Image: kubevalImage},
+				},
+			}
+		},
+		files: map[string]string{
+			"deployment.yaml": deploymentTemplate,
+			"service.yaml":    serviceTemplate,
+		},
+	},
+	"namespace-provisioning": {
+		description: "A Namespace and its ResourceQuota. The namespace is set from the name in " + builtins.PkgContextFile + ".",
+		pipeline: func(string) *kptfilev1.Pipeline {
+			return &kptfilev1.Pipeline{
+				Mutators: []kptfilev1.Function{
+					{Image: setNamespaceImage, ConfigPath: builtins.PkgContextFile},
+				},
+			}
+		},
+		files: map[string]string{
+			"namespace.yaml":     namespaceTemplate,
+			"resourcequota.yaml": resourceQuotaTemplate,
+		},
+	},
+	"app-with-pipeline": {
+		description: "A Deployment with its configuration and Service. The namespace is set from the name in " + builtins.PkgContextFile + ", and the resources are labeled with the app name and validated against their schemas.",
+		pipeline: func(name string) *kptfilev1.Pipeline {
+			return &kptfilev1.Pipeline{
+				Mutators: []kptfilev1.Function{
+					{Image: setNamespaceImage, ConfigPath: builtins.PkgContextFile},
+					{Image: setLabelsImage, ConfigMap: map[string]string{"app": name}},
+				},
+				Validators: []kptfilev1.Function{
+					{Image: kubevalImage},
+				},
+			}
+		},
+		files: map[string]string{
+			"configmap.yaml":  configMapTemplate,
+			"deployment.yaml": deploymentTemplate,
+			"service.yaml":    serviceTemplate,
+		},
+	},
+}
+
+// InitTemplateNames returns the names of the templates a package can be
+// initialized with.
+func InitTemplateNames() []string {
+	var names []string
+	for name := range initTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fileNames returns the file names of the resources, sorted.
+func (t initTemplate) fileNames() []string {
+	var names []string
+	for name := range t.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var deploymentTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: NAME
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: NAME
+  template:
+    metadata:
+      labels:
+        app: NAME
+    spec:
+      containers:
+      - name: NAME
+        image: nginx:1.25
+        ports:
+        - containerPort: 80
+`
+
+var serviceTemplate = `apiVersion: v1
+kind: Service
+metadata:
+  name: NAME
+spec:
+  selector:
+    app: NAME
+  ports:
+  - port: 80
+    targetPort: 80
+`
+
+var configMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: NAME-config
+data:
+  LOG_LEVEL: info
+`
+
+var namespaceTemplate = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: example
+`
+
+var resourceQuotaTemplate = `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: default
+  namespace: example
+spec:
+  hard:
+    cpu: "10"
+    memory: 10Gi
+`
@@ -174,8 +174,9 @@ func KubectlDeleteNamespace(t *testing.T, name string) {
 	t.Logf("output: %v", string(out))
 }
 
-func RegisterRepository(t *testing.T, repoURL, namespace, name string) {
-	cmd := exec.Command("kpt", "alpha", "repo", "register", "--namespace", namespace, "--name", name, repoURL)
+func RegisterRepository(t *testing.T, repoURL, namespace, name string, args ...string) {
+	args = append([]string{"alpha", "repo", "register", "--namespace", namespace, "--name", name, repoURL}, args...)
+	cmd := exec.Command("kpt", args...)
 	t.Logf("running command %v", strings.Join(cmd.Args, " "))
 	out, err := cmd.CombinedOutput()
 	if err != nil {
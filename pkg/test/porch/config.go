@@ -36,6 +36,11 @@ type Command struct {
 	ExitCode int `yaml:"exitCode,omitempty"`
 	// Yaml indicates that stdout is yaml and the test will reformat it for stable ordering
 	Yaml bool `yaml:"yaml,omitempty"`
+	// Golden is the directory, relative to the test case, holding the package
+	// the command writes to stdout as a ResourceList, like `kpt alpha rpkg
+	// pull` does. If set, the package is compared against it instead of
+	// against StdOut.
+	Golden string `yaml:"golden,omitempty"`
 }
 
 type TestCaseConfig struct {
@@ -46,6 +51,9 @@ type TestCaseConfig struct {
 	ConfigFile string `yaml:"-"`
 	// Repository is the name of the k8s Repository resource to register the default Git repo.
 	Repository string `yaml:"repository,omitempty"`
+	// GitAuth are the credentials the default Git repo requires. It is only
+	// enforced by the in-process git server of hermetic runs.
+	GitAuth *GitAuth `yaml:"gitAuth,omitempty"`
 	// Commands is a list of kpt commands to be executed by the test.
 	Commands []Command `yaml:"commands,omitempty"`
 	// Skip the test? If the value is not empty, it will be used as a message with which to skip the test.
@@ -0,0 +1,412 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/otiai10/copy"
+)
+
+// fixtureSignature is the author and committer of all commits created when
+// loading fixtures. Its date is fixed so that the resulting commit SHAs are
+// stable across runs.
+var fixtureSignature = object.Signature{
+	Name:  "Kpt",
+	Email: "kpt@kpt.dev",
+	When:  time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// GitServer is an in-process git server speaking the smart HTTP protocol.
+// Repositories are created on first access, mirroring the behavior of the
+// in-cluster test git server, so tests can register any repository URL.
+// The protocol is implemented with go-git, so no git binary is needed. Like
+// go-git, the server doesn't support shallow fetches.
+type GitServer struct {
+	// URL is the base URL of the server. Repositories are served at
+	// URL + "/" + name.
+	URL string
+
+	root      string
+	transport transport.Transport
+	server    *httptest.Server
+
+	// repoMu serializes all access to the repositories.
+	repoMu sync.Mutex
+	mu     sync.Mutex
+	auth   map[string]GitAuth
+}
+
+// GitAuth are the credentials a repository of the git server requires. The
+// zero value allows anonymous access.
+type GitAuth struct {
+	// Username and Password require basic auth.
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// Token requires a token, sent either as a bearer token or as the
+	// password of basic auth with any username, like the personal access
+	// tokens of git hosting services.
+	Token string `yaml:"token,omitempty"`
+}
+
+// IsAnonymous returns true if no credentials are required.
+func (a GitAuth) IsAnonymous() bool {
+	return a.Username == "" && a.Password == "" && a.Token == ""
+}
+
+// authorized returns true if the request carries the required credentials.
+func (a GitAuth) authorized(r *http.Request) bool {
+	if a.IsAnonymous() {
+		return true
+	}
+	if a.Token != "" {
+		if r.Header.Get("Authorization") == "Bearer "+a.Token {
+			return true
+		}
+		_, password, ok := r.BasicAuth()
+		return ok && password == a.Token
+	}
+	username, password, ok := r.BasicAuth()
+	return ok && username == a.Username && password == a.Password
+}
+
+// RegisterArgs returns the flags of `kpt alpha repo register` passing the
+// credentials.
+func (a GitAuth) RegisterArgs() []string {
+	switch {
+	case a.Token != "":
+		return []string{"--repo-basic-username=token", "--repo-basic-password=" + a.Token}
+	case !a.IsAnonymous():
+		return []string{"--repo-basic-username=" + a.Username, "--repo-basic-password=" + a.Password}
+	}
+	return nil
+}
+
+// StartGitServer starts a git server serving repositories out of a temporary
+// directory. The server listens on addr; if addr is empty, a random port on
+// the loopback interface is used. The server is stopped when the test ends.
+func StartGitServer(t *testing.T, addr string) *GitServer {
+	gs := &GitServer{
+		root: t.TempDir(),
+		auth: map[string]GitAuth{},
+	}
+	gs.transport = server.NewServer(server.NewFilesystemLoader(osfs.New(gs.root)))
+	gs.server = httptest.NewUnstartedServer(http.HandlerFunc(gs.serveHTTP))
+	if addr != "" {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Fatalf("Failed to listen on %q: %v", addr, err)
+		}
+		gs.server.Listener.Close()
+		gs.server.Listener = l
+	}
+	gs.server.Start()
+	gs.URL = gs.server.URL
+	t.Cleanup(gs.server.Close)
+
+	t.Logf("Started in-process git server at %s serving %s", gs.URL, gs.root)
+	return gs
+}
+
+// RepoURL returns the URL under which the named repository is served.
+func (gs *GitServer) RepoURL(name string) string {
+	return gs.URL + "/" + name
+}
+
+// SetRepoAuth sets the credentials required to access the named repository,
+// so tests sharing the server can each cover another credential type.
+func (gs *GitServer) SetRepoAuth(name string, auth GitAuth) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.auth[repoName(name)] = auth
+}
+
+// LoadFixture creates the named repository and populates it from the
+// fixture directory. The fixture directory contains one directory per
+// package, each of which contains one directory per revision:
+//
+//	<fixture>/<package>/<revision>/...
+//
+// Revisions are committed in lexical order to the main branch and tagged
+// as <package>/<revision>, so main ends up holding the latest revision of
+// every package, just like a typical blueprint repository.
+func (gs *GitServer) LoadFixture(t *testing.T, name, fixture string) {
+	type revision struct {
+		pkg, rev string
+	}
+	var revisions []revision
+
+	pkgs, err := os.ReadDir(fixture)
+	if err != nil {
+		t.Fatalf("Failed to read fixture directory %q: %v", fixture, err)
+	}
+	for _, p := range pkgs {
+		if !p.IsDir() {
+			continue
+		}
+		revs, err := os.ReadDir(filepath.Join(fixture, p.Name()))
+		if err != nil {
+			t.Fatalf("Failed to read fixture package %q: %v", p.Name(), err)
+		}
+		for _, r := range revs {
+			if r.IsDir() {
+				revisions = append(revisions, revision{pkg: p.Name(), rev: r.Name()})
+			}
+		}
+	}
+	sort.SliceStable(revisions, func(i, j int) bool {
+		if revisions[i].rev != revisions[j].rev {
+			return revisions[i].rev < revisions[j].rev
+		}
+		return revisions[i].pkg < revisions[j].pkg
+	})
+
+	gs.repoMu.Lock()
+	defer gs.repoMu.Unlock()
+	dir, err := gs.ensureRepo(name)
+	if err != nil {
+		t.Fatalf("Failed to create repository %q: %v", name, err)
+	}
+
+	// commit straight into the served repository from a scratch worktree.
+	work := t.TempDir()
+	storage := filesystem.NewStorage(osfs.New(dir), cache.NewObjectLRUDefault())
+	repo, err := git.Open(storage, osfs.New(work))
+	if err != nil {
+		t.Fatalf("Failed to open repository %q: %v", name, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree of repository %q: %v", name, err)
+	}
+	for _, r := range revisions {
+		dst := filepath.Join(work, r.pkg)
+		if err := os.RemoveAll(dst); err != nil {
+			t.Fatalf("Failed to clear package directory %q: %v", dst, err)
+		}
+		if err := copy.Copy(filepath.Join(fixture, r.pkg, r.rev), dst); err != nil {
+			t.Fatalf("Failed to copy fixture %s/%s: %v", r.pkg, r.rev, err)
+		}
+		if err := wt.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+			t.Fatalf("Failed to stage fixture %s/%s: %v", r.pkg, r.rev, err)
+		}
+		commit, err := wt.Commit(fmt.Sprintf("%s %s", r.pkg, r.rev), &git.CommitOptions{
+			Author:            &fixtureSignature,
+			Committer:         &fixtureSignature,
+			AllowEmptyCommits: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to commit fixture %s/%s: %v", r.pkg, r.rev, err)
+		}
+		if _, err := repo.CreateTag(r.pkg+"/"+r.rev, commit, nil); err != nil {
+			t.Fatalf("Failed to tag fixture %s/%s: %v", r.pkg, r.rev, err)
+		}
+	}
+}
+
+func (gs *GitServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	name, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	name = repoName(name)
+	if name == "" || name == "." || name == ".." {
+		http.NotFound(w, r)
+		return
+	}
+	gs.mu.Lock()
+	auth := gs.auth[name]
+	gs.mu.Unlock()
+	if !auth.authorized(r) {
+		if auth.Token != "" {
+			w.Header().Add("WWW-Authenticate", `Bearer realm="git"`)
+		}
+		w.Header().Add("WWW-Authenticate", `Basic realm="git"`)
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	gs.repoMu.Lock()
+	defer gs.repoMu.Unlock()
+	if _, err := gs.ensureRepo(name); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ep, err := transport.NewEndpoint("/" + name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && rest == "info/refs":
+		gs.advertiseRefs(w, r, ep, r.URL.Query().Get("service"))
+	case r.Method == http.MethodPost && rest == transport.UploadPackServiceName:
+		gs.uploadPack(w, r, ep)
+	case r.Method == http.MethodPost && rest == transport.ReceivePackServiceName:
+		gs.receivePack(w, r, ep)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// advertiseRefs answers the initial request of a smart HTTP client with
+// the references of the repository.
+func (gs *GitServer) advertiseRefs(w http.ResponseWriter, r *http.Request, ep *transport.Endpoint, service string) {
+	var session transport.Session
+	var err error
+	switch service {
+	case transport.UploadPackServiceName:
+		session, err = gs.transport.NewUploadPackSession(ep, nil)
+	case transport.ReceivePackServiceName:
+		session, err = gs.transport.NewReceivePackSession(ep, nil)
+	default:
+		http.Error(w, "only the smart HTTP protocol is supported", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer session.Close()
+
+	refs, err := session.AdvertisedReferencesContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	refs.Prefix = [][]byte{[]byte("# service=" + service), pktline.Flush}
+
+	w.Header().Set("Content-Type", "application/x-"+service+"-advertisement")
+	w.Header().Set("Cache-Control", "no-cache")
+	_ = refs.Encode(w)
+}
+
+func (gs *GitServer) uploadPack(w http.ResponseWriter, r *http.Request, ep *transport.Endpoint) {
+	body, err := requestBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+	req := packp.NewUploadPackRequest()
+	if err := req.Decode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session, err := gs.transport.NewUploadPackSession(ep, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer session.Close()
+	resp, err := session.UploadPack(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer resp.Close()
+
+	w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+	w.Header().Set("Cache-Control", "no-cache")
+	_ = resp.Encode(w)
+}
+
+func (gs *GitServer) receivePack(w http.ResponseWriter, r *http.Request, ep *transport.Endpoint) {
+	body, err := requestBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+	req := packp.NewReferenceUpdateRequest()
+	if err := req.Decode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session, err := gs.transport.NewReceivePackSession(ep, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer session.Close()
+	// a failed update is reported to the client in the status, if it asked
+	// for one.
+	status, err := session.ReceivePack(r.Context(), req)
+	if status == nil && err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-git-receive-pack-result")
+	w.Header().Set("Cache-Control", "no-cache")
+	if status != nil {
+		_ = status.Encode(w)
+	}
+}
+
+// requestBody returns the body of the request, decompressing it if the
+// client compressed it like git does for large requests.
+func requestBody(r *http.Request) (io.ReadCloser, error) {
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		return r.Body, nil
+	}
+	return gzip.NewReader(r.Body)
+}
+
+// ensureRepo creates an empty bare repository with main as its default
+// branch, unless it already exists, and returns its path on disk. The
+// caller must hold repoMu.
+func (gs *GitServer) ensureRepo(name string) (string, error) {
+	dir := filepath.Join(gs.root, repoName(name))
+	if _, err := os.Stat(dir); err == nil {
+		return dir, nil
+	}
+	if _, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+		Bare:        true,
+	}); err != nil {
+		return "", fmt.Errorf("failed to initialize repository %q: %w", name, err)
+	}
+	return dir, nil
+}
+
+// repoName returns the name a repository is stored under. Like git
+// http-backend, the server serves a repository both with and without the
+// .git suffix, as kpt drops the suffix from package URLs.
+func repoName(name string) string {
+	return strings.TrimSuffix(name, ".git")
+}
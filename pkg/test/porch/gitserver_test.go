@@ -0,0 +1,275 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func writeFixture(t *testing.T, files map[string]string) string {
+	fixture := t.TempDir()
+	for path, content := range files {
+		p := filepath.Join(fixture, path)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fixture
+}
+
+// listRefs returns the names of the references of the remote repository.
+func listRefs(t *testing.T, url string, auth transport.AuthMethod) ([]string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, err
+	}
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.Name().String())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func TestGitServerLoadFixture(t *testing.T) {
+	fixture := writeFixture(t, map[string]string{
+		"basens/v1/Kptfile":        "kind: Kptfile\n",
+		"basens/v1/removed.yaml":   "kind: ConfigMap\n",
+		"basens/v2/Kptfile":        "kind: Kptfile\n",
+		"basens/v2/namespace.yaml": "kind: Namespace\n",
+		"empty/v1/Kptfile":         "kind: Kptfile\n",
+	})
+
+	gs := StartGitServer(t, "")
+	gs.LoadFixture(t, "blueprints.git", fixture)
+
+	refs, err := listRefs(t, gs.RepoURL("blueprints.git"), nil)
+	if err != nil {
+		t.Fatalf("Failed to list references: %v", err)
+	}
+	for _, ref := range []string{"refs/heads/main", "refs/tags/basens/v1", "refs/tags/basens/v2", "refs/tags/empty/v1"} {
+		if !contains(refs, ref) {
+			t.Errorf("expected ref %q in %v", ref, refs)
+		}
+	}
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	if _, err := git.PlainClone(clone, false, &git.CloneOptions{URL: gs.RepoURL("blueprints.git")}); err != nil {
+		t.Fatalf("Failed to clone: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "basens", "namespace.yaml")); err != nil {
+		t.Errorf("expected main to contain the latest revision of basens: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "basens", "removed.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected files removed in the latest revision of basens to be gone from main: %v", err)
+	}
+}
+
+func TestGitServerLoadFixtureIsStable(t *testing.T) {
+	fixture := writeFixture(t, map[string]string{
+		"basens/v1/Kptfile": "kind: Kptfile\n",
+	})
+
+	var heads []string
+	for i := 0; i < 2; i++ {
+		gs := StartGitServer(t, "")
+		gs.LoadFixture(t, "blueprints.git", fixture)
+		repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{URL: gs.RepoURL("blueprints.git")})
+		if err != nil {
+			t.Fatalf("Failed to clone: %v", err)
+		}
+		head, err := repo.Head()
+		if err != nil {
+			t.Fatalf("Failed to resolve HEAD: %v", err)
+		}
+		heads = append(heads, head.Hash().String())
+	}
+	if heads[0] != heads[1] {
+		t.Errorf("expected the same commit when loading the same fixture, got %v", heads)
+	}
+}
+
+func TestGitServerCreatesRepositories(t *testing.T) {
+	gs := StartGitServer(t, "")
+
+	refs, err := listRefs(t, gs.RepoURL("new-repo"), nil)
+	if err != nil {
+		t.Fatalf("Failed to list references of a new repository: %v", err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("expected new repository to be empty, got %v", refs)
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitEmpty(t, repo, "initial")
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{gs.RepoURL("new-repo")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Push(&git.PushOptions{RefSpecs: []config.RefSpec{"refs/heads/master:refs/heads/main"}}); err != nil {
+		t.Fatalf("Failed to push: %v", err)
+	}
+
+	refs, err = listRefs(t, gs.RepoURL("new-repo"), nil)
+	if err != nil {
+		t.Fatalf("Failed to list references after push: %v", err)
+	}
+	if !contains(refs, "refs/heads/main") {
+		t.Errorf("expected pushed branch in %v", refs)
+	}
+}
+
+func TestGitServerAuth(t *testing.T) {
+	gs := StartGitServer(t, "")
+	gs.SetRepoAuth("basic", GitAuth{Username: "user", Password: "secret"})
+	gs.SetRepoAuth("token", GitAuth{Token: "t0ken"})
+
+	testCases := map[string]struct {
+		repo    string
+		auth    transport.AuthMethod
+		wantErr bool
+	}{
+		"anonymous": {
+			repo: "anonymous",
+		},
+		"basic auth": {
+			repo: "basic",
+			auth: &githttp.BasicAuth{Username: "user", Password: "secret"},
+		},
+		"basic auth with wrong password": {
+			repo:    "basic",
+			auth:    &githttp.BasicAuth{Username: "user", Password: "wrong"},
+			wantErr: true,
+		},
+		"basic auth without credentials": {
+			repo:    "basic",
+			wantErr: true,
+		},
+		"bearer token": {
+			repo: "token",
+			auth: &githttp.TokenAuth{Token: "t0ken"},
+		},
+		"token as basic auth password": {
+			repo: "token",
+			auth: &githttp.BasicAuth{Username: "any", Password: "t0ken"},
+		},
+		"wrong token": {
+			repo:    "token",
+			auth:    &githttp.TokenAuth{Token: "wrong"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := listRefs(t, gs.RepoURL(tc.repo), tc.auth)
+			if tc.wantErr && err == nil {
+				t.Errorf("expected listing references of %q to fail", tc.repo)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Failed to list references of %q: %v", tc.repo, err)
+			}
+		})
+	}
+}
+
+// TestGitServerGitCLI checks that the server also works with the git
+// binary, which kpt uses to fetch packages.
+func TestGitServerGitCLI(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+	fixture := writeFixture(t, map[string]string{
+		"basens/v1/Kptfile": "kind: Kptfile\n",
+	})
+	gs := StartGitServer(t, "")
+	gs.LoadFixture(t, "blueprints.git", fixture)
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, "", "clone", gs.RepoURL("blueprints.git"), clone)
+	if _, err := os.Stat(filepath.Join(clone, "basens", "Kptfile")); err != nil {
+		t.Errorf("expected clone to contain basens: %v", err)
+	}
+
+	runGit(t, clone, "commit", "--allow-empty", "--message", "update")
+	runGit(t, clone, "push", "origin", "main")
+
+	// fetch into the existing clone, which negotiates with the commits the
+	// clone already has.
+	other := filepath.Join(t.TempDir(), "other")
+	runGit(t, "", "clone", gs.RepoURL("blueprints.git"), other)
+	runGit(t, clone, "commit", "--allow-empty", "--message", "another update")
+	runGit(t, clone, "push", "origin", "main")
+	runGit(t, other, "pull", "--ff-only")
+	if got, want := runGit(t, other, "log", "-1", "--format=%s"), "another update"; got != want {
+		t.Errorf("expected latest commit %q after pull, got %q", want, got)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	args = append([]string{"-c", "user.name=Kpt", "-c", "user.email=kpt@kpt.dev"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, string(out))
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commitEmpty(t *testing.T, repo *git.Repository, msg string) {
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "Kpt", Email: "kpt@kpt.dev", When: time.Now()}
+	if _, err := wt.Commit(msg, &git.CommitOptions{Author: sig, AllowEmptyCommits: true}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
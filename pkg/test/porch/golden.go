@@ -0,0 +1,192 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateGoldenFilesEnv can be set to update golden files instead of
+// comparing against them.
+const UpdateGoldenFilesEnv = "UPDATE_GOLDEN_FILES"
+
+// UpdateGoldenFiles can be set, typically from an -update flag of the test
+// binary, to update golden files instead of comparing against them.
+var UpdateGoldenFiles bool
+
+// ShouldUpdateGoldenFiles reports whether golden files should be rewritten
+// with the actual test output.
+func ShouldUpdateGoldenFiles() bool {
+	return UpdateGoldenFiles || os.Getenv(UpdateGoldenFilesEnv) != ""
+}
+
+// AssertPackageRevisionResourcesGolden compares the resources of a
+// PackageRevisionResources against the golden directory.
+func AssertPackageRevisionResourcesGolden(t *testing.T, prr *porchapi.PackageRevisionResources, goldenDir string) {
+	t.Helper()
+	AssertResourcesGolden(t, prr.Spec.Resources, goldenDir)
+}
+
+// AssertDirGolden compares the contents of a local directory, such as the
+// output of `kpt alpha rpkg pull`, against the golden directory.
+func AssertDirGolden(t *testing.T, dir, goldenDir string) {
+	t.Helper()
+	resources, err := readResources(dir)
+	if err != nil {
+		t.Fatalf("Failed to read package directory %q: %v", dir, err)
+	}
+	AssertResourcesGolden(t, resources, goldenDir)
+}
+
+// AssertResourcesGolden compares package resources, keyed by file path,
+// against the files in goldenDir. YAML files are normalized before the
+// comparison so that field and document ordering doesn't matter. If golden
+// files are being updated, goldenDir is replaced with the normalized
+// resources instead.
+func AssertResourcesGolden(t *testing.T, resources map[string]string, goldenDir string) {
+	t.Helper()
+
+	got := make(map[string]string, len(resources))
+	for path, content := range resources {
+		got[filepath.ToSlash(path)] = normalizeResource(t, path, content)
+	}
+
+	if ShouldUpdateGoldenFiles() {
+		writeGolden(t, got, goldenDir)
+		return
+	}
+
+	golden, err := readResources(goldenDir)
+	if err != nil {
+		t.Fatalf("Failed to read golden directory %q: %v", goldenDir, err)
+	}
+	want := make(map[string]string, len(golden))
+	for path, content := range golden {
+		want[path] = normalizeResource(t, path, content)
+	}
+
+	for _, path := range sortedKeys(want, got) {
+		w, inWant := want[path]
+		g, inGot := got[path]
+		switch {
+		case !inGot:
+			t.Errorf("golden file %q is missing from the package", path)
+		case !inWant:
+			t.Errorf("package contains file %q which is not in %s", path, goldenDir)
+		case w != g:
+			t.Errorf("unexpected content of %q (-want, +got): %s", path, cmp.Diff(w, g))
+		}
+	}
+}
+
+// normalizeResource returns a canonical form of YAML content by decoding
+// each document and encoding it again with sorted keys. Other files are
+// returned as is.
+func normalizeResource(t *testing.T, path, content string) string {
+	if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" && filepath.Base(path) != "Kptfile" {
+		return content
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			// Not valid yaml; compare verbatim.
+			return content
+		}
+		if doc == nil {
+			continue
+		}
+		if err := encoder.Encode(doc); err != nil {
+			t.Fatalf("Failed to encode %q: %v", path, err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatalf("Failed to encode %q: %v", path, err)
+	}
+	return out.String()
+}
+
+func readResources(dir string) (map[string]string, error) {
+	resources := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		resources[filepath.ToSlash(rel)] = string(b)
+		return nil
+	})
+	return resources, err
+}
+
+func writeGolden(t *testing.T, resources map[string]string, goldenDir string) {
+	if err := os.RemoveAll(goldenDir); err != nil {
+		t.Fatalf("Failed to remove golden directory %q: %v", goldenDir, err)
+	}
+	for path, content := range resources {
+		p := filepath.Join(goldenDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("Failed to create golden directory for %q: %v", p, err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write golden file %q: %v", p, err)
+		}
+	}
+	t.Logf("Updated golden directory %s", goldenDir)
+}
+
+func sortedKeys(maps ...map[string]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
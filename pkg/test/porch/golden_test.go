@@ -0,0 +1,83 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	porchapi "github.com/GoogleContainerTools/kpt/porch/api/porch/v1alpha1"
+)
+
+func TestNormalizeResource(t *testing.T) {
+	testCases := map[string]struct {
+		path string
+		a, b string
+	}{
+		"field order": {
+			path: "namespace.yaml",
+			a:    "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: example\n",
+			b:    "kind: Namespace\nmetadata:\n    name: example\napiVersion: v1\n",
+		},
+		"kptfile": {
+			path: "Kptfile",
+			a:    "apiVersion: kpt.dev/v1\nkind: Kptfile\n",
+			b:    "kind: Kptfile\napiVersion: kpt.dev/v1\n",
+		},
+		"empty documents": {
+			path: "resources.yml",
+			a:    "a: 1\n---\nb: 2\n",
+			b:    "---\na: 1\n---\n---\nb: 2\n",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			if got, want := normalizeResource(t, tc.path, tc.b), normalizeResource(t, tc.path, tc.a); got != want {
+				t.Errorf("expected normalized content to match; got %q, want %q", got, want)
+			}
+		})
+	}
+
+	if got, want := normalizeResource(t, "README.md", "b: 1\na: 2\n"), "b: 1\na: 2\n"; got != want {
+		t.Errorf("expected non-yaml file to be unchanged; got %q, want %q", got, want)
+	}
+}
+
+func TestAssertPackageRevisionResourcesGolden(t *testing.T) {
+	goldenDir := filepath.Join(t.TempDir(), "golden")
+	prr := &porchapi.PackageRevisionResources{
+		Spec: porchapi.PackageRevisionResourcesSpec{
+			Resources: map[string]string{
+				"Kptfile":        "apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: basens\n",
+				"ns/ns.yaml":     "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: example\n",
+				"docs/README.md": "# basens\n",
+			},
+		},
+	}
+
+	t.Setenv(UpdateGoldenFilesEnv, "true")
+	AssertPackageRevisionResourcesGolden(t, prr, goldenDir)
+	t.Setenv(UpdateGoldenFilesEnv, "")
+
+	if _, err := os.Stat(filepath.Join(goldenDir, "ns", "ns.yaml")); err != nil {
+		t.Fatalf("expected golden file to be written: %v", err)
+	}
+
+	// Reordered fields must still match the golden files.
+	prr.Spec.Resources["Kptfile"] = "metadata:\n  name: basens\nkind: Kptfile\napiVersion: kpt.dev/v1\n"
+	AssertPackageRevisionResourcesGolden(t, prr, goldenDir)
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// RemoteGitProviderEnv selects a git hosting service, github or gitlab,
+	// on which the test repositories are created instead of the test git
+	// server.
+	RemoteGitProviderEnv = "KPT_E2E_GIT_PROVIDER"
+	// RemoteGitTokenEnv is the access token used to create and delete the
+	// repositories, and to register them with porch.
+	RemoteGitTokenEnv = "KPT_E2E_GIT_TOKEN"
+	// RemoteGitOrgEnv is the organization, or GitLab group, owning the
+	// repositories. If empty, they are owned by the user of the token.
+	RemoteGitOrgEnv = "KPT_E2E_GIT_ORG"
+	// RemoteGitAPIURLEnv optionally overrides the API URL of the provider,
+	// e.g. for GitHub Enterprise or a self-managed GitLab.
+	RemoteGitAPIURLEnv = "KPT_E2E_GIT_API_URL"
+
+	GitHubProvider = "github"
+	GitLabProvider = "gitlab"
+)
+
+// RemoteGitProvider creates test repositories on a git hosting service, so
+// that tests run against its authentication and behavior.
+type RemoteGitProvider struct {
+	// Provider is either GitHubProvider or GitLabProvider.
+	Provider string
+	// APIURL is the base URL of the REST API of the provider.
+	APIURL string
+	// Org owns the repositories. If empty, the user of the token does.
+	Org string
+	// Token is an access token allowed to create and delete repositories.
+	Token string
+}
+
+// RemoteGitProviderFromEnv returns the provider configured in the
+// environment, or nil if RemoteGitProviderEnv is not set.
+func RemoteGitProviderFromEnv(t *testing.T) *RemoteGitProvider {
+	provider := os.Getenv(RemoteGitProviderEnv)
+	if provider == "" {
+		return nil
+	}
+	p := &RemoteGitProvider{
+		Provider: provider,
+		APIURL:   os.Getenv(RemoteGitAPIURLEnv),
+		Org:      os.Getenv(RemoteGitOrgEnv),
+		Token:    os.Getenv(RemoteGitTokenEnv),
+	}
+	if p.APIURL == "" {
+		switch provider {
+		case GitHubProvider:
+			p.APIURL = "https://api.github.com"
+		case GitLabProvider:
+			p.APIURL = "https://gitlab.com/api/v4"
+		}
+	}
+	if provider != GitHubProvider && provider != GitLabProvider {
+		t.Fatalf("Unsupported %s %q; must be %s or %s", RemoteGitProviderEnv, provider, GitHubProvider, GitLabProvider)
+	}
+	if p.Token == "" {
+		t.Fatalf("%s must be set when %s is set", RemoteGitTokenEnv, RemoteGitProviderEnv)
+	}
+	return p
+}
+
+// Auth returns the credentials with which the repositories are registered.
+func (p *RemoteGitProvider) Auth() GitAuth {
+	return GitAuth{Token: p.Token}
+}
+
+// CreateRepo creates an empty private repository named after name, with a
+// unique suffix so concurrent runs don't collide, and returns its clone URL.
+// The repository is deleted when the test ends.
+func (p *RemoteGitProvider) CreateRepo(t *testing.T, name string) string {
+	name = fmt.Sprintf("kpt-e2e-%s-%s", name, strconv.FormatInt(time.Now().UnixNano(), 36))
+
+	var cloneURL, deletePath string
+	switch p.Provider {
+	case GitHubProvider:
+		createPath := "/user/repos"
+		if p.Org != "" {
+			createPath = "/orgs/" + url.PathEscape(p.Org) + "/repos"
+		}
+		var repo struct {
+			FullName string `json:"full_name"`
+			CloneURL string `json:"clone_url"`
+		}
+		p.call(t, http.MethodPost, createPath, map[string]interface{}{"name": name, "private": true}, &repo)
+		cloneURL, deletePath = repo.CloneURL, "/repos/"+repo.FullName
+	case GitLabProvider:
+		body := map[string]interface{}{"name": name, "path": name, "visibility": "private"}
+		if p.Org != "" {
+			var group struct {
+				ID int `json:"id"`
+			}
+			p.call(t, http.MethodGet, "/namespaces/"+url.PathEscape(p.Org), nil, &group)
+			body["namespace_id"] = group.ID
+		}
+		var project struct {
+			ID            int    `json:"id"`
+			HTTPURLToRepo string `json:"http_url_to_repo"`
+		}
+		p.call(t, http.MethodPost, "/projects", body, &project)
+		cloneURL, deletePath = project.HTTPURLToRepo, "/projects/"+strconv.Itoa(project.ID)
+	}
+
+	t.Cleanup(func() {
+		p.call(t, http.MethodDelete, deletePath, nil, nil)
+	})
+	t.Logf("created %s repository %s", p.Provider, cloneURL)
+	return cloneURL
+}
+
+// call sends a request to the API of the provider and decodes the JSON
+// response into out, if not nil.
+func (p *RemoteGitProvider) call(t *testing.T, method, path string, in, out interface{}) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			t.Fatalf("Failed to encode %s request: %v", p.Provider, err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(p.APIURL, "/")+path, body)
+	if err != nil {
+		t.Fatalf("Failed to create %s request: %v", p.Provider, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Provider == GitLabProvider {
+		req.Header.Set("PRIVATE-TOKEN", p.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to call %s API %s %s: %v", p.Provider, method, path, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read %s API response: %v", p.Provider, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		t.Fatalf("%s API %s %s returned %s: %s", p.Provider, method, path, resp.Status, string(b))
+	}
+	if out != nil {
+		if err := json.Unmarshal(b, out); err != nil {
+			t.Fatalf("Failed to decode %s API response: %v", p.Provider, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The kpt Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRemoteGitProviderCreateRepo(t *testing.T) {
+	testCases := map[string]struct {
+		provider    string
+		org         string
+		authHeader  string
+		createPath  string
+		response    string
+		expectedURL string
+		deletePath  string
+	}{
+		"github org": {
+			provider:    GitHubProvider,
+			org:         "platkrm",
+			authHeader:  "Authorization",
+			createPath:  "/orgs/platkrm/repos",
+			response:    `{"full_name": "platkrm/repo", "clone_url": "https://github.com/platkrm/repo.git"}`,
+			expectedURL: "https://github.com/platkrm/repo.git",
+			deletePath:  "/repos/platkrm/repo",
+		},
+		"github user": {
+			provider:    GitHubProvider,
+			authHeader:  "Authorization",
+			createPath:  "/user/repos",
+			response:    `{"full_name": "me/repo", "clone_url": "https://github.com/me/repo.git"}`,
+			expectedURL: "https://github.com/me/repo.git",
+			deletePath:  "/repos/me/repo",
+		},
+		"gitlab group": {
+			provider:    GitLabProvider,
+			org:         "platkrm",
+			authHeader:  "Private-Token",
+			createPath:  "/projects",
+			response:    `{"id": 42, "http_url_to_repo": "https://gitlab.com/platkrm/repo.git"}`,
+			expectedURL: "https://gitlab.com/platkrm/repo.git",
+			deletePath:  "/projects/42",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []string
+			var created map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if !strings.HasSuffix(r.Header.Get(tc.authHeader), "secret") {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/namespaces/platkrm":
+					_, _ = w.Write([]byte(`{"id": 7}`))
+				case r.Method == http.MethodPost && r.URL.Path == tc.createPath:
+					if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(tc.response))
+				case r.Method == http.MethodDelete && r.URL.Path == tc.deletePath:
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			p := &RemoteGitProvider{Provider: tc.provider, APIURL: server.URL, Org: tc.org, Token: "secret"}
+			t.Run("create", func(t *testing.T) {
+				if got := p.CreateRepo(t, "basic"); got != tc.expectedURL {
+					t.Errorf("unexpected clone URL; got %q, want %q", got, tc.expectedURL)
+				}
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if name, _ := created["name"].(string); !strings.HasPrefix(name, "kpt-e2e-basic-") {
+				t.Errorf("unexpected repository name %q", name)
+			}
+			if tc.provider == GitLabProvider && created["namespace_id"] != float64(7) {
+				t.Errorf("expected the project to be created in the group, got namespace_id %v", created["namespace_id"])
+			}
+			if last := requests[len(requests)-1]; last != http.MethodDelete+" "+tc.deletePath {
+				t.Errorf("expected the repository to be deleted at the end of the test, got requests %v", requests)
+			}
+		})
+	}
+}
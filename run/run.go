@@ -26,6 +26,7 @@ import (
 
 	kptcommands "github.com/GoogleContainerTools/kpt/commands"
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/overview"
+	"github.com/GoogleContainerTools/kpt/internal/plugin"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/spf13/cobra"
@@ -204,3 +205,18 @@ func hideFlags(cmd *cobra.Command) {
 		hideFlags(child)
 	}
 }
+
+// RunPlugin executes the kpt plugin matching the command line arguments if
+// they don't refer to a builtin command. It returns the exit code of the
+// plugin, and false if no plugin was executed.
+func RunPlugin(ctx context.Context, cmd *cobra.Command, args []string) (int, bool) {
+	p, found := plugin.Lookup(cmd, args)
+	if !found {
+		return 0, false
+	}
+	exitCode, err := p.Run(ctx, version, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	return exitCode, true
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	docs "github.com/GoogleContainerTools/kpt/internal/docs/generated/fndocs"
@@ -17,7 +18,9 @@ import (
 	"github.com/GoogleContainerTools/kpt/internal/pkg"
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	"github.com/GoogleContainerTools/kpt/internal/util/pathutil"
+	fnresult "github.com/GoogleContainerTools/kpt/pkg/api/fnresult/v1"
 	kptfile "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/kptfile/kptfileutil"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
@@ -33,6 +36,9 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+// uidGIDPattern matches the uid[:gid] format of --user.
+var uidGIDPattern = regexp.MustCompile(`^[0-9]+(:[0-9]+)?$`)
+
 // GetEvalFnRunner returns a EvalFnRunner.
 func GetEvalFnRunner(ctx context.Context, parent string) *EvalFnRunner {
 	r := &EvalFnRunner{Ctx: ctx}
@@ -49,6 +55,9 @@ func GetEvalFnRunner(ctx context.Context, parent string) *EvalFnRunner {
 	r.Command = c
 	r.Command.Flags().StringVarP(&r.Dest, "output", "o", "",
 		fmt.Sprintf("output resources are written to provided location. Allowed values: %s|%s|<OUT_DIR_PATH>", cmdutil.Stdout, cmdutil.Unwrap))
+	r.Command.Flags().StringVar(&r.ResultsFormat, "results-format", "",
+		fmt.Sprintf("write the function results to stdout in a machine-readable format. Allowed values: %s|%s",
+			output.JSON, output.YAML))
 	r.Command.Flags().StringVarP(
 		&r.Image, "image", "i", "", "run this image as a function")
 	_ = r.Command.RegisterFlagCompletionFunc("image", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -85,15 +94,29 @@ func GetEvalFnRunner(ctx context.Context, parent string) *EvalFnRunner {
 		"a list of environment variables to be used by functions")
 	r.Command.Flags().BoolVar(
 		&r.AsCurrentUser, "as-current-user", false, "use the uid and gid that kpt is running with to run the function in the container")
+	r.Command.Flags().StringVar(
+		&r.User, "user", "", "run the function in the container with this uid and optional gid, in the format UID[:GID]")
+	r.Command.Flags().BoolVar(
+		&r.ReadOnlyRootfs, "read-only", false, "run the function in the container with a read-only root filesystem")
 
 	r.Command.Flags().Var(&r.RunnerOptions.ImagePullPolicy, "image-pull-policy",
 		"pull image before running the container "+r.RunnerOptions.ImagePullPolicy.HelpAllowedValues())
 	_ = r.Command.RegisterFlagCompletionFunc("image-pull-policy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return r.RunnerOptions.ImagePullPolicy.AllStrings(), cobra.ShellCompDirectiveDefault
 	})
+	r.Command.Flags().Var(&r.RunnerOptions.Runtime, "runtime",
+		"container runtime running container functions "+r.RunnerOptions.Runtime.HelpAllowedValues()+
+			". Defaults to the "+fnruntime.ContainerRuntimeEnv+" env var, or the first runtime found in PATH.")
+	_ = r.Command.RegisterFlagCompletionFunc("runtime", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return r.RunnerOptions.Runtime.AllStrings(), cobra.ShellCompDirectiveDefault
+	})
 
 	r.Command.Flags().BoolVar(
 		&r.RunnerOptions.AllowWasm, "allow-alpha-wasm", false, "allow alpha wasm functions to be run. If true, you can specify a wasm image with --image flag or a path to a wasm file (must have the .wasm file extension) with --exec flag.")
+	r.Command.Flags().BoolVar(
+		&r.RunnerOptions.PreferBuiltin, "prefer-builtin", r.RunnerOptions.PreferBuiltin,
+		"execute catalog functions with a builtin implementation compatible with their version without containers. "+
+			"Defaults to the "+fnruntime.PreferBuiltinEnv+" env var.")
 
 	// selector flags
 	r.Command.Flags().StringVar(
@@ -138,6 +161,7 @@ func EvalCommand(ctx context.Context, name string) *cobra.Command {
 type EvalFnRunner struct {
 	Command              *cobra.Command
 	Dest                 string
+	ResultsFormat        string
 	OutContent           bytes.Buffer
 	FromStdin            bool
 	Image                string
@@ -151,6 +175,8 @@ type EvalFnRunner struct {
 	Mounts               []string
 	Env                  []string
 	AsCurrentUser        bool
+	User                 string
+	ReadOnlyRootfs       bool
 	IncludeMetaResources bool
 	Ctx                  context.Context
 	Selector             kptfile.Selector
@@ -165,7 +191,8 @@ type EvalFnRunner struct {
 	excludeLabels       []string
 	excludeAnnotations  []string
 
-	runFns runfn.RunFns
+	runFns    runfn.RunFns
+	fnResults *fnresult.ResultList
 }
 
 func (r *EvalFnRunner) InitDefaults() {
@@ -173,8 +200,15 @@ func (r *EvalFnRunner) InitDefaults() {
 }
 
 func (r *EvalFnRunner) runE(c *cobra.Command, _ []string) error {
-	err := runner.HandleError(r.Ctx, r.runFns.Execute())
-	if err != nil {
+	err := r.runFns.Execute()
+	if r.ResultsFormat != "" {
+		// the result is written to stdout whether the function failed
+		// or not.
+		pkgPath, _, _ := pathutil.ResolveAbsAndRelPaths(r.runFns.Path)
+		err = output.Write(printer.FromContextOrDie(r.Ctx).OutStream(), output.Format(r.ResultsFormat),
+			"fn eval", pkgPath, output.FnResultsData(r.fnResults), err)
+	}
+	if err = runner.HandleError(r.Ctx, err); err != nil {
 		return err
 	}
 	if err = cmdutil.WriteFnOutput(r.Dest, r.OutContent.String(), r.FromStdin,
@@ -393,11 +427,11 @@ func (r *EvalFnRunner) getFunctionSpec() (*runtimeutil.FunctionSpec, []string, e
 		fn.Container.Image = r.Image
 	} else if r.Exec != "" {
 		// check the flags that doesn't make sense with exec function
-		// --mount, --as-current-user, --network and --env are
-		// only used with container functions
-		if r.AsCurrentUser || r.Network ||
+		// --mount, --as-current-user, --user, --read-only, --network and
+		// --env are only used with container functions
+		if r.AsCurrentUser || r.User != "" || r.ReadOnlyRootfs || r.Network ||
 			len(r.Mounts) != 0 || len(r.Env) != 0 {
-			return nil, nil, fmt.Errorf("--mount, --as-current-user, --network and --env can only be used with container functions")
+			return nil, nil, fmt.Errorf("--mount, --as-current-user, --user, --read-only, --network and --env can only be used with container functions")
 		}
 		s, err := shlex.Split(r.Exec)
 		if err != nil {
@@ -442,6 +476,14 @@ func (r *EvalFnRunner) validateOptionalFlags() error {
 			return fmt.Errorf("--type must be either `mutator` or `validator`")
 		}
 	}
+	if r.User != "" {
+		if r.AsCurrentUser {
+			return fmt.Errorf("--user and --as-current-user cannot be used together")
+		}
+		if !uidGIDPattern.MatchString(r.User) {
+			return fmt.Errorf("--user must be in the format UID[:GID], e.g. 1000:1000, got %q", r.User)
+		}
+	}
 	// ResultsDir stores the hydrated output in a structured format to result dir. If not specified, only make
 	// in-place changes.
 	if r.ResultsDir != "" {
@@ -459,6 +501,14 @@ func (r *EvalFnRunner) preRunE(c *cobra.Command, args []string) error {
 	if err := r.validateOptionalFlags(); err != nil {
 		return err
 	}
+	if r.ResultsFormat != "" {
+		if !output.IsFormat(r.ResultsFormat) {
+			return errors.Errorf("invalid --results-format %q: must be one of %s", r.ResultsFormat, output.SupportedFormatsLabel())
+		}
+		if r.Dest == cmdutil.Stdout || r.Dest == cmdutil.Unwrap {
+			return errors.Errorf("--results-format cannot be used with --output %s", r.Dest)
+		}
+	}
 	if r.Dest != "" && r.Dest != cmdutil.Stdout && r.Dest != cmdutil.Unwrap {
 		if err := cmdutil.CheckDirectoryNotPresent(r.Dest); err != nil {
 			return err
@@ -493,18 +543,21 @@ func (r *EvalFnRunner) preRunE(c *cobra.Command, args []string) error {
 	}
 
 	// set the output to stdout if in dry-run mode or no arguments are specified
-	var output io.Writer
+	var out io.Writer
 	var input io.Reader
 	r.OutContent = bytes.Buffer{}
 	if args[0] == "-" {
-		output = &r.OutContent
+		if r.ResultsFormat != "" {
+			return errors.Errorf("--results-format is not supported when reading resources from stdin")
+		}
+		out = &r.OutContent
 		input = c.InOrStdin()
 		r.FromStdin = true
 
 		// clear args as it indicates stdin and not path
 		args = []string{}
 	} else if r.Dest != "" {
-		output = &r.OutContent
+		out = &r.OutContent
 	}
 
 	// set the path if specified as an argument
@@ -539,27 +592,33 @@ func (r *EvalFnRunner) preRunE(c *cobra.Command, args []string) error {
 		}
 	}
 	r.parseSelectors()
+	if r.ResultsFormat != "" {
+		r.fnResults = fnresult.NewResultList()
+	}
 	r.runFns = runfn.RunFns{
-		Ctx:           r.Ctx,
-		Function:      fnSpec,
-		ExecArgs:      execArgs,
-		OriginalExec:  r.Exec,
-		Output:        output,
-		Input:         input,
-		Path:          path,
-		Network:       r.Network,
-		StorageMounts: storageMounts,
-		ResultsDir:    r.ResultsDir,
-		Env:           r.Env,
-		AsCurrentUser: r.AsCurrentUser,
-		FnConfig:      fnConfig,
-		FnConfigPath:  r.FnConfigPath,
+		Ctx:            r.Ctx,
+		Function:       fnSpec,
+		ExecArgs:       execArgs,
+		OriginalExec:   r.Exec,
+		Output:         out,
+		Input:          input,
+		Path:           path,
+		Network:        r.Network,
+		StorageMounts:  storageMounts,
+		ResultsDir:     r.ResultsDir,
+		Env:            r.Env,
+		AsCurrentUser:  r.AsCurrentUser,
+		User:           r.User,
+		ReadOnlyRootfs: r.ReadOnlyRootfs,
+		FnConfig:       fnConfig,
+		FnConfigPath:   r.FnConfigPath,
 		// fn eval should remove all files when all resources
 		// are deleted.
 		ContinueOnEmptyResult: true,
 		Selector:              r.Selector,
 		Exclusion:             r.Exclusion,
 		RunnerOptions:         r.RunnerOptions,
+		Results:               r.fnResults,
 	}
 
 	return nil
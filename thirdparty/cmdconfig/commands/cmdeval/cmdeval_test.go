@@ -279,6 +279,58 @@ kind: ConfigMap
 apiVersion: v1
 `,
 		},
+		{
+			name: "user and read-only rootfs",
+			args: []string{"eval", dir, "--user", "1000:1000", "--read-only", "--image", "foo:bar"},
+			path: dir,
+			expectedStruct: &runfn.RunFns{
+				Path:           dir,
+				User:           "1000:1000",
+				ReadOnlyRootfs: true,
+				RunnerOptions: fnruntime.RunnerOptions{
+					ImagePullPolicy: fnruntime.IfNotPresentPull,
+				},
+				Env:                   []string{},
+				ContinueOnEmptyResult: true,
+				Ctx:                   context.TODO(),
+			},
+			expectedFn: &runtimeutil.FunctionSpec{
+				Container: runtimeutil.ContainerSpec{
+					Image: "gcr.io/kpt-fn/foo:bar",
+				},
+			},
+		},
+		{
+			name: "invalid user",
+			args: []string{"eval", dir, "--user", "nobody", "--image", "foo:bar"},
+			err:  `--user must be in the format UID[:GID], e.g. 1000:1000, got "nobody"`,
+		},
+		{
+			name: "user and as current user",
+			args: []string{"eval", dir, "--user", "1000", "--as-current-user", "--image", "foo:bar"},
+			err:  "--user and --as-current-user cannot be used together",
+		},
+		{
+			name: "invalid results format",
+			args: []string{"eval", dir, "--results-format", "sarif", "--image", "foo:bar"},
+			err:  `invalid --results-format "sarif": must be one of json, yaml`,
+		},
+		{
+			name: "results format with output to stdout",
+			args: []string{"eval", dir, "--results-format", "json", "-o", "stdout", "--image", "foo:bar"},
+			err:  "--results-format cannot be used with --output stdout",
+		},
+		{
+			name:  "results format with stdin",
+			args:  []string{"eval", "-", "--results-format", "json", "--image", "foo:bar"},
+			input: os.Stdin,
+			err:   "--results-format is not supported when reading resources from stdin",
+		},
+		{
+			name: "user with exec",
+			args: []string{"eval", dir, "--user", "1000", "--exec", "./foo"},
+			err:  "--mount, --as-current-user, --user, --read-only, --network and --env can only be used with container functions",
+		},
 		{
 			name: "as current user",
 			args: []string{"eval", dir, "--as-current-user", "--image", "foo:bar"},
@@ -5,9 +5,15 @@ package cmdsink
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/fndocs"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/parse"
+	"github.com/GoogleContainerTools/kpt/internal/util/push"
+	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +23,7 @@ func GetSinkRunner(ctx context.Context, name string) *SinkRunner {
 		Ctx: ctx,
 	}
 	c := &cobra.Command{
-		Use:     "sink DIR [flags]",
+		Use:     "sink DIR | oci://IMAGE [flags]",
 		Short:   fndocs.SinkShort,
 		Long:    fndocs.SinkShort + "\n" + fndocs.SinkLong,
 		Args:    cobra.MinimumNArgs(1),
@@ -39,8 +45,30 @@ type SinkRunner struct {
 }
 
 func (r *SinkRunner) runE(c *cobra.Command, args []string) error {
+	if parse.IsOciArg(args[0]) {
+		return r.pushOci(c, strings.TrimPrefix(args[0], parse.OciScheme))
+	}
 	if err := cmdutil.CheckDirectoryNotPresent(args[0]); err != nil {
 		return err
 	}
 	return cmdutil.WriteToOutput(c.InOrStdin(), nil, args[0])
 }
+
+// pushOci writes the resources to a temporary directory and pushes it as a
+// package image to the registry.
+func (r *SinkRunner) pushOci(c *cobra.Command, image string) error {
+	dir, err := os.MkdirTemp("", "kpt-sink-")
+	if err != nil {
+		return fmt.Errorf("error creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := cmdutil.WriteToOutput(c.InOrStdin(), nil, dir); err != nil {
+		return err
+	}
+	p := push.NewOciPusher(image)
+	if err := p.Push(r.Ctx, dir); err != nil {
+		return err
+	}
+	printer.FromContextOrDie(r.Ctx).Printf("Pushed package to image %q.\n", p.LockedImage)
+	return nil
+}
@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/GoogleContainerTools/kpt/internal/testutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/fetch"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/GoogleContainerTools/kpt/pkg/printer/fake"
 	"github.com/stretchr/testify/assert"
@@ -222,3 +225,44 @@ items:
 		t.FailNow()
 	}
 }
+
+func TestSinkCommand_oci(t *testing.T) {
+	image := testutil.SetupOciRegistry(t) + "/blueprints/app:v1"
+
+	b := &bytes.Buffer{}
+	r := GetSinkRunner(fake.CtxWithPrinter(nil, b), "")
+	r.Command.SetIn(bytes.NewBufferString(`apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- kind: Deployment
+  metadata:
+    name: foo
+    annotations:
+      config.kubernetes.io/index: '0'
+      config.kubernetes.io/path: 'app/f1.yaml'
+  spec:
+    replicas: 1
+`))
+	r.Command.SetArgs([]string{"oci://" + image})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Contains(t, b.String(), "Pushed package to image")
+
+	c := fetch.NewOciCloner(image)
+	if !assert.NoError(t, c.ClonerUsingOciPull(fake.CtxWithDefaultPrinter())) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(c.AbsPath())
+	actual, err := os.ReadFile(filepath.Join(c.AbsPath(), "app", "f1.yaml"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `kind: Deployment
+metadata:
+  name: foo
+spec:
+  replicas: 1
+`, string(actual))
+	assert.Equal(t, c.LockedImage, strings.TrimSuffix(strings.TrimPrefix(b.String(), `Pushed package to image "`), "\".\n"))
+}
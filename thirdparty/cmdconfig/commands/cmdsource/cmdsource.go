@@ -6,12 +6,16 @@ package cmdsource
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/fndocs"
 	"github.com/GoogleContainerTools/kpt/internal/pkg"
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
 	"github.com/GoogleContainerTools/kpt/internal/util/cmdutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/fetch"
+	"github.com/GoogleContainerTools/kpt/internal/util/parse"
 	kptfile "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/GoogleContainerTools/kpt/thirdparty/cmdconfig/commands/runner"
@@ -29,7 +33,7 @@ func GetSourceRunner(ctx context.Context, name string) *SourceRunner {
 		Ctx:            ctx,
 	}
 	c := &cobra.Command{
-		Use:     "source [DIR] [flags]",
+		Use:     "source [DIR | oci://IMAGE] [flags]",
 		Short:   fndocs.SourceShort,
 		Long:    fndocs.SourceShort + "\n" + fndocs.SourceLong,
 		Example: fndocs.SourceExamples,
@@ -98,13 +102,24 @@ func (r *SourceRunner) runE(c *cobra.Command, args []string) error {
 
 	var inputs []kio.Reader
 	for _, a := range args {
-		pkgPath, err := filepath.Abs(a)
-		if err != nil {
-			return fmt.Errorf("cannot convert input path %q to absolute path: %w", a, err)
-		}
-		resolvedPath, err := argutil.ResolveSymlink(r.Ctx, pkgPath)
-		if err != nil {
-			return err
+		var resolvedPath string
+		if parse.IsOciArg(a) {
+			// read the package pulled from the registry
+			c := fetch.NewOciCloner(strings.TrimPrefix(a, parse.OciScheme))
+			if err := c.ClonerUsingOciPull(r.Ctx); err != nil {
+				return err
+			}
+			defer os.RemoveAll(c.AbsPath())
+			resolvedPath = c.AbsPath()
+		} else {
+			pkgPath, err := filepath.Abs(a)
+			if err != nil {
+				return fmt.Errorf("cannot convert input path %q to absolute path: %w", a, err)
+			}
+			resolvedPath, err = argutil.ResolveSymlink(r.Ctx, pkgPath)
+			if err != nil {
+				return err
+			}
 		}
 		inputs = append(inputs, kio.LocalPackageReader{
 			PackagePath:        resolvedPath,
@@ -626,3 +626,31 @@ items:
 	}
 	assert.Contains(t, stderr.String(), "please note that the symlinks within the package are ignored")
 }
+
+func TestSourceCommand_oci(t *testing.T) {
+	image := testutil.SetupOciRegistry(t) + "/blueprints/dataset1:v1"
+	testutil.PushOciPackage(t, image, testutil.Dataset1)
+
+	b := &bytes.Buffer{}
+	r := GetSourceRunner(fake.CtxWithPrinter(b, nil), "")
+	r.Command.SetArgs([]string{"oci://" + image})
+	if !assert.NoError(t, r.Command.Execute()) {
+		t.FailNow()
+	}
+	assert.Contains(t, b.String(), "kind: ResourceList")
+	assert.Contains(t, b.String(), "internal.config.kubernetes.io/path: 'mysql/mysql-configmap.resource.yaml'")
+	assert.Contains(t, b.String(), "internal.config.kubernetes.io/path: 'wordpress/wordpress-service.resource.yaml'")
+}
+
+func TestSourceCommand_ociMissingImage(t *testing.T) {
+	image := testutil.SetupOciRegistry(t) + "/blueprints/missing:v1"
+
+	r := GetSourceRunner(fake.CtxWithDefaultPrinter(), "")
+	r.Command.SetArgs([]string{"oci://" + image})
+	r.Command.SilenceUsage = true
+	r.Command.SilenceErrors = true
+	err := r.Command.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "error pulling image")
+	}
+}
@@ -5,16 +5,20 @@ package cmdtree
 
 import (
 	"context"
+	"path"
 	"path/filepath"
 
 	"github.com/GoogleContainerTools/kpt/internal/docs/generated/pkgdocs"
 	"github.com/GoogleContainerTools/kpt/internal/util/argutil"
+	"github.com/GoogleContainerTools/kpt/internal/util/output"
 	kptfilev1 "github.com/GoogleContainerTools/kpt/pkg/api/kptfile/v1"
 	"github.com/GoogleContainerTools/kpt/pkg/printer"
 	"github.com/GoogleContainerTools/kpt/thirdparty/cmdconfig/commands/runner"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 func GetTreeRunner(ctx context.Context, name string) *TreeRunner {
@@ -29,6 +33,8 @@ func GetTreeRunner(ctx context.Context, name string) *TreeRunner {
 		RunE:    r.runE,
 		Args:    cobra.MaximumNArgs(1),
 	}
+	c.Flags().StringVarP(&r.output, "output", "o", "",
+		"write the resources to stdout in a machine-readable format instead of a tree: "+output.SupportedFormatsLabel())
 
 	r.Command = c
 	return r
@@ -42,9 +48,14 @@ func NewCommand(ctx context.Context, name string) *cobra.Command {
 type TreeRunner struct {
 	Command *cobra.Command
 	Ctx     context.Context
+	output  string
 }
 
 func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(r.output)
+	if err != nil {
+		return err
+	}
 	var input kio.Reader
 	var root = "."
 	if len(args) == 0 {
@@ -53,6 +64,10 @@ func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
 	root = filepath.Clean(args[0])
 	resolvedPath, err := argutil.ResolveSymlink(r.Ctx, args[0])
 	if err != nil {
+		if format != output.None {
+			return output.Write(printer.FromContextOrDie(r.Ctx).OutStream(), format,
+				"pkg tree", root, nil, err)
+		}
 		return err
 	}
 	input = kio.LocalPackageReader{
@@ -65,6 +80,21 @@ func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
 		IncludeLocalConfig: true,
 	}}
 
+	if format != output.None {
+		w := &resourceEntryWriter{}
+		err := kio.Pipeline{
+			Inputs:  []kio.Reader{input},
+			Filters: fltrs,
+			Outputs: []kio.Writer{w},
+		}.Execute()
+		pkgPath, absErr := filepath.Abs(resolvedPath)
+		if absErr != nil {
+			pkgPath = resolvedPath
+		}
+		return output.Write(printer.FromContextOrDie(r.Ctx).OutStream(), format,
+			"pkg tree", pkgPath, w.packageEntries(), err)
+	}
+
 	return runner.HandleError(r.Ctx, kio.Pipeline{
 		Inputs:  []kio.Reader{input},
 		Filters: fltrs,
@@ -78,3 +108,58 @@ func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
 func (r *TreeRunner) getMatchFilesGlob() []string {
 	return append([]string{kptfilev1.KptFileName}, kio.DefaultMatch...)
 }
+
+// ResourceEntry is an entry of the machine-readable output of `pkg tree`.
+type ResourceEntry struct {
+	// Package is the path of the package or subpackage holding the
+	// resource, relative to the package. It is "." for the package itself.
+	Package string `json:"package"`
+	// Path is the path of the file holding the resource, relative to the
+	// package.
+	Path       string `json:"path"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// resourceEntryWriter collects the resources of a package as entries.
+type resourceEntryWriter struct {
+	entries []ResourceEntry
+}
+
+func (w *resourceEntryWriter) Write(nodes []*yaml.RNode) error {
+	for _, node := range nodes {
+		path, _, err := kioutil.GetFileAnnotations(node)
+		if err != nil {
+			return err
+		}
+		w.entries = append(w.entries, ResourceEntry{
+			Path:       filepath.ToSlash(path),
+			APIVersion: node.GetApiVersion(),
+			Kind:       node.GetKind(),
+			Name:       node.GetName(),
+			Namespace:  node.GetNamespace(),
+		})
+	}
+	return nil
+}
+
+// packageEntries returns the entries with the subpackages holding their
+// resources, i.e. the closest directory with a Kptfile.
+func (w *resourceEntryWriter) packageEntries() []ResourceEntry {
+	pkgDirs := map[string]bool{}
+	for _, e := range w.entries {
+		if e.Kind == kptfilev1.KptFileKind && path.Base(e.Path) == kptfilev1.KptFileName {
+			pkgDirs[path.Dir(e.Path)] = true
+		}
+	}
+	for i := range w.entries {
+		dir := path.Dir(w.entries[i].Path)
+		for dir != "." && !pkgDirs[dir] {
+			dir = path.Dir(dir)
+		}
+		w.entries[i].Package = dir
+	}
+	return w.entries
+}
@@ -480,3 +480,79 @@ spec:
 	}
 	assert.Contains(t, stderr.String(), "please note that the symlinks within the package are ignored")
 }
+
+func TestTreeCommand_output(t *testing.T) {
+	d := t.TempDir()
+	err := os.WriteFile(filepath.Join(d, "f1.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, os.MkdirAll(filepath.Join(d, "sub", "config"), 0700)) {
+		return
+	}
+	err = os.WriteFile(filepath.Join(d, "sub", "Kptfile"), []byte(`apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: sub
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = os.WriteFile(filepath.Join(d, "sub", "config", "cm.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bar
+`), 0600)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	out := &bytes.Buffer{}
+	r := GetTreeRunner(fake.CtxWithPrinter(out, &bytes.Buffer{}), "")
+	r.Command.SetArgs([]string{d, "--output", "yaml"})
+	if !assert.NoError(t, r.Command.Execute()) {
+		return
+	}
+	assert.Equal(t, fmt.Sprintf(`apiVersion: kpt.dev/v1
+command: pkg tree
+data:
+- apiVersion: apps/v1
+  kind: Deployment
+  name: foo
+  namespace: prod
+  package: .
+  path: f1.yaml
+- apiVersion: v1
+  kind: Service
+  name: foo
+  package: .
+  path: f1.yaml
+- apiVersion: kpt.dev/v1
+  kind: Kptfile
+  name: sub
+  package: sub
+  path: sub/Kptfile
+- apiVersion: v1
+  kind: ConfigMap
+  name: bar
+  package: sub
+  path: sub/config/cm.yaml
+exitCode: 0
+kind: CommandResult
+package: %s
+`, d), out.String())
+
+	r = GetTreeRunner(fake.CtxWithPrinter(out, &bytes.Buffer{}), "")
+	r.Command.SetArgs([]string{d, "--output", "xml"})
+	assert.EqualError(t, r.Command.Execute(), `invalid output "xml": supported outputs are: json, yaml`)
+}
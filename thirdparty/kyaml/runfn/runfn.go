@@ -63,6 +63,10 @@ type RunFns struct {
 	// ResultsDir is where to write each functions results
 	ResultsDir string
 
+	// Results, if set, collects the results of the functions. Otherwise
+	// the results are only written to ResultsDir.
+	Results *fnresult.ResultList
+
 	fnResults *fnresult.ResultList
 
 	// functionFilterProvider provides a filter to perform the function.
@@ -74,6 +78,14 @@ type RunFns struct {
 	// the uid and gid that run the command
 	AsCurrentUser bool
 
+	// User is the uid and optional gid, in the format uid[:gid], to run
+	// container functions with. It cannot be used with AsCurrentUser.
+	User string
+
+	// ReadOnlyRootfs runs container functions with a read-only root
+	// filesystem.
+	ReadOnlyRootfs bool
+
 	// Env contains environment variables that will be exported to container
 	Env []string
 
@@ -287,7 +299,10 @@ func (r *RunFns) init() error {
 		r.uniquePath = types.UniquePath(absPath)
 	}
 
-	r.fnResults = fnresult.NewResultList()
+	r.fnResults = r.Results
+	if r.fnResults == nil {
+		r.fnResults = fnresult.NewResultList()
+	}
 
 	// functionFilterProvider set the filter provider
 	if r.functionFilterProvider == nil {
@@ -311,7 +326,10 @@ type currentUserFunc func() (*user.User, error)
 
 // getUIDGID will return "nobody" if asCurrentUser is false. Otherwise
 // return "uid:gid" according to the return from currentUser function.
-func getUIDGID(asCurrentUser bool, currentUser currentUserFunc) (string, error) {
+func getUIDGID(asCurrentUser bool, user string, currentUser currentUserFunc) (string, error) {
+	if user != "" {
+		return user, nil
+	}
 	if !asCurrentUser {
 		return "nobody", nil
 	}
@@ -361,7 +379,9 @@ func (r *RunFns) defaultFnFilterProvider(spec runtimeutil.FunctionSpec, fnConfig
 		// If AllowWasm is true, we try to use the image field as a wasm image.
 		// TODO: we can be smarter here. If the image doesn't support wasm/js platform,
 		// it should fallback to run it as container fn.
-		if r.RunnerOptions.AllowWasm {
+		if builtin, found := fnruntime.LookupBuiltin(resolvedImage, fnResult); r.RunnerOptions.PreferBuiltin && found {
+			fltr.Run = builtin
+		} else if r.RunnerOptions.AllowWasm {
 			wFn, err := fnruntime.NewWasmFn(fnruntime.NewOciLoader(filepath.Join(os.TempDir(), "kpt-fn-wasm"), resolvedImage))
 			if err != nil {
 				return nil, err
@@ -369,14 +389,16 @@ func (r *RunFns) defaultFnFilterProvider(spec runtimeutil.FunctionSpec, fnConfig
 			fltr.Run = wFn.Run
 		} else {
 			// TODO: Add a test for this behavior
-			uidgid, err := getUIDGID(r.AsCurrentUser, currentUser)
+			uidgid, err := getUIDGID(r.AsCurrentUser, r.User, currentUser)
 			if err != nil {
 				return nil, err
 			}
 			c := &fnruntime.ContainerFn{
 				Image:           resolvedImage,
 				ImagePullPolicy: r.RunnerOptions.ImagePullPolicy,
+				Runtime:         r.RunnerOptions.Runtime,
 				UIDGID:          uidgid,
+				ReadOnlyRootfs:  r.ReadOnlyRootfs,
 				StorageMounts:   r.StorageMounts,
 				Env:             spec.Container.Env,
 				FnResult:        fnResult,